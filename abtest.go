@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// BuiltInPrompt names the built-in prompt in a -prompts list
+const BuiltInPrompt = "built-in"
+
+// ABTest plays the same model with each of several prompts against the same
+// opponent under the same conditions: round by round, every prompt plays
+// game N of the round with the same side moving first, so drift in the
+// backend over the run affects them all alike. The model plays X and the
+// opponent O, with the first move alternating between rounds.
+type ABTest struct {
+	Prompts  []string // the prompts compared, the first being the baseline
+	Variants []Agent  // the model with each prompt, named apart
+	Opponent Agent
+	Games    int // games each prompt plays
+	// BeforeGame, if set, is called as every game starts, e.g. to announce it
+	BeforeGame func(agents map[string]Agent)
+	// AfterGame, if set, is called with every finished game, e.g. to
+	// record statistics
+	AfterGame func(result GameResult, agents map[string]Agent)
+	// Out is where games print their moves, os.Stdout if nil
+	Out io.Writer
+}
+
+// Run plays the test's games, stopping early if stop says so after a game
+func (t *ABTest) Run(newGame func() Game, opening Opening, clock *Clock, maxRetries int, debug bool, stats *GameStats, stop func() bool) {
+	out := t.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	for round := 1; round <= t.Games; round++ {
+		fmt.Fprintf(out, "\n##### Round %d of %d #####\n", round, t.Games)
+		for i, variant := range t.Variants {
+			agents := map[string]Agent{PlayerX: variant, PlayerO: t.Opponent}
+			fmt.Fprintf(out, "\n--- Prompt %s ---\n", t.Prompts[i])
+			if t.BeforeGame != nil {
+				t.BeforeGame(agents)
+			}
+			// The round number, not a running count, picks who moves first
+			result := PlayGame(out, newGame(), agents, opening, clock, maxRetries, debug, round, stats)
+			if t.AfterGame != nil {
+				t.AfterGame(result, agents)
+			}
+			if stop() {
+				return
+			}
+		}
+	}
+}
+
+// Report prints each prompt's win, illegal-move, and blunder rates with 95%
+// confidence intervals, and how each differs from the baseline, the first
+func (t *ABTest) Report(w io.Writer, stats *GameStats) {
+	fmt.Fprintf(w, "  %-24s %6s %22s %22s %22s\n", "Prompt", "Games", "Win % [95% CI]", "Illegal % [95% CI]", "Blunder % [95% CI]")
+	for i, variant := range t.Variants {
+		m := stats.Model(variant.Name())
+		fmt.Fprintf(w, "  %-24s %6d %22s %22s %22s\n", shortName(t.Prompts[i], 24), m.Games,
+			describeRate(m.Wins, m.Games), describeRate(m.IllegalMoves, m.Answers), describeRate(m.Grades[Blunder], m.GradedMoves))
+	}
+	baseline := stats.Model(t.Variants[0].Name())
+	for i, variant := range t.Variants[1:] {
+		m := stats.Model(variant.Name())
+		fmt.Fprintf(w, "  %s vs %s:\n", t.Prompts[i+1], t.Prompts[0])
+		fmt.Fprintf(w, "    wins:          %s\n", describeDifference(m.Wins, m.Games, baseline.Wins, baseline.Games))
+		fmt.Fprintf(w, "    illegal moves: %s\n", describeDifference(m.IllegalMoves, m.Answers, baseline.IllegalMoves, baseline.Answers))
+		if m.GradedMoves > 0 && baseline.GradedMoves > 0 {
+			fmt.Fprintf(w, "    blunders:      %s\n", describeDifference(m.Grades[Blunder], m.GradedMoves, baseline.Grades[Blunder], baseline.GradedMoves))
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
+
+// describeDifference shows how much one rate differs from another, in
+// percentage points, and whether that could be chance
+func describeDifference(count, total, baseCount, baseTotal int) string {
+	diff := (rate(count, total) - rate(baseCount, baseTotal)) * 100
+	return fmt.Sprintf("%+.1f points (%s)", diff, describeP(CompareRates(rate(count, total), total, rate(baseCount, baseTotal), baseTotal)))
+}
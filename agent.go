@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Agent chooses moves for one side of a game
+type Agent interface {
+	// Name identifies the agent in console output and statistics
+	Name() string
+	// ChooseMove returns the move the agent wants to play; it must not modify game
+	ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error)
+}
+
+// MoveResult describes a move chosen by an agent
+type MoveResult struct {
+	Position   int
+	Prompt     string        // prompt sent to the LLM, if any
+	Response   string        // raw LLM response, if any
+	Duration   time.Duration // time spent waiting on the LLM, if any
+	Transcript string        // extra exchanges shown in debug output, if any
+	Reasoning  string        // the model's reasoning behind its move, with -cot or -json
+	Override   string        // why an engine replaced the LLM's choice, if it did
+	Usage      Usage         // tokens used by the LLM calls behind this move, if reported
+}
+
+// LLMAgent asks a model on an LLM backend for its moves
+type LLMAgent struct {
+	Backend     Backend
+	Model       string
+	System      string
+	Persona     string // the built-in persona at the start of System, if any
+	Variant     string // what sets this player apart in an A/B test, e.g. its prompt
+	Temperature float64
+	TopP        float64
+	TopK        int
+	Seed        int
+	MaxTokens   int
+	Chat        bool      // send the move history as user/assistant turns instead of inside the prompt
+	Stream      io.Writer // when set, responses are streamed and shown here as they arrive
+	Tools       bool      // offer a make_move tool and read the move from the tool call
+	JSON        bool      // demand a {"position", "reasoning"} JSON object and parse it
+	Grammar     bool      // constrain decoding to the available positions with a GBNF grammar
+	LogitBias   bool      // bias the digit tokens toward the available positions
+	Reason      bool      // ask for step-by-step reasoning and read the move from its last line
+	Retry       RetryPolicy
+	Timeout     time.Duration   // limit for each HTTP request; 0 for none
+	MoveTimeout time.Duration   // limit for one LLM call including HTTP retries; 0 for none
+	Clock       *Clock          // when set, the time left is shown in prompts
+	Template    *PromptTemplate // when set, builds the prompt for plain text answers
+	// Conversations, when set, keep a chat with each game instead of
+	// prompting afresh for every move
+	Conversations *Conversations
+	Examples      *FewShot // when set, solved examples shown before the model's own position
+}
+
+// Name returns the model name, with its persona, A/B test variant, and
+// whether it keeps conversations, so they're kept apart in the statistics
+// from the same model prompted plainly
+func (a *LLMAgent) Name() string {
+	var styles []string
+	if a.Persona != "" {
+		styles = append(styles, a.Persona)
+	}
+	if a.Variant != "" {
+		styles = append(styles, a.Variant)
+	}
+	if a.Conversations != nil {
+		styles = append(styles, "conversation")
+	}
+	if len(styles) == 0 {
+		return a.Model
+	}
+	return fmt.Sprintf("%s (%s)", a.Model, strings.Join(styles, ", "))
+}
+
+// ChooseMove prompts the model and parses a position from its response
+func (a *LLMAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	var history []ChatMessage
+	promptHistory := moveHistory
+	if a.Chat {
+		history = BuildChatHistory(game, player, moveHistory)
+		promptHistory = nil
+	}
+
+	// Games where players choose their mark ask for it alongside the position
+	marks, choosesMark := ChoosesMark(game)
+	prompt := game.Prompt(player, promptHistory)
+	if a.Template != nil {
+		var err error
+		if prompt, err = a.Template.Render(game, player, promptHistory); err != nil {
+			return MoveResult{Position: -1}, err
+		}
+	}
+	if a.Conversations != nil {
+		history, prompt = a.Conversations.Next(game, player, moveHistory)
+	}
+	req := a.newRequest(history, prompt)
+	if a.Conversations != nil {
+		req.System = strings.TrimSpace(a.System + "\n\n" + game.Rules(player))
+	}
+	if a.Tools {
+		req.Prompt = BuildToolPrompt(game, player, promptHistory)
+		tool := MakeMoveTool(LegalPositions(game))
+		if choosesMark {
+			AddMarkProperty(tool.Parameters, marks.Marks())
+		}
+		req.Tools = []ToolDefinition{tool}
+	} else if a.JSON {
+		req.Prompt = BuildJSONPrompt(game, player, promptHistory)
+		req.Format = MoveSchema(LegalPositions(game))
+		if choosesMark {
+			AddMarkProperty(req.Format, marks.Marks())
+		}
+	} else if a.Reason {
+		req.Prompt = BuildReasoningPrompt(game, player, promptHistory)
+	} else if a.Grammar {
+		req.Grammar = MoveGrammar(game.Legal())
+	} else if a.LogitBias {
+		req.LogitBias = MoveLogitBias(game.Legal())
+	}
+	if a.Examples != nil {
+		// Chats get the examples as earlier turns, and prompts in their text
+		if a.Chat || a.Conversations != nil {
+			req.Messages = append(append([]ChatMessage(nil), a.Examples.Turns...), req.Messages...)
+		} else {
+			req.Prompt = a.Examples.Text + req.Prompt
+		}
+	}
+	req.Prompt += a.clockNote(player)
+	result := MoveResult{Position: -1, Prompt: req.Prompt}
+
+	resp, duration, err := a.send(req)
+	if err != nil {
+		return result, fmt.Errorf("error calling LLM: %w", err)
+	}
+	if a.Conversations != nil {
+		a.Conversations.Answer(game, player, resp.Text)
+	}
+	result.Response = resp.Text
+	result.Duration = duration
+	result.Usage = resp.Usage
+
+	if position, called, err := PositionFromToolCalls(resp.ToolCalls); called {
+		result.Response = strings.TrimSpace(fmt.Sprintf("%s make_move(position=%d)", strings.TrimSpace(resp.Text), position))
+		if err != nil {
+			return result, fmt.Errorf("error parsing move: %w", err)
+		}
+		if choosesMark {
+			mark := MarkFromToolCalls(resp.ToolCalls)
+			if mark == "" {
+				return result, fmt.Errorf("error parsing move: make_move call has no mark")
+			}
+			position = marks.EncodeMove(position, mark)
+		}
+		result.Position = position
+		return result, nil
+	}
+
+	if a.JSON {
+		position, reasoning, err := ParseJSONMove(resp.Text)
+		if reasoning != "" {
+			result.Reasoning = strings.TrimSpace(reasoning)
+			result.Transcript = "--- Reasoning ---\n" + result.Reasoning + "\n"
+		}
+		if err != nil {
+			return result, fmt.Errorf("error parsing move: %w", err)
+		}
+		if choosesMark {
+			mark := ParseJSONMark(resp.Text)
+			if mark == "" {
+				return result, fmt.Errorf("error parsing move: JSON move has no mark")
+			}
+			position = marks.EncodeMove(position, mark)
+		}
+		result.Position = position
+		return result, nil
+	}
+
+	if a.Reason {
+		position, err := ParseFinalMove(game, resp.Text)
+		if result.Reasoning = finalMoveReasoning(resp.Text); result.Reasoning != "" {
+			result.Transcript = "--- Reasoning ---\n" + result.Reasoning + "\n"
+		}
+		if err != nil {
+			return result, fmt.Errorf("error parsing move: %w", err)
+		}
+		result.Position = position
+		return result, nil
+	}
+
+	// Models without tool support answer in text, so fall back to parsing it
+	position, err := game.ParseMove(resp.Text)
+	if err != nil {
+		return result, fmt.Errorf("error parsing move: %w", err)
+	}
+	result.Position = position
+	return result, nil
+}
+
+// Complete sends a prompt to the model and returns its raw response
+func (a *LLMAgent) Complete(prompt string) (LLMResponse, time.Duration, error) {
+	return a.send(a.newRequest(nil, prompt))
+}
+
+// clockNote tells player their time left, when playing on a clock
+func (a *LLMAgent) clockNote(player string) string {
+	if a.Clock == nil {
+		return ""
+	}
+	return a.Clock.Note(player)
+}
+
+// newRequest builds a request with the agent's model and generation settings
+func (a *LLMAgent) newRequest(history []ChatMessage, prompt string) LLMRequest {
+	return LLMRequest{
+		Model:       a.Model,
+		System:      a.System,
+		Messages:    history,
+		Prompt:      prompt,
+		Temperature: a.Temperature,
+		TopP:        a.TopP,
+		TopK:        a.TopK,
+		Seed:        a.Seed,
+		MaxTokens:   a.MaxTokens,
+	}
+}
+
+// send sends a request to the backend, retrying transport and HTTP failures
+// with backoff. The duration is that of the successful attempt alone, so
+// waiting out an outage doesn't skew response times.
+func (a *LLMAgent) send(req LLMRequest) (LLMResponse, time.Duration, error) {
+	ctx := context.Background()
+	if a.MoveTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.MoveTimeout)
+		defer cancel()
+	}
+
+	for retry := 1; ; retry++ {
+		resp, duration, err := a.sendOnce(ctx, req)
+		if err == nil || ctx.Err() != nil || !IsRetryable(err) || retry > a.Retry.MaxRetries {
+			if ctx.Err() != nil {
+				return resp, duration, fmt.Errorf("no response within the %s move deadline: %w", a.MoveTimeout, ctx.Err())
+			}
+			return resp, duration, err
+		}
+		delay := a.Retry.Delay(retry, err)
+		slog.Info(fmt.Sprintf("Backend request failed; retrying in %.1fs (%d/%d)", delay.Seconds(), retry, a.Retry.MaxRetries), "model", a.Model, "err", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// sendOnce sends a request to the backend, streaming the response if configured
+func (a *LLMAgent) sendOnce(ctx context.Context, req LLMRequest) (LLMResponse, time.Duration, error) {
+	if a.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.Timeout)
+		defer cancel()
+	}
+
+	// Tool calls arrive as structured data, so there is nothing useful to stream
+	if a.Stream == nil || len(req.Tools) > 0 {
+		return CallLLM(ctx, a.Backend, req)
+	}
+
+	streamed := false
+	fmt.Fprint(a.Stream, "LLM output: ")
+	req.OnToken = func(text string) {
+		streamed = true
+		fmt.Fprint(a.Stream, text)
+	}
+	resp, duration, err := CallLLM(ctx, a.Backend, req)
+	if !streamed {
+		// The backend doesn't stream, so show the whole response at once
+		fmt.Fprint(a.Stream, strings.TrimSpace(resp.Text))
+	}
+	fmt.Fprintln(a.Stream)
+	return resp, duration, err
+}
+
+// Warmup sends a trivial request so the model is loaded before any timed move,
+// once per endpoint when requests are balanced over several. It returns the
+// slowest warmup time.
+func (a *LLMAgent) Warmup() (time.Duration, error) {
+	var slowest time.Duration
+	for i := 0; i < endpointCount(a.Backend); i++ {
+		_, duration, err := a.send(LLMRequest{
+			Model:       a.Model,
+			System:      a.System,
+			Prompt:      "Reply with OK.",
+			Temperature: a.Temperature,
+			MaxTokens:   1,
+		})
+		if err != nil {
+			return slowest, err
+		}
+		slowest = max(slowest, duration)
+	}
+	return slowest, nil
+}
+
+// SetOption sets a generation option by its flag name, e.g. "temperature" or "top-k"
+func (a *LLMAgent) SetOption(key, value string) error {
+	var err error
+	switch strings.ReplaceAll(key, "_", "-") {
+	case "temperature":
+		a.Temperature, err = strconv.ParseFloat(value, 64)
+	case "top-p":
+		a.TopP, err = strconv.ParseFloat(value, 64)
+	case "top-k":
+		a.TopK, err = strconv.Atoi(value)
+	case "seed":
+		a.Seed, err = strconv.Atoi(value)
+	case "max-tokens":
+		a.MaxTokens, err = strconv.Atoi(value)
+	default:
+		return fmt.Errorf("unknown option %q: must be temperature, top-p, top-k, seed, or max-tokens", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for option %s", value, key)
+	}
+	return nil
+}
+
+// AgentOptions configures the LLM agent modes and built-in opponents
+type AgentOptions struct {
+	Votes           int
+	MCTSSimulations int
+	MinimaxDepth    int // moves the minimax opponent searches ahead; 0 for the game's default
+}
+
+// NewLLMPlayer wraps an LLM agent according to the -agent flag
+func NewLLMPlayer(mode string, llm *LLMAgent, opts AgentOptions) (Agent, error) {
+	switch mode {
+	case "llm":
+		return llm, nil
+	case "ensemble":
+		return &EnsembleAgent{LLM: llm, Votes: opts.Votes}, nil
+	case "reflect":
+		return &ReflectAgent{LLM: llm}, nil
+	case "hybrid":
+		return &HybridAgent{LLM: llm}, nil
+	}
+	return nil, fmt.Errorf("unknown agent %q: must be llm, ensemble, reflect, or hybrid", mode)
+}
+
+// NewOpponent creates the agent named by the -opponent flag; "llm" reuses the given LLM agent
+func NewOpponent(name string, llm Agent, opts AgentOptions) (Agent, error) {
+	switch name {
+	case "llm":
+		return llm, nil
+	case "minimax":
+		return &MinimaxAgent{Depth: opts.MinimaxDepth}, nil
+	case "random":
+		return &RandomAgent{}, nil
+	case "mcts":
+		return &MCTSAgent{Simulations: opts.MCTSSimulations}, nil
+	case "heuristic":
+		return &HeuristicAgent{}, nil
+	}
+	return nil, fmt.Errorf("unknown opponent %q: must be llm, minimax, mcts, heuristic, or random", name)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	anthropicDefaultURL       = "https://api.anthropic.com"
+	anthropicVersion          = "2023-06-01"
+	anthropicDefaultMaxTokens = 1024
+)
+
+func init() {
+	RegisterProvider(&BasicProvider{
+		ID:   "anthropic",
+		Info: "Anthropic Messages API (key from ANTHROPIC_API_KEY)",
+		URL:  anthropicDefaultURL,
+		Factory: func(cfg BackendConfig) (Backend, error) {
+			return newAnthropicBackend(cfg), nil
+		},
+	})
+}
+
+type AnthropicRequest struct {
+	Model       string          `json:"model"`
+	MaxTokens   int             `json:"max_tokens"`
+	System      string          `json:"system,omitempty"`
+	Messages    []ChatMessage   `json:"messages"`
+	Temperature float64         `json:"temperature"`
+	TopP        float64         `json:"top_p,omitempty"`
+	TopK        int             `json:"top_k,omitempty"`
+	Tools       []anthropicTool `json:"tools,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// AnthropicStreamEvent is the data of one server-sent event from a streamed response
+type AnthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"` // message_start events
+	Usage anthropicUsage `json:"usage"` // message_delta events
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (u anthropicUsage) usage() Usage {
+	return Usage{PromptTokens: u.InputTokens, CompletionTokens: u.OutputTokens}
+}
+
+type AnthropicResponse struct {
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Name  string          `json:"name"`  // tool_use blocks
+		Input json.RawMessage `json:"input"` // tool_use blocks
+	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+// AnthropicBackend talks to the Anthropic Messages API
+type AnthropicBackend struct {
+	URL     string
+	APIKey  string
+	Headers map[string]string
+}
+
+// Generate sends the prompt, after any earlier turns, as a user message
+func (a *AnthropicBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	if a.APIKey == "" {
+		return LLMResponse{}, fmt.Errorf("anthropic backend requires an API key (set ANTHROPIC_API_KEY)")
+	}
+
+	// The Messages API requires max_tokens on every request
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = anthropicDefaultMaxTokens
+	}
+	reqBody := AnthropicRequest{
+		Model:       req.Model,
+		MaxTokens:   maxTokens,
+		System:      req.System,
+		Messages:    appendChatMessage(append([]ChatMessage(nil), req.Messages...), ChatMessage{Role: "user", Content: req.Prompt}),
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		TopK:        req.TopK,
+		Stream:      req.OnToken != nil,
+	}
+	for _, tool := range req.Tools {
+		reqBody.Tools = append(reqBody.Tools, anthropicTool{Name: tool.Name, Description: tool.Description, InputSchema: tool.Parameters})
+	}
+	headers := mergeHeaders(map[string]string{
+		"x-api-key":         a.APIKey,
+		"anthropic-version": anthropicVersion,
+	}, a.Headers)
+
+	url := strings.TrimSuffix(a.URL, "/") + "/v1/messages"
+	if reqBody.Stream {
+		var usage Usage
+		text, err := streamText(ctx, url, headers, reqBody, true, req.OnToken, func(data []byte) (string, error) {
+			var event AnthropicStreamEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", err
+			}
+			switch event.Type {
+			case "error":
+				return "", errors.New(event.Error.Message)
+			case "message_start":
+				usage.Add(event.Message.Usage.usage())
+			case "message_delta":
+				// The output count here is cumulative, not a delta
+				usage.CompletionTokens = event.Usage.OutputTokens
+			case "content_block_delta":
+				if event.Delta.Type == "text_delta" {
+					return event.Delta.Text, nil
+				}
+			}
+			return "", nil
+		})
+		return LLMResponse{Text: text, Usage: usage}, err
+	}
+
+	var anthropicResp AnthropicResponse
+	if err := postJSON(ctx, url, headers, reqBody, &anthropicResp); err != nil {
+		return LLMResponse{}, err
+	}
+
+	resp := LLMResponse{Usage: anthropicResp.Usage.usage()}
+	for _, block := range anthropicResp.Content {
+		switch block.Type {
+		case "text":
+			resp.Text += block.Text
+		case "tool_use":
+			var call ToolCall
+			call.Function.Name = block.Name
+			call.Function.Arguments = ToolArguments(block.Input)
+			resp.ToolCalls = append(resp.ToolCalls, call)
+		}
+	}
+	return resp, nil
+}
+
+// newAnthropicBackend falls back to the API key from the environment
+func newAnthropicBackend(cfg BackendConfig) *AnthropicBackend {
+	return &AnthropicBackend{URL: cfg.URL, APIKey: orDefault(cfg.APIKey, os.Getenv("ANTHROPIC_API_KEY")), Headers: cfg.Headers}
+}
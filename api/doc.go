@@ -0,0 +1,5 @@
+// Package api holds the protobuf definitions of the llama-tac-toe gRPC API
+// and the Go code generated from them.
+package api
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative llamatactoe.proto
@@ -0,0 +1,932 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: llamatactoe.proto
+
+// The llama-tac-toe API, served by "llama-tac-toe grpc", for services that
+// orchestrate matches between models programmatically
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateMatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Who plays X and O: a model on the server's backend, or one of the
+	// engines minimax, mcts, heuristic, or random
+	PlayerX string `protobuf:"bytes,1,opt,name=player_x,json=playerX,proto3" json:"player_x,omitempty"`
+	PlayerO string `protobuf:"bytes,2,opt,name=player_o,json=playerO,proto3" json:"player_o,omitempty"`
+	// Games to play, alternating who moves first; 1 if unset
+	Games         int32 `protobuf:"varint,3,opt,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMatchRequest) Reset() {
+	*x = CreateMatchRequest{}
+	mi := &file_llamatactoe_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMatchRequest) ProtoMessage() {}
+
+func (x *CreateMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMatchRequest.ProtoReflect.Descriptor instead.
+func (*CreateMatchRequest) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateMatchRequest) GetPlayerX() string {
+	if x != nil {
+		return x.PlayerX
+	}
+	return ""
+}
+
+func (x *CreateMatchRequest) GetPlayerO() string {
+	if x != nil {
+		return x.PlayerO
+	}
+	return ""
+}
+
+func (x *CreateMatchRequest) GetGames() int32 {
+	if x != nil {
+		return x.Games
+	}
+	return 0
+}
+
+type Match struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Game          string                 `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"` // the server's game, e.g. "Tic-Tac-Toe"
+	PlayerX       string                 `protobuf:"bytes,3,opt,name=player_x,json=playerX,proto3" json:"player_x,omitempty"`
+	PlayerO       string                 `protobuf:"bytes,4,opt,name=player_o,json=playerO,proto3" json:"player_o,omitempty"`
+	Games         int32                  `protobuf:"varint,5,opt,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	mi := &file_llamatactoe_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
+
+func (x *Match) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Match) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Match) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *Match) GetPlayerX() string {
+	if x != nil {
+		return x.PlayerX
+	}
+	return ""
+}
+
+func (x *Match) GetPlayerO() string {
+	if x != nil {
+		return x.PlayerO
+	}
+	return ""
+}
+
+func (x *Match) GetGames() int32 {
+	if x != nil {
+		return x.Games
+	}
+	return 0
+}
+
+type StreamEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_llamatactoe_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamEventsRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+type Event struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	MatchId string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_GameStarted
+	//	*Event_MovePlayed
+	//	*Event_GameFinished
+	//	*Event_MatchFinished
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_llamatactoe_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Event) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetGameStarted() *GameStarted {
+	if x != nil {
+		if x, ok := x.Event.(*Event_GameStarted); ok {
+			return x.GameStarted
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetMovePlayed() *MovePlayed {
+	if x != nil {
+		if x, ok := x.Event.(*Event_MovePlayed); ok {
+			return x.MovePlayed
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetGameFinished() *GameFinished {
+	if x != nil {
+		if x, ok := x.Event.(*Event_GameFinished); ok {
+			return x.GameFinished
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetMatchFinished() *MatchFinished {
+	if x != nil {
+		if x, ok := x.Event.(*Event_MatchFinished); ok {
+			return x.MatchFinished
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_GameStarted struct {
+	GameStarted *GameStarted `protobuf:"bytes,2,opt,name=game_started,json=gameStarted,proto3,oneof"`
+}
+
+type Event_MovePlayed struct {
+	MovePlayed *MovePlayed `protobuf:"bytes,3,opt,name=move_played,json=movePlayed,proto3,oneof"`
+}
+
+type Event_GameFinished struct {
+	GameFinished *GameFinished `protobuf:"bytes,4,opt,name=game_finished,json=gameFinished,proto3,oneof"`
+}
+
+type Event_MatchFinished struct {
+	MatchFinished *MatchFinished `protobuf:"bytes,5,opt,name=match_finished,json=matchFinished,proto3,oneof"`
+}
+
+func (*Event_GameStarted) isEvent_Event() {}
+
+func (*Event_MovePlayed) isEvent_Event() {}
+
+func (*Event_GameFinished) isEvent_Event() {}
+
+func (*Event_MatchFinished) isEvent_Event() {}
+
+type GameStarted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Game          int32                  `protobuf:"varint,1,opt,name=game,proto3" json:"game,omitempty"` // numbered from 1 within the match
+	Board         string                 `protobuf:"bytes,2,opt,name=board,proto3" json:"board,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameStarted) Reset() {
+	*x = GameStarted{}
+	mi := &file_llamatactoe_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameStarted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameStarted) ProtoMessage() {}
+
+func (x *GameStarted) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameStarted.ProtoReflect.Descriptor instead.
+func (*GameStarted) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GameStarted) GetGame() int32 {
+	if x != nil {
+		return x.Game
+	}
+	return 0
+}
+
+func (x *GameStarted) GetBoard() string {
+	if x != nil {
+		return x.Board
+	}
+	return ""
+}
+
+type MovePlayed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Game          int32                  `protobuf:"varint,1,opt,name=game,proto3" json:"game,omitempty"`
+	Move          int32                  `protobuf:"varint,2,opt,name=move,proto3" json:"move,omitempty"`    // numbered from 1 within the game
+	Player        string                 `protobuf:"bytes,3,opt,name=player,proto3" json:"player,omitempty"` // X or O
+	Position      int32                  `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"` // e.g. "position 4"
+	Board         string                 `protobuf:"bytes,6,opt,name=board,proto3" json:"board,omitempty"`             // after the move
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MovePlayed) Reset() {
+	*x = MovePlayed{}
+	mi := &file_llamatactoe_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MovePlayed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovePlayed) ProtoMessage() {}
+
+func (x *MovePlayed) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MovePlayed.ProtoReflect.Descriptor instead.
+func (*MovePlayed) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MovePlayed) GetGame() int32 {
+	if x != nil {
+		return x.Game
+	}
+	return 0
+}
+
+func (x *MovePlayed) GetMove() int32 {
+	if x != nil {
+		return x.Move
+	}
+	return 0
+}
+
+func (x *MovePlayed) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+func (x *MovePlayed) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *MovePlayed) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MovePlayed) GetBoard() string {
+	if x != nil {
+		return x.Board
+	}
+	return ""
+}
+
+type GameFinished struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Game          int32                  `protobuf:"varint,1,opt,name=game,proto3" json:"game,omitempty"`
+	Winner        string                 `protobuf:"bytes,2,opt,name=winner,proto3" json:"winner,omitempty"` // X, O, draw, or error
+	Result        string                 `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"` // e.g. "X wins on time"
+	Moves         int32                  `protobuf:"varint,4,opt,name=moves,proto3" json:"moves,omitempty"`
+	Seconds       float64                `protobuf:"fixed64,5,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameFinished) Reset() {
+	*x = GameFinished{}
+	mi := &file_llamatactoe_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameFinished) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameFinished) ProtoMessage() {}
+
+func (x *GameFinished) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameFinished.ProtoReflect.Descriptor instead.
+func (*GameFinished) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GameFinished) GetGame() int32 {
+	if x != nil {
+		return x.Game
+	}
+	return 0
+}
+
+func (x *GameFinished) GetWinner() string {
+	if x != nil {
+		return x.Winner
+	}
+	return ""
+}
+
+func (x *GameFinished) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *GameFinished) GetMoves() int32 {
+	if x != nil {
+		return x.Moves
+	}
+	return 0
+}
+
+func (x *GameFinished) GetSeconds() float64 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+type MatchFinished struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Games         int32                  `protobuf:"varint,1,opt,name=games,proto3" json:"games,omitempty"` // fewer than asked for if the run's budget ran out
+	XWins         int32                  `protobuf:"varint,2,opt,name=x_wins,json=xWins,proto3" json:"x_wins,omitempty"`
+	OWins         int32                  `protobuf:"varint,3,opt,name=o_wins,json=oWins,proto3" json:"o_wins,omitempty"`
+	Draws         int32                  `protobuf:"varint,4,opt,name=draws,proto3" json:"draws,omitempty"`
+	Errors        int32                  `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchFinished) Reset() {
+	*x = MatchFinished{}
+	mi := &file_llamatactoe_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchFinished) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchFinished) ProtoMessage() {}
+
+func (x *MatchFinished) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchFinished.ProtoReflect.Descriptor instead.
+func (*MatchFinished) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MatchFinished) GetGames() int32 {
+	if x != nil {
+		return x.Games
+	}
+	return 0
+}
+
+func (x *MatchFinished) GetXWins() int32 {
+	if x != nil {
+		return x.XWins
+	}
+	return 0
+}
+
+func (x *MatchFinished) GetOWins() int32 {
+	if x != nil {
+		return x.OWins
+	}
+	return 0
+}
+
+func (x *MatchFinished) GetDraws() int32 {
+	if x != nil {
+		return x.Draws
+	}
+	return 0
+}
+
+func (x *MatchFinished) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+type GetStandingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only this match's games, or every match's if empty
+	MatchId       string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStandingsRequest) Reset() {
+	*x = GetStandingsRequest{}
+	mi := &file_llamatactoe_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStandingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStandingsRequest) ProtoMessage() {}
+
+func (x *GetStandingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStandingsRequest.ProtoReflect.Descriptor instead.
+func (*GetStandingsRequest) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetStandingsRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+type Standings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Standings     []*Standing            `protobuf:"bytes,1,rep,name=standings,proto3" json:"standings,omitempty"` // best first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Standings) Reset() {
+	*x = Standings{}
+	mi := &file_llamatactoe_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Standings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Standings) ProtoMessage() {}
+
+func (x *Standings) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Standings.ProtoReflect.Descriptor instead.
+func (*Standings) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Standings) GetStandings() []*Standing {
+	if x != nil {
+		return x.Standings
+	}
+	return nil
+}
+
+// Standing is a player's record, scored like chess: a point for a win, half
+// for a draw, and a game a player couldn't finish forfeited to the opponent
+type Standing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Games         int32                  `protobuf:"varint,2,opt,name=games,proto3" json:"games,omitempty"`
+	Wins          int32                  `protobuf:"varint,3,opt,name=wins,proto3" json:"wins,omitempty"`
+	Draws         int32                  `protobuf:"varint,4,opt,name=draws,proto3" json:"draws,omitempty"`
+	Losses        int32                  `protobuf:"varint,5,opt,name=losses,proto3" json:"losses,omitempty"`
+	Points        float64                `protobuf:"fixed64,6,opt,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Standing) Reset() {
+	*x = Standing{}
+	mi := &file_llamatactoe_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Standing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Standing) ProtoMessage() {}
+
+func (x *Standing) ProtoReflect() protoreflect.Message {
+	mi := &file_llamatactoe_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Standing.ProtoReflect.Descriptor instead.
+func (*Standing) Descriptor() ([]byte, []int) {
+	return file_llamatactoe_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Standing) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Standing) GetGames() int32 {
+	if x != nil {
+		return x.Games
+	}
+	return 0
+}
+
+func (x *Standing) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *Standing) GetDraws() int32 {
+	if x != nil {
+		return x.Draws
+	}
+	return 0
+}
+
+func (x *Standing) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
+	}
+	return 0
+}
+
+func (x *Standing) GetPoints() float64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+var File_llamatactoe_proto protoreflect.FileDescriptor
+
+const file_llamatactoe_proto_rawDesc = "" +
+	"\n" +
+	"\x11llamatactoe.proto\x12\x0ellamatactoe.v1\"`\n" +
+	"\x12CreateMatchRequest\x12\x19\n" +
+	"\bplayer_x\x18\x01 \x01(\tR\aplayerX\x12\x19\n" +
+	"\bplayer_o\x18\x02 \x01(\tR\aplayerO\x12\x14\n" +
+	"\x05games\x18\x03 \x01(\x05R\x05games\"w\n" +
+	"\x05Match\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04game\x18\x02 \x01(\tR\x04game\x12\x19\n" +
+	"\bplayer_x\x18\x03 \x01(\tR\aplayerX\x12\x19\n" +
+	"\bplayer_o\x18\x04 \x01(\tR\aplayerO\x12\x14\n" +
+	"\x05games\x18\x05 \x01(\x05R\x05games\"0\n" +
+	"\x13StreamEventsRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\"\xb9\x02\n" +
+	"\x05Event\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12@\n" +
+	"\fgame_started\x18\x02 \x01(\v2\x1b.llamatactoe.v1.GameStartedH\x00R\vgameStarted\x12=\n" +
+	"\vmove_played\x18\x03 \x01(\v2\x1a.llamatactoe.v1.MovePlayedH\x00R\n" +
+	"movePlayed\x12C\n" +
+	"\rgame_finished\x18\x04 \x01(\v2\x1c.llamatactoe.v1.GameFinishedH\x00R\fgameFinished\x12F\n" +
+	"\x0ematch_finished\x18\x05 \x01(\v2\x1d.llamatactoe.v1.MatchFinishedH\x00R\rmatchFinishedB\a\n" +
+	"\x05event\"7\n" +
+	"\vGameStarted\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\x05R\x04game\x12\x14\n" +
+	"\x05board\x18\x02 \x01(\tR\x05board\"\xa0\x01\n" +
+	"\n" +
+	"MovePlayed\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\x05R\x04game\x12\x12\n" +
+	"\x04move\x18\x02 \x01(\x05R\x04move\x12\x16\n" +
+	"\x06player\x18\x03 \x01(\tR\x06player\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\x05R\bposition\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05board\x18\x06 \x01(\tR\x05board\"\x82\x01\n" +
+	"\fGameFinished\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\x05R\x04game\x12\x16\n" +
+	"\x06winner\x18\x02 \x01(\tR\x06winner\x12\x16\n" +
+	"\x06result\x18\x03 \x01(\tR\x06result\x12\x14\n" +
+	"\x05moves\x18\x04 \x01(\x05R\x05moves\x12\x18\n" +
+	"\aseconds\x18\x05 \x01(\x01R\aseconds\"\x81\x01\n" +
+	"\rMatchFinished\x12\x14\n" +
+	"\x05games\x18\x01 \x01(\x05R\x05games\x12\x15\n" +
+	"\x06x_wins\x18\x02 \x01(\x05R\x05xWins\x12\x15\n" +
+	"\x06o_wins\x18\x03 \x01(\x05R\x05oWins\x12\x14\n" +
+	"\x05draws\x18\x04 \x01(\x05R\x05draws\x12\x16\n" +
+	"\x06errors\x18\x05 \x01(\x05R\x06errors\"0\n" +
+	"\x13GetStandingsRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\"C\n" +
+	"\tStandings\x126\n" +
+	"\tstandings\x18\x01 \x03(\v2\x18.llamatactoe.v1.StandingR\tstandings\"\x8e\x01\n" +
+	"\bStanding\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05games\x18\x02 \x01(\x05R\x05games\x12\x12\n" +
+	"\x04wins\x18\x03 \x01(\x05R\x04wins\x12\x14\n" +
+	"\x05draws\x18\x04 \x01(\x05R\x05draws\x12\x16\n" +
+	"\x06losses\x18\x05 \x01(\x05R\x06losses\x12\x16\n" +
+	"\x06points\x18\x06 \x01(\x01R\x06points2\xf5\x01\n" +
+	"\vLlamaTacToe\x12H\n" +
+	"\vCreateMatch\x12\".llamatactoe.v1.CreateMatchRequest\x1a\x15.llamatactoe.v1.Match\x12L\n" +
+	"\fStreamEvents\x12#.llamatactoe.v1.StreamEventsRequest\x1a\x15.llamatactoe.v1.Event0\x01\x12N\n" +
+	"\fGetStandings\x12#.llamatactoe.v1.GetStandingsRequest\x1a\x19.llamatactoe.v1.StandingsB*Z(github.com/brianhealey/llama-tac-toe/apib\x06proto3"
+
+var (
+	file_llamatactoe_proto_rawDescOnce sync.Once
+	file_llamatactoe_proto_rawDescData []byte
+)
+
+func file_llamatactoe_proto_rawDescGZIP() []byte {
+	file_llamatactoe_proto_rawDescOnce.Do(func() {
+		file_llamatactoe_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_llamatactoe_proto_rawDesc), len(file_llamatactoe_proto_rawDesc)))
+	})
+	return file_llamatactoe_proto_rawDescData
+}
+
+var file_llamatactoe_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_llamatactoe_proto_goTypes = []any{
+	(*CreateMatchRequest)(nil),  // 0: llamatactoe.v1.CreateMatchRequest
+	(*Match)(nil),               // 1: llamatactoe.v1.Match
+	(*StreamEventsRequest)(nil), // 2: llamatactoe.v1.StreamEventsRequest
+	(*Event)(nil),               // 3: llamatactoe.v1.Event
+	(*GameStarted)(nil),         // 4: llamatactoe.v1.GameStarted
+	(*MovePlayed)(nil),          // 5: llamatactoe.v1.MovePlayed
+	(*GameFinished)(nil),        // 6: llamatactoe.v1.GameFinished
+	(*MatchFinished)(nil),       // 7: llamatactoe.v1.MatchFinished
+	(*GetStandingsRequest)(nil), // 8: llamatactoe.v1.GetStandingsRequest
+	(*Standings)(nil),           // 9: llamatactoe.v1.Standings
+	(*Standing)(nil),            // 10: llamatactoe.v1.Standing
+}
+var file_llamatactoe_proto_depIdxs = []int32{
+	4,  // 0: llamatactoe.v1.Event.game_started:type_name -> llamatactoe.v1.GameStarted
+	5,  // 1: llamatactoe.v1.Event.move_played:type_name -> llamatactoe.v1.MovePlayed
+	6,  // 2: llamatactoe.v1.Event.game_finished:type_name -> llamatactoe.v1.GameFinished
+	7,  // 3: llamatactoe.v1.Event.match_finished:type_name -> llamatactoe.v1.MatchFinished
+	10, // 4: llamatactoe.v1.Standings.standings:type_name -> llamatactoe.v1.Standing
+	0,  // 5: llamatactoe.v1.LlamaTacToe.CreateMatch:input_type -> llamatactoe.v1.CreateMatchRequest
+	2,  // 6: llamatactoe.v1.LlamaTacToe.StreamEvents:input_type -> llamatactoe.v1.StreamEventsRequest
+	8,  // 7: llamatactoe.v1.LlamaTacToe.GetStandings:input_type -> llamatactoe.v1.GetStandingsRequest
+	1,  // 8: llamatactoe.v1.LlamaTacToe.CreateMatch:output_type -> llamatactoe.v1.Match
+	3,  // 9: llamatactoe.v1.LlamaTacToe.StreamEvents:output_type -> llamatactoe.v1.Event
+	9,  // 10: llamatactoe.v1.LlamaTacToe.GetStandings:output_type -> llamatactoe.v1.Standings
+	8,  // [8:11] is the sub-list for method output_type
+	5,  // [5:8] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_llamatactoe_proto_init() }
+func file_llamatactoe_proto_init() {
+	if File_llamatactoe_proto != nil {
+		return
+	}
+	file_llamatactoe_proto_msgTypes[3].OneofWrappers = []any{
+		(*Event_GameStarted)(nil),
+		(*Event_MovePlayed)(nil),
+		(*Event_GameFinished)(nil),
+		(*Event_MatchFinished)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llamatactoe_proto_rawDesc), len(file_llamatactoe_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_llamatactoe_proto_goTypes,
+		DependencyIndexes: file_llamatactoe_proto_depIdxs,
+		MessageInfos:      file_llamatactoe_proto_msgTypes,
+	}.Build()
+	File_llamatactoe_proto = out.File
+	file_llamatactoe_proto_goTypes = nil
+	file_llamatactoe_proto_depIdxs = nil
+}
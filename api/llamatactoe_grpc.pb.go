@@ -0,0 +1,212 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: llamatactoe.proto
+
+// The llama-tac-toe API, served by "llama-tac-toe grpc", for services that
+// orchestrate matches between models programmatically
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LlamaTacToe_CreateMatch_FullMethodName  = "/llamatactoe.v1.LlamaTacToe/CreateMatch"
+	LlamaTacToe_StreamEvents_FullMethodName = "/llamatactoe.v1.LlamaTacToe/StreamEvents"
+	LlamaTacToe_GetStandings_FullMethodName = "/llamatactoe.v1.LlamaTacToe/GetStandings"
+)
+
+// LlamaTacToeClient is the client API for LlamaTacToe service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LlamaTacToeClient interface {
+	// CreateMatch starts a match in the background and returns it at once
+	CreateMatch(ctx context.Context, in *CreateMatchRequest, opts ...grpc.CallOption) (*Match, error)
+	// StreamEvents sends a match's events from its start, then as they
+	// happen, ending once the match is finished
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// GetStandings ranks the players of the matches played so far
+	GetStandings(ctx context.Context, in *GetStandingsRequest, opts ...grpc.CallOption) (*Standings, error)
+}
+
+type llamaTacToeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLlamaTacToeClient(cc grpc.ClientConnInterface) LlamaTacToeClient {
+	return &llamaTacToeClient{cc}
+}
+
+func (c *llamaTacToeClient) CreateMatch(ctx context.Context, in *CreateMatchRequest, opts ...grpc.CallOption) (*Match, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Match)
+	err := c.cc.Invoke(ctx, LlamaTacToe_CreateMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *llamaTacToeClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LlamaTacToe_ServiceDesc.Streams[0], LlamaTacToe_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LlamaTacToe_StreamEventsClient = grpc.ServerStreamingClient[Event]
+
+func (c *llamaTacToeClient) GetStandings(ctx context.Context, in *GetStandingsRequest, opts ...grpc.CallOption) (*Standings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Standings)
+	err := c.cc.Invoke(ctx, LlamaTacToe_GetStandings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LlamaTacToeServer is the server API for LlamaTacToe service.
+// All implementations must embed UnimplementedLlamaTacToeServer
+// for forward compatibility.
+type LlamaTacToeServer interface {
+	// CreateMatch starts a match in the background and returns it at once
+	CreateMatch(context.Context, *CreateMatchRequest) (*Match, error)
+	// StreamEvents sends a match's events from its start, then as they
+	// happen, ending once the match is finished
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error
+	// GetStandings ranks the players of the matches played so far
+	GetStandings(context.Context, *GetStandingsRequest) (*Standings, error)
+	mustEmbedUnimplementedLlamaTacToeServer()
+}
+
+// UnimplementedLlamaTacToeServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLlamaTacToeServer struct{}
+
+func (UnimplementedLlamaTacToeServer) CreateMatch(context.Context, *CreateMatchRequest) (*Match, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateMatch not implemented")
+}
+func (UnimplementedLlamaTacToeServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Error(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedLlamaTacToeServer) GetStandings(context.Context, *GetStandingsRequest) (*Standings, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStandings not implemented")
+}
+func (UnimplementedLlamaTacToeServer) mustEmbedUnimplementedLlamaTacToeServer() {}
+func (UnimplementedLlamaTacToeServer) testEmbeddedByValue()                     {}
+
+// UnsafeLlamaTacToeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LlamaTacToeServer will
+// result in compilation errors.
+type UnsafeLlamaTacToeServer interface {
+	mustEmbedUnimplementedLlamaTacToeServer()
+}
+
+func RegisterLlamaTacToeServer(s grpc.ServiceRegistrar, srv LlamaTacToeServer) {
+	// If the following call panics, it indicates UnimplementedLlamaTacToeServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LlamaTacToe_ServiceDesc, srv)
+}
+
+func _LlamaTacToe_CreateMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LlamaTacToeServer).CreateMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LlamaTacToe_CreateMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LlamaTacToeServer).CreateMatch(ctx, req.(*CreateMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LlamaTacToe_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LlamaTacToeServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LlamaTacToe_StreamEventsServer = grpc.ServerStreamingServer[Event]
+
+func _LlamaTacToe_GetStandings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStandingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LlamaTacToeServer).GetStandings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LlamaTacToe_GetStandings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LlamaTacToeServer).GetStandings(ctx, req.(*GetStandingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LlamaTacToe_ServiceDesc is the grpc.ServiceDesc for LlamaTacToe service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LlamaTacToe_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "llamatactoe.v1.LlamaTacToe",
+	HandlerType: (*LlamaTacToeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateMatch",
+			Handler:    _LlamaTacToe_CreateMatch_Handler,
+		},
+		{
+			MethodName: "GetStandings",
+			Handler:    _LlamaTacToe_GetStandings_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _LlamaTacToe_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "llamatactoe.proto",
+}
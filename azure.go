@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const azureDefaultAPIVersion = "2024-06-01"
+
+func init() {
+	RegisterProvider(&BasicProvider{
+		ID:   "azure",
+		Info: "Azure OpenAI deployments (key from AZURE_OPENAI_API_KEY)",
+		Factory: func(cfg BackendConfig) (Backend, error) {
+			return newAzureOpenAIBackend(cfg), nil
+		},
+	})
+}
+
+// AzureOpenAIBackend talks to an Azure OpenAI deployment. Azure selects the
+// model by deployment name, so the request's model is only used as a fallback
+// when no deployment is configured.
+type AzureOpenAIBackend struct {
+	URL        string // e.g. https://my-resource.openai.azure.com
+	Deployment string
+	APIVersion string
+	APIKey     string
+	Headers    map[string]string
+}
+
+// Generate sends the prompt to the deployment's chat completions endpoint
+func (a *AzureOpenAIBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	if a.URL == "" {
+		return LLMResponse{}, fmt.Errorf("azure backend requires -url set to the resource endpoint")
+	}
+	if a.APIKey == "" {
+		return LLMResponse{}, fmt.Errorf("azure backend requires an API key (set AZURE_OPENAI_API_KEY)")
+	}
+	deployment := orDefault(a.Deployment, req.Model)
+	endpoint := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		strings.TrimSuffix(a.URL, "/"), url.PathEscape(deployment), url.QueryEscape(orDefault(a.APIVersion, azureDefaultAPIVersion)))
+
+	// The deployment already determines the model
+	req.Model = ""
+	return chatCompletion(ctx, endpoint, mergeHeaders(map[string]string{"api-key": a.APIKey}, a.Headers), req)
+}
+
+// newAzureOpenAIBackend falls back to the API key from the environment
+func newAzureOpenAIBackend(cfg BackendConfig) *AzureOpenAIBackend {
+	return &AzureOpenAIBackend{
+		URL:        cfg.URL,
+		Deployment: cfg.AzureDeployment,
+		APIVersion: cfg.AzureAPIVersion,
+		APIKey:     orDefault(cfg.APIKey, os.Getenv("AZURE_OPENAI_API_KEY")),
+		Headers:    cfg.Headers,
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backend sends prompts to an LLM API
+type Backend interface {
+	Generate(ctx context.Context, req LLMRequest) (LLMResponse, error)
+}
+
+// LLMRequest is a backend-independent completion request
+type LLMRequest struct {
+	Model       string
+	System      string        // optional system prompt
+	Messages    []ChatMessage // earlier conversation turns; Prompt follows as the last user message
+	Prompt      string
+	Temperature float64
+	TopP        float64            // nucleus sampling cutoff; 0 uses the backend's default
+	TopK        int                // sample from the K most likely tokens; 0 uses the backend's default
+	Seed        int                // random seed for reproducible sampling; 0 leaves it unseeded
+	MaxTokens   int                // 0 uses the backend's default
+	Tools       []ToolDefinition   // functions the model may call instead of answering in text
+	Format      map[string]any     // JSON schema the response must follow; backends without structured output ignore it
+	Grammar     string             // GBNF grammar constraining the output; only llama.cpp uses it
+	LogitBias   map[string]float64 // bias added to the logits of each token, keyed by token text
+	// OnToken, when set, asks the backend to stream its response and receives
+	// each piece of text as it arrives. Backends that cannot stream ignore it.
+	OnToken func(text string)
+}
+
+// LLMResponse is a backend-independent completion response
+type LLMResponse struct {
+	Text      string
+	ToolCalls []ToolCall
+	Usage     Usage // zero when the backend doesn't report token counts
+}
+
+// Usage counts the tokens used by one or more requests
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Add accumulates another request's usage
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+}
+
+// Total returns the prompt and completion tokens combined
+func (u Usage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// BackendConfig holds the settings used to create a backend
+type BackendConfig struct {
+	URL             string
+	AzureDeployment string
+	AzureAPIVersion string
+	AWSRegion       string
+	APIKey          string            // overrides the provider's API key environment variable
+	Headers         map[string]string // extra headers sent with every request
+	KeepAlive       string            // how long Ollama keeps the model loaded, e.g. "10m" or "-1"
+	Balance         string            // how requests are spread over comma-separated URLs: round-robin or least-busy
+}
+
+// Provider creates backends for one LLM API. Providers register themselves
+// with RegisterProvider, usually from an init function in their own file, and
+// are then selectable by name with -backend.
+type Provider interface {
+	Name() string
+	Description() string
+	// DefaultURL is used when -url is not given; empty lets the backend
+	// work out its own endpoint
+	DefaultURL() string
+	New(cfg BackendConfig) (Backend, error)
+}
+
+// BasicProvider is a Provider built from a name, description, default URL, and constructor
+type BasicProvider struct {
+	ID      string
+	Info    string
+	URL     string
+	Factory func(cfg BackendConfig) (Backend, error)
+}
+
+func (p *BasicProvider) Name() string        { return p.ID }
+func (p *BasicProvider) Description() string { return p.Info }
+func (p *BasicProvider) DefaultURL() string  { return p.URL }
+
+func (p *BasicProvider) New(cfg BackendConfig) (Backend, error) {
+	return p.Factory(cfg)
+}
+
+var providers = make(map[string]Provider)
+
+// RegisterProvider makes a provider available by name; registering the same name twice panics
+func RegisterProvider(p Provider) {
+	if _, exists := providers[p.Name()]; exists {
+		panic(fmt.Sprintf("backend provider %q registered twice", p.Name()))
+	}
+	providers[p.Name()] = p
+}
+
+// ProviderNames returns the registered provider names in sorted order
+func ProviderNames() []string {
+	var names []string
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListProviders prints the registered providers
+func ListProviders() {
+	fmt.Println("Available backends:")
+	for _, name := range ProviderNames() {
+		p := providers[name]
+		fmt.Printf("  %-12s %s\n", name, p.Description())
+		if p.DefaultURL() != "" {
+			fmt.Printf("  %-12s default URL: %s\n", "", p.DefaultURL())
+		}
+	}
+}
+
+// NewBackend creates a backend from the provider registered under name. A
+// comma-separated URL creates one backend per endpoint behind a BalancedBackend.
+func NewBackend(name string, cfg BackendConfig) (Backend, error) {
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q: must be one of %s", name, strings.Join(ProviderNames(), ", "))
+	}
+	urls := strings.Split(cfg.URL, ",")
+	if len(urls) == 1 {
+		return p.New(cfg)
+	}
+
+	var backends []Backend
+	for _, url := range urls {
+		endpointCfg := cfg
+		endpointCfg.URL = strings.TrimSpace(url)
+		backend, err := p.New(endpointCfg)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+	return NewBalancedBackend(backends, cfg.Balance)
+}
+
+// DefaultBackendURL returns the URL used when -url is not given for the named backend
+func DefaultBackendURL(name string) string {
+	if p, ok := providers[name]; ok {
+		return p.DefaultURL()
+	}
+	return ""
+}
+
+// CallLLM sends a request to the backend and returns the response and duration.
+// The request is abandoned if ctx is cancelled or its deadline passes.
+func CallLLM(ctx context.Context, backend Backend, req LLMRequest) (LLMResponse, time.Duration, error) {
+	startTime := time.Now()
+	resp, err := backend.Generate(ctx, req)
+	if err != nil {
+		return LLMResponse{}, 0, err
+	}
+	return resp, time.Since(startTime), nil
+}
+
+// bearerHeaders adds an Authorization bearer token, if apiKey is set, to the custom headers
+func bearerHeaders(apiKey string, custom map[string]string) map[string]string {
+	if apiKey == "" {
+		return custom
+	}
+	return mergeHeaders(map[string]string{"Authorization": "Bearer " + apiKey}, custom)
+}
+
+// mergeHeaders combines backend-required headers with custom ones; custom headers win
+func mergeHeaders(base, custom map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(custom))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range custom {
+		merged[k] = v
+	}
+	return merged
+}
+
+// newJSONRequest builds a POST request with body encoded as JSON
+func newJSONRequest(ctx context.Context, url string, headers map[string]string, body any) (*http.Request, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// postJSON sends body as JSON to url and decodes the JSON response into out
+func postJSON(ctx context.Context, url string, headers map[string]string, body any, out any) error {
+	req, err := newJSONRequest(ctx, url, headers, body)
+	if err != nil {
+		return err
+	}
+	return doJSON(req, out)
+}
+
+// getJSON fetches url and decodes the JSON response into out
+func getJSON(ctx context.Context, url string, headers map[string]string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return doJSON(req, out)
+}
+
+// doJSON sends a prepared request and decodes the JSON response into out
+func doJSON(req *http.Request, out any) error {
+	resp, err := send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// HTTPError is a non-200 response from a backend
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	Body       string
+	RetryAfter time.Duration // from the Retry-After header, if the server sent one
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d from %s: %s", e.StatusCode, e.URL, e.Body)
+}
+
+// send performs a request, turning non-200 responses into HTTPErrors
+func send(req *http.Request) (*http.Response, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		httpErr := &HTTPError{StatusCode: resp.StatusCode, URL: req.URL.String(), Body: strings.TrimSpace(string(respBody))}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			httpErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, httpErr
+	}
+	return resp, nil
+}
+
+// streamText posts a streaming request and returns the assembled response text.
+// Each chunk (a JSON line, or the data of a server-sent event when sse is set)
+// is decoded by decode, and its text is passed on to onToken as it arrives.
+func streamText(ctx context.Context, url string, headers map[string]string, body any, sse bool, onToken func(string), decode func(data []byte) (string, error)) (string, error) {
+	req, err := newJSONRequest(ctx, url, headers, body)
+	if err != nil {
+		return "", err
+	}
+	resp, err := send(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if sse {
+			data, ok := strings.CutPrefix(line, "data:")
+			if !ok {
+				continue
+			}
+			line = strings.TrimSpace(data)
+		}
+		if line == "" || line == "[DONE]" {
+			continue
+		}
+		chunk, err := decode([]byte(line))
+		if err != nil {
+			return text.String(), err
+		}
+		if chunk != "" {
+			text.WriteString(chunk)
+			onToken(chunk)
+		}
+	}
+	return text.String(), scanner.Err()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BalancedBackend spreads requests across several endpoints serving the same
+// models, e.g. one Ollama server per GPU or host
+type BalancedBackend struct {
+	Backends []Backend
+	Strategy string // "round-robin" or "least-busy"
+
+	mu       sync.Mutex
+	next     int
+	inFlight []int
+}
+
+// NewBalancedBackend checks the strategy name and creates the balancer
+func NewBalancedBackend(backends []Backend, strategy string) (*BalancedBackend, error) {
+	switch strategy {
+	case "", "round-robin":
+		strategy = "round-robin"
+	case "least-busy":
+	default:
+		return nil, fmt.Errorf("unknown balance strategy %q: must be round-robin or least-busy", strategy)
+	}
+	return &BalancedBackend{Backends: backends, Strategy: strategy, inFlight: make([]int, len(backends))}, nil
+}
+
+// Endpoints returns the number of endpoints requests are spread over
+func (b *BalancedBackend) Endpoints() int {
+	return len(b.Backends)
+}
+
+// endpointCount returns how many endpoints a backend sends requests to
+func endpointCount(backend Backend) int {
+	if multi, ok := backend.(interface{ Endpoints() int }); ok {
+		return multi.Endpoints()
+	}
+	return 1
+}
+
+// Generate sends the request to the endpoint chosen by the strategy
+func (b *BalancedBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	i := b.acquire()
+	defer b.release(i)
+	return b.Backends[i].Generate(ctx, req)
+}
+
+// acquire picks an endpoint and counts the request against it
+func (b *BalancedBackend) acquire() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := b.next
+	if b.Strategy == "least-busy" {
+		// Start the scan at the round-robin position so ties rotate
+		for k := range b.Backends {
+			j := (b.next + k) % len(b.Backends)
+			if b.inFlight[j] < b.inFlight[i] {
+				i = j
+			}
+		}
+	}
+	b.next = (i + 1) % len(b.Backends)
+	b.inFlight[i]++
+	return i
+}
+
+func (b *BalancedBackend) release(i int) {
+	b.mu.Lock()
+	b.inFlight[i]--
+	b.mu.Unlock()
+}
+
+// ListModels returns the models available on every endpoint, since any of
+// them may be asked for a move
+func (b *BalancedBackend) ListModels(ctx context.Context) ([]string, error) {
+	var common []string
+	for i, backend := range b.Backends {
+		lister, ok := backend.(ModelLister)
+		if !ok {
+			return nil, errCannotListModels
+		}
+		models, err := lister.ListModels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			common = models
+			continue
+		}
+		var kept []string
+		for _, model := range common {
+			if hasModel(models, model) {
+				kept = append(kept, model)
+			}
+		}
+		common = kept
+	}
+	return common, nil
+}
+
+// PullModel pulls the model on every endpoint that can pull models
+func (b *BalancedBackend) PullModel(ctx context.Context, model string, progress func(status string, completed, total int64)) error {
+	for _, backend := range b.Backends {
+		puller, ok := backend.(ModelPuller)
+		if !ok {
+			continue
+		}
+		if err := puller.PullModel(ctx, model, progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	bedrockDefaultRegion    = "us-east-1"
+	bedrockAnthropicVersion = "bedrock-2023-05-31"
+	bedrockDefaultMaxTokens = 512
+)
+
+func init() {
+	RegisterProvider(&BasicProvider{
+		ID:   "bedrock",
+		Info: "AWS Bedrock runtime with SigV4 (credentials from AWS_* variables)",
+		Factory: func(cfg BackendConfig) (Backend, error) {
+			return newBedrockBackend(cfg), nil
+		},
+	})
+}
+
+// BedrockBackend calls models on the AWS Bedrock runtime InvokeModel API,
+// signing each request with SigV4. Each model family on Bedrock expects its
+// own request body, chosen from the model ID.
+type BedrockBackend struct {
+	URL          string // e.g. https://bedrock-runtime.us-east-1.amazonaws.com
+	Region       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Headers      map[string]string
+}
+
+type BedrockAnthropicRequest struct {
+	AnthropicVersion string        `json:"anthropic_version"`
+	MaxTokens        int           `json:"max_tokens"`
+	System           string        `json:"system,omitempty"`
+	Messages         []ChatMessage `json:"messages"`
+	Temperature      float64       `json:"temperature"`
+	TopP             float64       `json:"top_p,omitempty"`
+	TopK             int           `json:"top_k,omitempty"`
+}
+
+type BedrockTitanRequest struct {
+	InputText            string `json:"inputText"`
+	TextGenerationConfig struct {
+		MaxTokenCount int     `json:"maxTokenCount"`
+		Temperature   float64 `json:"temperature"`
+		TopP          float64 `json:"topP,omitempty"`
+	} `json:"textGenerationConfig"`
+}
+
+type BedrockTitanResponse struct {
+	InputTextTokenCount int `json:"inputTextTokenCount"`
+	Results             []struct {
+		OutputText string `json:"outputText"`
+		TokenCount int    `json:"tokenCount"`
+	} `json:"results"`
+}
+
+type BedrockLlamaRequest struct {
+	Prompt      string  `json:"prompt"`
+	MaxGenLen   int     `json:"max_gen_len"`
+	Temperature float64 `json:"temperature"`
+	TopP        float64 `json:"top_p,omitempty"`
+}
+
+type BedrockLlamaResponse struct {
+	Generation           string `json:"generation"`
+	PromptTokenCount     int    `json:"prompt_token_count"`
+	GenerationTokenCount int    `json:"generation_token_count"`
+}
+
+// newBedrockBackend reads AWS credentials from the environment
+func newBedrockBackend(cfg BackendConfig) *BedrockBackend {
+	region := orDefault(cfg.AWSRegion, orDefault(os.Getenv("AWS_REGION"), orDefault(os.Getenv("AWS_DEFAULT_REGION"), bedrockDefaultRegion)))
+	return &BedrockBackend{
+		URL:          orDefault(cfg.URL, "https://bedrock-runtime."+region+".amazonaws.com"),
+		Region:       region,
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		Headers:      cfg.Headers,
+	}
+}
+
+// Generate invokes the model named by req.Model (a Bedrock model ID)
+func (b *BedrockBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	if b.AccessKey == "" || b.SecretKey == "" {
+		return LLMResponse{}, fmt.Errorf("bedrock backend requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = bedrockDefaultMaxTokens
+	}
+
+	modelID := req.Model
+	switch {
+	case strings.Contains(modelID, "anthropic."):
+		body := BedrockAnthropicRequest{
+			AnthropicVersion: bedrockAnthropicVersion,
+			MaxTokens:        maxTokens,
+			System:           req.System,
+			Messages:         appendChatMessage(append([]ChatMessage(nil), req.Messages...), ChatMessage{Role: "user", Content: req.Prompt}),
+			Temperature:      req.Temperature,
+			TopP:             req.TopP,
+			TopK:             req.TopK,
+		}
+		var resp AnthropicResponse
+		if err := b.invoke(ctx, modelID, body, &resp); err != nil {
+			return LLMResponse{}, err
+		}
+		var text strings.Builder
+		for _, block := range resp.Content {
+			if block.Type == "text" {
+				text.WriteString(block.Text)
+			}
+		}
+		return LLMResponse{Text: text.String(), Usage: resp.Usage.usage()}, nil
+
+	case strings.Contains(modelID, "amazon.titan"):
+		var body BedrockTitanRequest
+		body.InputText = flattenConversation(req)
+		body.TextGenerationConfig.MaxTokenCount = maxTokens
+		body.TextGenerationConfig.Temperature = req.Temperature
+		body.TextGenerationConfig.TopP = req.TopP
+		var resp BedrockTitanResponse
+		if err := b.invoke(ctx, modelID, body, &resp); err != nil {
+			return LLMResponse{}, err
+		}
+		if len(resp.Results) == 0 {
+			return LLMResponse{}, fmt.Errorf("response contained no results")
+		}
+		usage := Usage{PromptTokens: resp.InputTextTokenCount, CompletionTokens: resp.Results[0].TokenCount}
+		return LLMResponse{Text: resp.Results[0].OutputText, Usage: usage}, nil
+
+	case strings.Contains(modelID, "meta.llama"):
+		body := BedrockLlamaRequest{
+			Prompt:      llama3Prompt(req),
+			MaxGenLen:   maxTokens,
+			Temperature: req.Temperature,
+			TopP:        req.TopP,
+		}
+		var resp BedrockLlamaResponse
+		if err := b.invoke(ctx, modelID, body, &resp); err != nil {
+			return LLMResponse{}, err
+		}
+		usage := Usage{PromptTokens: resp.PromptTokenCount, CompletionTokens: resp.GenerationTokenCount}
+		return LLMResponse{Text: resp.Generation, Usage: usage}, nil
+	}
+	return LLMResponse{}, fmt.Errorf("unsupported Bedrock model %q: expected an anthropic, amazon.titan, or meta.llama model ID", modelID)
+}
+
+// invoke signs and sends an InvokeModel request
+func (b *BedrockBackend) invoke(ctx context.Context, modelID string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(b.URL, "/"))
+	if err != nil {
+		return err
+	}
+	escapedPath := endpoint.EscapedPath() + "/model/" + awsURIEscape(modelID) + "/invoke"
+	endpoint.Path = endpoint.Path + "/model/" + modelID + "/invoke"
+	endpoint.RawPath = escapedPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range b.Headers {
+		req.Header.Set(k, v)
+	}
+	b.sign(req, escapedPath, payload, time.Now().UTC())
+	return doJSON(req, out)
+}
+
+// sign adds AWS Signature Version 4 headers for the bedrock service
+func (b *BedrockBackend) sign(req *http.Request, escapedPath string, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if b.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", b.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Services other than S3 expect each path segment to be escaped a second time
+	segments := strings.Split(escapedPath, "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEscape(segment)
+	}
+
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		strings.Join(segments, "/"),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + b.Region + "/bedrock/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+b.SecretKey), date)
+	key = hmacSHA256(key, b.Region)
+	key = hmacSHA256(key, "bedrock")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		b.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsURIEscape percent-encodes everything except RFC 3986 unreserved characters
+func awsURIEscape(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			out.WriteByte(c)
+		} else {
+			fmt.Fprintf(&out, "%%%02X", c)
+		}
+	}
+	return out.String()
+}
+
+// flattenConversation renders a request as plain text for models without a chat format
+func flattenConversation(req LLMRequest) string {
+	var text strings.Builder
+	if req.System != "" {
+		text.WriteString(req.System + "\n\n")
+	}
+	for _, msg := range req.Messages {
+		role := "User"
+		if msg.Role == "assistant" {
+			role = "Bot"
+		}
+		text.WriteString(role + ": " + msg.Content + "\n\n")
+	}
+	if len(req.Messages) > 0 {
+		text.WriteString("User: " + req.Prompt + "\n\nBot:")
+	} else {
+		text.WriteString(req.Prompt)
+	}
+	return text.String()
+}
+
+// llama3Prompt wraps a request in the Llama 3 chat template
+func llama3Prompt(req LLMRequest) string {
+	var text strings.Builder
+	text.WriteString("<|begin_of_text|>")
+	for _, msg := range chatMessages(req) {
+		text.WriteString("<|start_header_id|>" + msg.Role + "<|end_header_id|>\n\n" + msg.Content + "<|eot_id|>")
+	}
+	text.WriteString("<|start_header_id|>assistant<|end_header_id|>\n\n")
+	return text.String()
+}
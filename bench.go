@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// BenchEngines are the reference engines the bench subcommand can play a
+// model against, from weakest to strongest
+var BenchEngines = []string{"random", "heuristic", "mcts", "minimax"}
+
+// Bench plays one model against each of several engines under the same
+// conditions: round by round, every engine plays game N of the round with the
+// same side moving first, so drift in the backend over the run affects them
+// all alike. The model plays X and the engines O, with the first move
+// alternating between rounds.
+type Bench struct {
+	Model   Agent
+	Engines []Agent
+	Games   int // games against each engine
+	// BeforeGame, if set, is called as every game starts, e.g. to announce it
+	BeforeGame func(agents map[string]Agent)
+	// AfterGame, if set, is called with every finished game, e.g. to
+	// record statistics
+	AfterGame func(result GameResult, agents map[string]Agent)
+	// Out is where games print their moves, os.Stdout if nil
+	Out io.Writer
+}
+
+// Run plays the benchmark's games, stopping early if stop says so after a game
+func (b *Bench) Run(newGame func() Game, opening Opening, clock *Clock, maxRetries int, debug bool, stats *GameStats, stop func() bool) {
+	out := b.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	for round := 1; round <= b.Games; round++ {
+		fmt.Fprintf(out, "\n##### Round %d of %d #####\n", round, b.Games)
+		for _, engine := range b.Engines {
+			agents := map[string]Agent{PlayerX: b.Model, PlayerO: engine}
+			fmt.Fprintf(out, "\n--- Against %s ---\n", engine.Name())
+			if b.BeforeGame != nil {
+				b.BeforeGame(agents)
+			}
+			// The round number, not a running count, picks who moves first
+			result := PlayGame(out, newGame(), agents, opening, clock, maxRetries, debug, round, stats)
+			if b.AfterGame != nil {
+				b.AfterGame(result, agents)
+			}
+			if stop() {
+				return
+			}
+		}
+	}
+}
+
+// Report prints the model's record and score against each engine with 95%
+// confidence intervals, and the benchmark score: its mean score over the
+// engines. perfect says minimax plays perfectly, so a draw is the best
+// result against it.
+func (b *Bench) Report(w io.Writer, stats *GameStats, perfect bool) {
+	fmt.Fprintf(w, "  %-16s %6s %10s %22s\n", "Engine", "Games", "W-L-D", "Score % [95% CI]")
+	var total float64
+	for _, engine := range b.Engines {
+		record := b.record(stats, engine)
+		score := "-"
+		if record.Games() > 0 {
+			low, high := WilsonInterval(record.Score(), record.Games())
+			score = fmt.Sprintf("%5.1f%% [%4.1f, %5.1f]", record.Score()*100, low*100, high*100)
+		}
+		fmt.Fprintf(w, "  %-16s %6d %10s %22s\n", engine.Name(), record.Games(), record, score)
+		total += record.Score()
+	}
+	if len(b.Engines) > 1 {
+		fmt.Fprintf(w, "Benchmark score:    %.1f%% (mean score over %d engines)\n", total/float64(len(b.Engines))*100, len(b.Engines))
+	}
+	for _, engine := range b.Engines {
+		if _, ok := engine.(*MinimaxAgent); ok && perfect {
+			if record := b.record(stats, engine); record.Games() > 0 {
+				// Perfect play never loses from the empty board, so a draw is the best result an LLM can get
+				fmt.Fprintf(w, "Against perfect play, theoretical draws achieved: %d/%d (%.1f%%)\n", record.Draws, record.Games(), rate(record.Draws, record.Games())*100)
+			}
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
+
+// record is the model's record against the engine
+func (b *Bench) record(stats *GameStats, engine Agent) WLD {
+	if h, ok := stats.HeadToHead[[2]string{b.Model.Name(), engine.Name()}]; ok {
+		return h.AsX
+	}
+	return WLD{}
+}
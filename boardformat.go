@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Board formats for -board-format: how the built-in prompts show a
+// tic-tac-toe board, to see which one models read most reliably
+const (
+	BoardGrid        = "grid"   // an ASCII grid, empty cells showing their position number
+	BoardJSON        = "json"   // a JSON object of each position's mark
+	BoardCoordinates = "coords" // each player's marks, and the empty cells, as (row, column)
+	BoardText        = "text"   // a sentence about each row
+)
+
+// BoardFormats are the -board-format values
+var BoardFormats = []string{BoardGrid, BoardJSON, BoardCoordinates, BoardText}
+
+// promptBoardFormat is how the built-in prompts show tic-tac-toe boards,
+// set by -board-format
+var promptBoardFormat = BoardGrid
+
+// FormatBoard shows board in one of the BoardFormats, ending in a newline
+func FormatBoard(board Board, format string) string {
+	var out strings.Builder
+	switch format {
+	case BoardJSON:
+		out.WriteString("{")
+		for position := 0; position < 9; position++ {
+			if position > 0 {
+				out.WriteString(", ")
+			}
+			mark := board[position/3][position%3]
+			if mark == Empty {
+				mark = ""
+			}
+			fmt.Fprintf(&out, "\"%d\": %q", position, mark)
+		}
+		out.WriteString("}\n")
+	case BoardCoordinates:
+		for _, mark := range []string{PlayerX, PlayerO, Empty} {
+			var cells []string
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					if board[i][j] == mark {
+						cells = append(cells, fmt.Sprintf("(%d,%d)", i, j))
+					}
+				}
+			}
+			label := mark
+			if mark == Empty {
+				label = "Empty"
+			}
+			if len(cells) == 0 {
+				cells = []string{"none"}
+			}
+			fmt.Fprintf(&out, "%s: %s\n", label, strings.Join(cells, ", "))
+		}
+	case BoardText:
+		rows := []string{"top", "middle", "bottom"}
+		columns := []string{"left", "middle", "right"}
+		for i, row := range rows {
+			var cells []string
+			for j, column := range columns {
+				cell := fmt.Sprintf("the %s square (position %d)", column, i*3+j)
+				if board[i][j] == Empty {
+					cells = append(cells, cell+" is empty")
+				} else {
+					cells = append(cells, fmt.Sprintf("%s has %s", cell, board[i][j]))
+				}
+			}
+			fmt.Fprintf(&out, "In the %s row, %s, %s, and %s.\n", row, cells[0], cells[1], cells[2])
+		}
+	default:
+		out.WriteString("-------------\n")
+		for i := 0; i < 3; i++ {
+			out.WriteString("| ")
+			for j := 0; j < 3; j++ {
+				if board[i][j] == Empty {
+					out.WriteString(fmt.Sprintf("%d ", i*3+j))
+				} else {
+					out.WriteString(fmt.Sprintf("%s ", board[i][j]))
+				}
+				out.WriteString("| ")
+			}
+			out.WriteString("\n-------------\n")
+		}
+	}
+	return out.String()
+}
+
+// hasTicTacToeBoard reports whether the built-in prompts show game's board
+// as a tic-tac-toe board, the only kind -board-format changes
+func hasTicTacToeBoard(game Game) bool {
+	switch g := game.(type) {
+	case *TicTacToe, *InfiniteTicTacToe:
+		return true
+	case *Notakto:
+		return len(g.Boards) == 1
+	case *SwapGame:
+		return hasTicTacToeBoard(g.Game)
+	}
+	return false
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+	"unicode/utf8"
+)
+
+// Bracket slots that don't hold a player
+const (
+	byeSlot  = -1 // no opponent, so the other player goes straight through
+	openSlot = -2 // the match that fills it hasn't been played
+)
+
+// suddenDeathGames is how many single games a tied elimination match adds,
+// alternating X, before the higher seed goes through on seeding
+const suddenDeathGames = 4
+
+// Bracket is the record of an elimination tournament
+type Bracket struct {
+	Winners  [][]int // Winners[0] is the seeded draw, Winners[k] the winners of round k
+	Results  []BracketResult
+	Champion int
+	RunnerUp int
+}
+
+// BracketResult is one decided match
+type BracketResult struct {
+	Stage          string
+	Winner, Loser  int
+	Score          string // winner's points first
+	DecidedBySeeds bool
+}
+
+// NewBracketTournament creates a knockout, or with double set a
+// double-elimination tournament, seeded in the order of players. Each match
+// is Games games with each player as X.
+func NewBracketTournament(players []Agent, games int, double bool) *Tournament {
+	t := NewTournament(players, games)
+	t.Format = FormatKnockout
+	if double {
+		t.Format = FormatDoubleElimination
+	}
+	return t
+}
+
+// seedOrder returns the seeds (0 for the top seed) of a bracket of size
+// slots, in draw order, so that the top seeds can only meet late: 0 plays
+// size-1, and the winner of that meets the winner of size/2-1 and size/2
+func seedOrder(size int) []int {
+	order := []int{0}
+	for len(order) < size {
+		n := len(order) * 2
+		var next []int
+		for _, seed := range order {
+			next = append(next, seed, n-1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// roundName names round r of a winners bracket with rounds rounds
+func roundName(r, rounds int) string {
+	switch rounds - r {
+	case 0:
+		return "Final"
+	case 1:
+		return "Semi-final"
+	case 2:
+		return "Quarter-final"
+	}
+	return fmt.Sprintf("Round %d", r)
+}
+
+// runBracket plays the bracket. In double elimination, a player's first
+// loss drops them into the losers bracket, whose winner meets the winners
+// bracket champion in a grand final, replayed if the champion loses it.
+func (t *Tournament) runBracket(play playFunc) {
+	size := 2
+	for size < len(t.Players) {
+		size *= 2
+	}
+	first := make([]int, size)
+	for i, seed := range seedOrder(size) {
+		first[i] = byeSlot
+		if seed < len(t.Players) {
+			first[i] = seed
+		}
+	}
+	b := &Bracket{Winners: [][]int{first}, Champion: openSlot, RunnerUp: openSlot}
+	t.bracket = b
+	double := t.Format == FormatDoubleElimination
+	rounds := bits.Len(uint(size)) - 1
+
+	var alive []int // losers bracket survivors
+	losersRound := 0
+	playLosers := func(pairs [][2]int) (stopped bool) {
+		losersRound++
+		for _, pair := range pairs {
+			if pair[0] >= 0 && pair[1] >= 0 {
+				fmt.Fprintf(t.out(), "\n========== Losers' round %d ==========\n", losersRound)
+				break
+			}
+		}
+		alive = nil
+		for n, pair := range pairs {
+			stage := fmt.Sprintf("Losers' round %d, match %d of %d", losersRound, n+1, len(pairs))
+			winner, _, stopped := t.bracketMatch(pair[0], pair[1], stage, play)
+			if stopped {
+				return true
+			}
+			alive = append(alive, winner)
+		}
+		return false
+	}
+
+	for r := 1; r <= rounds; r++ {
+		prev := b.Winners[r-1]
+		next := make([]int, len(prev)/2)
+		for i := range next {
+			next[i] = openSlot
+		}
+		b.Winners = append(b.Winners, next)
+		name := roundName(r, rounds)
+		if double {
+			name = "Winners' " + strings.ToLower(name[:1]) + name[1:]
+		}
+		fmt.Fprintf(t.out(), "\n========== %s ==========\n", name)
+		var losers []int
+		for i := range next {
+			stage := fmt.Sprintf("%s, match %d of %d", name, i+1, len(next))
+			if len(next) == 1 {
+				stage = name
+			}
+			winner, loser, stopped := t.bracketMatch(prev[2*i], prev[2*i+1], stage, play)
+			if stopped {
+				return
+			}
+			next[i] = winner
+			losers = append(losers, loser)
+		}
+		if !double {
+			if r == rounds {
+				b.Champion, b.RunnerUp = next[0], losers[0]
+			}
+			continue
+		}
+
+		// Losers of the first round play each other; later losers drop in
+		// against the survivors, in reverse order to put off rematches,
+		// and the survivors then play each other down to the next drop
+		var pairs [][2]int
+		if r == 1 {
+			if len(losers) == 1 {
+				alive = losers
+				continue
+			}
+			for i := 0; i < len(losers); i += 2 {
+				pairs = append(pairs, [2]int{losers[i], losers[i+1]})
+			}
+			if playLosers(pairs) {
+				return
+			}
+			continue
+		}
+		for i, survivor := range alive {
+			pairs = append(pairs, [2]int{survivor, losers[len(losers)-1-i]})
+		}
+		if playLosers(pairs) {
+			return
+		}
+		if r < rounds {
+			pairs = nil
+			for i := 0; i < len(alive); i += 2 {
+				pairs = append(pairs, [2]int{alive[i], alive[i+1]})
+			}
+			if playLosers(pairs) {
+				return
+			}
+		}
+	}
+	if !double {
+		return
+	}
+
+	fmt.Fprintln(t.out(), "\n========== Grand final ==========")
+	champion, challenger := b.Winners[rounds][0], alive[0]
+	winner, loser, stopped := t.bracketMatch(champion, challenger, "Grand final", play)
+	if stopped {
+		return
+	}
+	if winner == challenger {
+		// The winners bracket champion has now lost once too
+		fmt.Fprintln(t.out(), "\n========== Grand final reset ==========")
+		if winner, loser, stopped = t.bracketMatch(challenger, champion, "Grand final reset", play); stopped {
+			return
+		}
+	}
+	b.Champion, b.RunnerUp = winner, loser
+}
+
+// bracketMatch plays an elimination match between a and b, either of which
+// may be a bye. A match tied after Games games each way goes to sudden
+// death, then to the higher seed.
+func (t *Tournament) bracketMatch(a, b int, stage string, play playFunc) (winner, loser int, stopped bool) {
+	switch {
+	case a == byeSlot:
+		return b, a, false
+	case b == byeSlot:
+		return a, b, false
+	}
+	startA, startB := t.points[a][b], t.points[b][a]
+	score := func() (float64, float64) { return t.points[a][b] - startA, t.points[b][a] - startB }
+	if play(Pairing{X: a, O: b}, stage, t.Games) || play(Pairing{X: b, O: a}, stage+", return", t.Games) {
+		return openSlot, openSlot, true
+	}
+	for game := 0; game < suddenDeathGames; game++ {
+		if pa, pb := score(); pa != pb {
+			break
+		}
+		pairing := Pairing{X: a, O: b}
+		if game%2 == 1 {
+			pairing = Pairing{X: b, O: a}
+		}
+		if play(pairing, fmt.Sprintf("%s, sudden death %d", stage, game+1), 1) {
+			return openSlot, openSlot, true
+		}
+	}
+
+	pa, pb := score()
+	winner, loser = a, b
+	if pb > pa || (pa == pb && b < a) {
+		winner, loser, pa, pb = b, a, pb, pa
+	}
+	result := BracketResult{Stage: stage, Winner: winner, Loser: loser, Score: formatPoints(pa) + "-" + formatPoints(pb), DecidedBySeeds: pa == pb}
+	t.bracket.Results = append(t.bracket.Results, result)
+	if result.DecidedBySeeds {
+		fmt.Fprintf(t.out(), "🏆 %s: %s and %s tie %s; %s goes through as the higher seed\n", stage, t.Players[a].Name(), t.Players[b].Name(), result.Score, t.Players[winner].Name())
+	} else {
+		fmt.Fprintf(t.out(), "🏆 %s: %s beats %s %s\n", stage, t.Players[winner].Name(), t.Players[loser].Name(), result.Score)
+	}
+	return winner, loser, false
+}
+
+// slotLabel names a bracket slot for the tree
+func (t *Tournament) slotLabel(slot int, seeded bool) string {
+	switch slot {
+	case byeSlot:
+		return "bye"
+	case openSlot:
+		return "?"
+	}
+	name := shortName(t.Players[slot].Name(), 16)
+	if seeded {
+		return fmt.Sprintf("(%d) %s", slot+1, name)
+	}
+	return name
+}
+
+// PrintBracket prints the winners bracket as a tree, then the other
+// matches of a double elimination and the final placings
+func (t *Tournament) PrintBracket(w io.Writer) {
+	b := t.bracket
+	if b == nil {
+		return
+	}
+	var rounds [][]string
+	for k, slots := range b.Winners {
+		var labels []string
+		for _, slot := range slots {
+			labels = append(labels, t.slotLabel(slot, k == 0))
+		}
+		rounds = append(rounds, labels)
+	}
+	if t.Format == FormatDoubleElimination {
+		fmt.Fprintln(w, "Winners bracket:")
+	} else {
+		fmt.Fprintln(w, "Bracket:")
+	}
+	printBracketTree(w, rounds)
+
+	if t.Format == FormatDoubleElimination {
+		fmt.Fprintln(w, "Losers bracket and grand final:")
+		for _, result := range b.Results {
+			if strings.HasPrefix(result.Stage, "Winners'") {
+				continue
+			}
+			fmt.Fprintf(w, "  %-36s %s beat %s %s\n", result.Stage, t.Players[result.Winner].Name(), t.Players[result.Loser].Name(), result.Score)
+		}
+	}
+	if b.Champion >= 0 {
+		fmt.Fprintf(w, "🏆 Champion:  %s\n", t.Players[b.Champion].Name())
+		if b.RunnerUp >= 0 {
+			fmt.Fprintf(w, "🥈 Runner-up: %s\n", t.Players[b.RunnerUp].Name())
+		}
+	} else {
+		fmt.Fprintln(w, "The bracket wasn't finished")
+	}
+}
+
+// printBracketTree draws rounds of a single-elimination bracket side by
+// side: rounds[0] holds the draw, and each later round one label per match
+// of the round before, drawn level with the two slots that played it
+func printBracketTree(w io.Writer, rounds [][]string) {
+	width := 0
+	for _, labels := range rounds {
+		for _, label := range labels {
+			width = max(width, utf8.RuneCountInString(label))
+		}
+	}
+	colWidth := width + 5
+	grid := make([][]rune, 2*len(rounds[0])-1)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", colWidth*len(rounds)))
+	}
+
+	var rowOf []int
+	for k, labels := range rounds {
+		rows := make([]int, len(labels))
+		x := k * colWidth
+		connector := x + width + 2
+		for i, label := range labels {
+			if k == 0 {
+				rows[i] = 2 * i
+			} else {
+				rows[i] = (rowOf[2*i] + rowOf[2*i+1]) / 2
+			}
+			line := grid[rows[i]]
+			n := copy(line[x:], []rune(label))
+			if k < len(rounds)-1 {
+				for c := x + n + 1; c < connector; c++ {
+					line[c] = '─'
+				}
+			}
+		}
+		if k > 0 {
+			prevConnector := (k-1)*colWidth + width + 2
+			for i, row := range rows {
+				top, bottom := rowOf[2*i], rowOf[2*i+1]
+				grid[top][prevConnector] = '┐'
+				grid[bottom][prevConnector] = '┘'
+				for r := top + 1; r < bottom; r++ {
+					grid[r][prevConnector] = '│'
+				}
+				grid[row][prevConnector] = '├'
+				grid[row][prevConnector+1] = '─'
+			}
+		}
+		rowOf = rows
+	}
+	for _, line := range grid {
+		fmt.Fprintln(w, "  "+strings.TrimRight(string(line), " "))
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// ErrCircuitOpen is returned once a backend has failed too many times in a row
+// and no fallback is configured
+var ErrCircuitOpen = errors.New("circuit breaker open: backend failed too many times in a row")
+
+// CircuitBreaker stops sending requests to a backend after Threshold
+// consecutive failures. Once open it sends everything to Fallback, using
+// FallbackModel when set, for the rest of the run; without a fallback every
+// request fails immediately with ErrCircuitOpen.
+type CircuitBreaker struct {
+	Backend       Backend
+	Threshold     int
+	Fallback      Backend // optional
+	FallbackModel string  // optional; empty keeps the request's model
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+}
+
+// Generate sends the request to the backend, or to the fallback once the circuit is open
+func (c *CircuitBreaker) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	if c.isOpen() {
+		if c.Fallback == nil {
+			return LLMResponse{}, ErrCircuitOpen
+		}
+		req.Model = orDefault(c.FallbackModel, req.Model)
+		return c.Fallback.Generate(ctx, req)
+	}
+
+	resp, err := c.Backend.Generate(ctx, req)
+	c.record(err)
+	return resp, err
+}
+
+// Tripped reports whether the circuit is open with nowhere to fall back to,
+// meaning further games can only fail
+func (c *CircuitBreaker) Tripped() bool {
+	return c.isOpen() && c.Fallback == nil
+}
+
+func (c *CircuitBreaker) isOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.open
+}
+
+// record counts consecutive failures and opens the circuit at the threshold
+func (c *CircuitBreaker) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.failures = 0
+		return
+	}
+	// A cancelled request says nothing about the backend
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	c.failures++
+	if c.failures < c.Threshold || c.open {
+		return
+	}
+	c.open = true
+	if c.Fallback == nil {
+		slog.Warn(fmt.Sprintf("Circuit breaker open after %d consecutive backend failures", c.failures))
+	} else {
+		slog.Warn(fmt.Sprintf("Circuit breaker open after %d consecutive backend failures; switching to fallback %s for the rest of the run",
+			c.failures, orDefault(c.FallbackModel, "backend")))
+	}
+}
+
+// Endpoints returns the number of endpoints behind the primary backend
+func (c *CircuitBreaker) Endpoints() int {
+	return endpointCount(c.Backend)
+}
+
+// ListModels lists the primary backend's models, when it can
+func (c *CircuitBreaker) ListModels(ctx context.Context) ([]string, error) {
+	lister, ok := c.Backend.(ModelLister)
+	if !ok {
+		return nil, errCannotListModels
+	}
+	return lister.ListModels(ctx)
+}
+
+// PullModel pulls on the primary backend, when it can
+func (c *CircuitBreaker) PullModel(ctx context.Context, model string, progress func(status string, completed, total int64)) error {
+	puller, ok := c.Backend.(ModelPuller)
+	if !ok {
+		return fmt.Errorf("backend cannot pull models")
+	}
+	return puller.PullModel(ctx, model, progress)
+}
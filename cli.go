@@ -0,0 +1,224 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Subcommand is a way of running the program, "llama-tac-toe NAME [flags]",
+// with the flags it takes
+type Subcommand struct {
+	Name    string
+	Args    string // what comes after the flags, e.g. FILE, if anything
+	Summary string
+	Flags   []string // the only flags it takes besides generalFlags, if set
+}
+
+// subcommands are the ways of running the program; the first, play, is
+// the default without a subcommand
+var subcommands = []Subcommand{
+	{Name: "play", Summary: "Play games between two players, LLMs, engines, or people"},
+	{Name: "tournament", Summary: "Play a tournament between -models"},
+	{Name: "abtest", Summary: "Play one model with each of -prompts against the same opponent and compare the prompts"},
+	{Name: "serve", Summary: "Play while showing the game live on a web page at -web"},
+	{Name: "grpc", Summary: "Serve a gRPC API at -grpc-addr that plays the matches asked for"},
+	{Name: "matchmaker", Summary: "Pair remote players that join over HTTP at -match-addr into games"},
+	{Name: "join", Summary: "Play player X's model in the games a -matchmaker pairs it into"},
+	{Name: "bench", Summary: "Benchmark player X's model against each of the reference -engines"},
+	{Name: "puzzle", Summary: "Score player X's model on built-in tic-tac-toe puzzles", Flags: puzzleFlags},
+	{Name: "leaderboard", Summary: "Print the -leaderboard, for one -game if it's given", Flags: []string{"leaderboard", "game", "variant"}},
+	{Name: "replay", Args: "FILE", Summary: "Step through a game saved with -replays", Flags: []string{"delay"}},
+}
+
+// puzzleFlags are the flags of the puzzle subcommand: player X's backend,
+// model, and prompt settings, without any of a game's
+var puzzleFlags = []string{
+	"passes", "dry-run", "list-backends", "check-models", "auto-pull", "warmup", "debug",
+	"backend", "backend-x", "url", "url-x", "api-key", "api-key-x", "header", "azure-deployment", "azure-api-version", "aws-region",
+	"keep-alive", "balance", "proxy", "ca-cert", "insecure-tls", "max-idle-conns", "timeout", "move-timeout", "http-retries", "http-backoff",
+	"rpm", "tpm", "price-in", "price-out", "max-cost", "breaker-threshold", "fallback-model", "fallback-url", "fallback-backend", "fallback-api-key",
+	"model", "model-x", "system-x", "persona-x", "options-x", "temperature", "top-p", "top-k", "seed", "max-tokens",
+	"agent", "votes", "tools", "json", "cot", "grammar", "logit-bias", "stream", "chat", "examples", "hints", "board-format", "prompt-lang", "prompt-template",
+}
+
+// generalFlags are taken by every subcommand
+var generalFlags = []string{"config", "profile", "v", "q", "log-file", "no-color"}
+
+// subcommandOnly holds the flags that only some subcommands take, and
+// which; the subcommands without their own Flags take every other flag
+var subcommandOnly = map[string][]string{
+	"models":          {"tournament"},
+	"format":          {"tournament"},
+	"rounds":          {"tournament"},
+	"prompts":         {"abtest"},
+	"engines":         {"bench"},
+	"passes":          {"puzzle"},
+	"web":             {"serve"},
+	"grpc-addr":       {"grpc"},
+	"match-addr":      {"matchmaker"},
+	"match-timeout":   {"matchmaker"},
+	"matchmaker":      {"join"},
+	"delay":           {"replay"},
+	"tui":             {"play"},
+	"human":           {"play", "serve"},
+	"series":          {"play", "serve"},
+	"dashboard":       {"play", "serve"},
+	"discord-play":    {"play", "serve"},
+	"twitch":          {"play", "serve"},
+	"twitch-nick":     {"play", "serve"},
+	"twitch-token":    {"play", "serve"},
+	"twitch-window":   {"play", "serve"},
+	"concurrency":     {"play"},
+	"overlay":         {"play", "serve"},
+	"discord":         {"play", "serve"},
+	"discord-channel": {"play", "serve"},
+	"discord-token":   {"play", "serve"},
+	"opponent":        {"play", "serve", "abtest"},
+	"model-o":         {"play", "serve", "abtest"},
+	"backend-o":       {"play", "serve", "abtest"},
+	"url-o":           {"play", "serve", "abtest"},
+	"api-key-o":       {"play", "serve", "abtest"},
+	"system-o":        {"play", "serve", "abtest"},
+	"persona-o":       {"play", "serve", "abtest"},
+	"options-o":       {"play", "serve", "abtest"},
+	"model-x":         {"play", "serve", "abtest", "join", "bench", "puzzle"},
+	"time":            {"play", "serve", "tournament", "abtest", "join", "bench"},
+	"increment":       {"play", "serve", "tournament", "abtest", "join", "bench"},
+}
+
+// commandLine holds the flags given on the command line
+var commandLine = map[string]bool{}
+
+// takes reports whether the subcommand takes the named flag
+func (s Subcommand) takes(name string) bool {
+	if slices.Contains(generalFlags, name) {
+		return true
+	}
+	if s.Flags != nil {
+		return slices.Contains(s.Flags, name)
+	}
+	owners, ok := subcommandOnly[name]
+	return !ok || slices.Contains(owners, s.Name)
+}
+
+// FlagSet is the subcommand's own flag set: the flags it takes, sharing
+// their values with the global ones so settings from the environment and
+// -config files land in the same place
+func (s Subcommand) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(s.Name, flag.ContinueOnError)
+	flag.VisitAll(func(f *flag.Flag) {
+		if s.takes(f.Name) {
+			fs.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	return fs
+}
+
+// IgnoreOtherFlags puts the flags the subcommand doesn't take back to their
+// defaults, so that settings from the environment or a -config file meant
+// for other kinds of run don't change this one
+func (s Subcommand) IgnoreOtherFlags() {
+	flag.VisitAll(func(f *flag.Flag) {
+		if s.takes(f.Name) || f.Value.String() == f.DefValue {
+			return
+		}
+		switch value := f.Value.(type) {
+		case headerFlags:
+			clear(value)
+		case optionFlags:
+			clear(value)
+		default:
+			value.Set(f.DefValue)
+		}
+		delete(configured, f.Name)
+	})
+}
+
+// PrintUsage prints how to run the subcommand and its flags, and for play,
+// the default, the other subcommands
+func (s Subcommand) PrintUsage(w io.Writer) {
+	args := ""
+	if s.Args != "" {
+		args = " " + s.Args
+	}
+	if s.Name == subcommands[0].Name {
+		fmt.Fprintf(w, "Usage: llama-tac-toe [SUBCOMMAND] [flags]\n\nSubcommands:\n")
+		for _, sub := range subcommands {
+			fmt.Fprintf(w, "  %-12s %s\n", sub.Name, sub.Summary)
+		}
+		fmt.Fprintf(w, "\nRun \"llama-tac-toe SUBCOMMAND -h\" for a subcommand's flags. Without one, llama-tac-toe plays:\n\n")
+	} else {
+		fmt.Fprintf(w, "Usage: llama-tac-toe %s [flags]%s\n\n%s.\n\n", s.Name, args, s.Summary)
+	}
+	fmt.Fprintf(w, "Flags:\n")
+	fs := s.FlagSet()
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
+
+// ParseCommandLine finds the subcommand args start with, play if they
+// don't, and parses its flags, which may come between its arguments too. It
+// returns the subcommand and its arguments, or flag.ErrHelp once it has
+// printed the usage asked for.
+func ParseCommandLine(args []string) (Subcommand, []string, error) {
+	sub := subcommands[0]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		i := slices.IndexFunc(subcommands, func(s Subcommand) bool { return s.Name == args[0] })
+		if i == -1 {
+			return sub, nil, fmt.Errorf("unknown subcommand %q (see \"llama-tac-toe -h\")", args[0])
+		}
+		sub, args = subcommands[i], args[1:]
+	}
+	fs := sub.FlagSet()
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				sub.PrintUsage(os.Stdout)
+				return sub, nil, err
+			}
+			if name, ok := strings.CutPrefix(err.Error(), "flag provided but not defined: -"); ok && flag.Lookup(name) != nil {
+				var owners []string
+				for _, s := range subcommands {
+					if s.takes(name) {
+						owners = append(owners, s.Name)
+					}
+				}
+				kind := "subcommand"
+				if len(owners) > 1 {
+					kind = "subcommands"
+				}
+				return sub, nil, fmt.Errorf("-%s only applies to the %s %s, not %s", name, joinOr(owners), kind, sub.Name)
+			}
+			return sub, nil, fmt.Errorf("%w (see \"llama-tac-toe %s -h\")", err, sub.Name)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional, args = append(positional, fs.Arg(0)), fs.Args()[1:]
+	}
+	fs.Visit(func(f *flag.Flag) { commandLine[f.Name] = true })
+	if sub.Args == "" && len(positional) > 0 {
+		return sub, nil, fmt.Errorf("unexpected argument %q: the %s subcommand only takes flags", positional[0], sub.Name)
+	}
+	return sub, positional, nil
+}
+
+// joinOr joins names as "a", "a or b", or "a, b, or c"
+func joinOr(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " or " + names[1]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", or " + names[len(names)-1]
+}
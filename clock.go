@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock is a chess clock for both players. Only time spent waiting on the
+// LLM is charged, so engines and humans never run out; each move made adds
+// the increment, and a player whose time runs out loses.
+type Clock struct {
+	Initial   time.Duration
+	Increment time.Duration
+
+	mu        sync.Mutex
+	remaining map[string]time.Duration
+}
+
+// NewClock creates a clock giving each player initial time plus increment per move
+func NewClock(initial, increment time.Duration) *Clock {
+	c := &Clock{Initial: initial, Increment: increment}
+	c.Reset()
+	return c
+}
+
+// Reset gives both players their initial time, for a new game
+func (c *Clock) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remaining = map[string]time.Duration{PlayerX: c.Initial, PlayerO: c.Initial}
+}
+
+// Charge takes elapsed time off player's clock, reporting whether it ran out
+func (c *Clock) Charge(player string, elapsed time.Duration) (expired bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remaining[player] -= elapsed
+	return c.remaining[player] <= 0
+}
+
+// Moved adds the increment to player's clock after a move
+func (c *Clock) Moved(player string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remaining[player] += c.Increment
+}
+
+// Remaining returns player's time left, never below zero
+func (c *Clock) Remaining(player string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(c.remaining[player], 0)
+}
+
+// String shows both clocks for the console, e.g. "X 52.3s, O 60.0s"
+func (c *Clock) String() string {
+	return fmt.Sprintf("X %.1fs, O %.1fs", c.Remaining(PlayerX).Seconds(), c.Remaining(PlayerO).Seconds())
+}
+
+// Note tells player how much time is left, for the prompt
+func (c *Clock) Note(player string) string {
+	note := fmt.Sprintf("\n⏱️  CLOCK: You have %.1fs left and %s has %.1fs", c.Remaining(player).Seconds(),
+		OtherPlayer(player), c.Remaining(OtherPlayer(player)).Seconds())
+	if c.Increment > 0 {
+		note += fmt.Sprintf(", plus %s per move", c.Increment)
+	}
+	return note + ". If your time runs out you LOSE, so answer promptly.\n"
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// colorBoards is whether boards are printed in color: on a terminal unless
+// -no-color or the NO_COLOR environment variable turns it off
+var colorBoards bool
+
+// ANSI styles for board cells
+const (
+	ansiReset   = "\033[0m"
+	ansiX       = "\033[1;31m"    // bold red
+	ansiO       = "\033[1;34m"    // bold blue
+	ansiLast    = "\033[7m"       // reverse video, added to the mark's color
+	ansiWinning = "\033[1;30;42m" // bold black on green
+)
+
+// ColorDisplay is a game that can print its board in color itself, with the
+// most recent move and a completed line highlighted. Games without it have
+// their marks colored in the text Display prints.
+type ColorDisplay interface {
+	// DisplayColor prints the board to w like Display; last is the most
+	// recent move as played, or -1 before the first
+	DisplayColor(w io.Writer, last int)
+}
+
+// displayGame prints game's board to w, in color if colorBoards is set
+func displayGame(w io.Writer, game Game, history []Move) {
+	if !colorBoards {
+		game.Display(w)
+		return
+	}
+	last := -1
+	if len(history) > 0 {
+		last = history[len(history)-1].Position
+	}
+	displayColor(w, game, last)
+}
+
+// displayColor prints game's board to w in color, last being the most
+// recent move or -1
+func displayColor(w io.Writer, game Game, last int) {
+	if board, ok := game.(ColorDisplay); ok {
+		board.DisplayColor(w, last)
+		return
+	}
+	var board bytes.Buffer
+	game.Display(&board)
+	fmt.Fprint(w, colorMarks(board.String()))
+}
+
+// colorCell returns a cell's text in its mark's color, reversed if it holds
+// the most recent move and on green if it's part of a completed line
+func colorCell(text, mark string, last, winning bool) string {
+	style := ""
+	switch mark {
+	case PlayerX:
+		style = ansiX
+	case PlayerO:
+		style = ansiO
+	}
+	switch {
+	case winning:
+		style = ansiWinning
+	case last && style != "":
+		style += ansiLast
+	}
+	if style == "" {
+		return text
+	}
+	return style + text + ansiReset
+}
+
+// colorMarks colors every X and O standing alone in text, such as a board
+// printed by Display, leaving words like "Board" alone
+func colorMarks(text string) string {
+	var colored strings.Builder
+	letter := func(i int) bool {
+		if i < 0 || i >= len(text) {
+			return false
+		}
+		c := text[i]
+		return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	for i := 0; i < len(text); i++ {
+		mark := string(text[i])
+		if (mark == PlayerX || mark == PlayerO) && !letter(i-1) && !letter(i+1) {
+			colored.WriteString(colorCell(mark, mark, false, false))
+			continue
+		}
+		colored.WriteByte(text[i])
+	}
+	return colored.String()
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
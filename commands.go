@@ -0,0 +1,879 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Each subcommand's run function validates the flags it takes and runs it.
+// Flags it doesn't take are back at their defaults (see IgnoreOtherFlags).
+
+// runPlay plays -games games, or matches with -series, between two players
+func runPlay(f *cliFlags) {
+	if *f.concurrency < 1 {
+		fmt.Printf("Invalid -concurrency value %d: must be 1 or more\n", *f.concurrency)
+		os.Exit(2)
+	}
+	if *f.concurrency > 1 && (*f.human != "" || *f.seriesLength > 0 || *f.timeControl > 0 || *f.stream) {
+		fmt.Println("-concurrency can't be used with -human, -series, -time, or -stream")
+		os.Exit(2)
+	}
+	if *f.tuiMode && (*f.human != "" || *f.seriesLength > 0 || *f.stream || *f.showDashboard || *f.concurrency > 1 || *f.outputFormat == "json") {
+		fmt.Println("-tui can't be used with -human, -series, -stream, -dashboard, -concurrency, or -output json")
+		os.Exit(2)
+	}
+	if (*f.discordWebhook != "" || *f.discordChannel != "" || *f.overlayDir != "") && (*f.tuiMode || *f.concurrency > 1) {
+		fmt.Println("Discord posts and -overlay show one game at a time and can't be used with -tui or -concurrency")
+		os.Exit(2)
+	}
+	playGames(f, "play", "", *f.tuiMode, *f.concurrency)
+}
+
+// runServe plays like runPlay, one game at a time, while showing the game
+// live on a web page at -web
+func runServe(f *cliFlags) {
+	playGames(f, "serve", *f.webAddr, false, 1)
+}
+
+// playGames plays the games of the play and serve subcommands: with
+// serveAddr set, shown live on a web page there, with tui in the terminal
+// interface, and with concurrency above 1 several at a time
+func playGames(f *cliFlags, name, serveAddr string, tui bool, concurrency int) {
+	*f.human = strings.ToUpper(*f.human)
+	if *f.human != "" && *f.human != PlayerX && *f.human != PlayerO {
+		fmt.Printf("Invalid -human value %q: must be X or O\n", *f.human)
+		os.Exit(2)
+	}
+	if *f.showDashboard && (*f.human != "" || *f.stream || *f.debug) {
+		fmt.Println("-dashboard can't be used with -human, -stream, or -debug")
+		os.Exit(2)
+	}
+	var discord *Discord
+	var err error
+	if *f.discordWebhook != "" || *f.discordChannel != "" {
+		if discord, err = NewDiscord(*f.discordWebhook, *f.discordChannel, orDefault(*f.discordToken, os.Getenv("DISCORD_BOT_TOKEN"))); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	if *f.discordPlay && (*f.human == "" || *f.discordChannel == "") {
+		fmt.Println("-discord-play needs -human for the side the channel plays and -discord-channel for where")
+		os.Exit(2)
+	}
+	var twitch *TwitchChat
+	if *f.twitchChannel != "" {
+		if *f.human == "" || *f.discordPlay {
+			fmt.Println("-twitch needs -human for the side chat plays, and can't be used with -discord-play")
+			os.Exit(2)
+		}
+		if *f.twitchWindow <= 0 {
+			fmt.Printf("Invalid -twitch-window %s: must be positive\n", *f.twitchWindow)
+			os.Exit(2)
+		}
+		if twitch, err = ConnectTwitch(*f.twitchChannel, *f.twitchNick, orDefault(*f.twitchToken, os.Getenv("TWITCH_OAUTH_TOKEN"))); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	s := newSession(f, name)
+	defer s.close()
+
+	// The LLM plays X and the opponent plays O; a human takes over one side
+	// and faces the opponent on the other
+	opponentLLM := s.playerO
+	if *f.human == PlayerO {
+		opponentLLM = s.playerX
+	}
+	opponent := s.newEngine(*f.opponentName, opponentLLM)
+	agents := map[string]Agent{PlayerX: s.playerX, PlayerO: opponent}
+	if *f.human != "" {
+		if *f.discordPlay {
+			agents[*f.human] = &DiscordAgent{Discord: discord}
+		} else if twitch != nil {
+			agents[*f.human] = &TwitchAgent{Chat: twitch, Window: *f.twitchWindow}
+		} else {
+			agents[*f.human] = &HumanAgent{Reader: bufio.NewReader(os.Stdin), Out: s.out}
+		}
+		agents[OtherPlayer(*f.human)] = opponent
+	}
+
+	fmt.Fprintf(s.out, "=== %s: %s vs %s ===\n", s.newGame().Name(), agentLabel(agents[PlayerX]), agentLabel(agents[PlayerO]))
+	if *f.discordPlay {
+		fmt.Fprintf(s.out, "The Discord channel is playing as: %s\n", *f.human)
+	} else if twitch != nil {
+		fmt.Fprintf(s.out, "Twitch chat of #%s is playing as: %s\n", twitch.Channel, *f.human)
+	} else if *f.human != "" {
+		fmt.Fprintf(s.out, "You are playing as: %s\n", *f.human)
+	}
+	if s.llmX.Model == s.llmO.Model {
+		fmt.Fprintf(s.out, "Using model: %s\n", s.llmX.Model)
+	} else {
+		fmt.Fprintf(s.out, "Using models: %s (X) vs %s (O)\n", s.llmX.Model, s.llmO.Model)
+	}
+	s.printBackends(true)
+	s.printSettings()
+	if *f.games == 0 {
+		fmt.Fprintln(s.out, "Games to play: Unlimited")
+	} else {
+		fmt.Fprintf(s.out, "Games to play: %d\n", *f.games)
+	}
+	if concurrency > 1 {
+		fmt.Fprintf(s.out, "Concurrency: %d games at a time\n", concurrency)
+	}
+
+	// Only check and warm up the models that will actually be asked for
+	// moves, and each model once when both players share it
+	sameModel := s.llmX.Model == s.llmO.Model && s.backendForX == s.backendForO && s.urlForX == s.urlForO
+	var activeLLMs []*LLMAgent
+	if *f.human == "" {
+		activeLLMs = append(activeLLMs, s.llmX)
+	}
+	if *f.opponentName == "llm" {
+		if *f.human == PlayerO {
+			activeLLMs = append(activeLLMs, s.llmX)
+		} else if *f.human != "" || !sameModel {
+			activeLLMs = append(activeLLMs, s.llmO)
+		}
+	}
+	s.prepare(activeLLMs, false)
+
+	s.openRecorders()
+	s.startReport(fmt.Sprintf("%s: %s vs %s", s.newGame().Name(), agents[PlayerX].Name(), agents[PlayerO].Name()),
+		[2]string{"Players", fmt.Sprintf("%s (X, %s) vs %s (O, %s)",
+			agents[PlayerX].Name(), agentLabel(agents[PlayerX]), agents[PlayerO].Name(), agentLabel(agents[PlayerO]))})
+
+	// -games counts matches with -series, whose length isn't known in advance
+	total := *f.games
+	if *f.seriesLength > 0 {
+		total = 0
+	}
+	if *f.showDashboard {
+		s.gameOut = io.Discard
+		s.dashboard = NewDashboard(s.out, fmt.Sprintf("%s: %s (X) vs %s (O)", s.newGame().Name(), agents[PlayerX].Name(), agents[PlayerO].Name()), total)
+	} else if !tui {
+		// A quiet run shows its progress instead of the games
+		s.showProgress(total)
+	}
+
+	var live *Live
+	if serveAddr != "" || *f.overlayDir != "" || discord != nil {
+		live = NewLive()
+	}
+	if serveAddr != "" {
+		if err := ServeWeb(serveAddr, live); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(s.out, "Live board at %s, overlay for OBS at %soverlay\n", webURL(serveAddr), webURL(serveAddr))
+	}
+	var overlay *Overlay
+	if *f.overlayDir != "" {
+		if overlay, err = NewOverlay(*f.overlayDir, live); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(1)
+		}
+	}
+	var discordFeed *DiscordFeed
+	if discord != nil {
+		discordFeed = NewDiscordFeed(discord, live)
+	}
+
+	s.startEvents(agents, nil)
+	var series *Series
+	var matches MatchStats
+	if *f.seriesLength > 0 {
+		series = NewSeries(*f.seriesLength)
+	}
+
+	if tui {
+		title := fmt.Sprintf("%s: %s (X) vs %s (O)", s.newGame().Name(), agents[PlayerX].Name(), agents[PlayerO].Name())
+		panel := NewDashboard(s.out, title, *f.games)
+		console := s.out
+		err := RunTUI(title, *f.debug, func(ui *TUI) {
+			// Messages while the interface is up, such as why the run
+			// stopped, are shown in it through its stdout
+			s.out = os.Stdout
+			tuiAgents := ui.Agents(agents)
+			play := func(out io.Writer, gameNumber int, gameStats *GameStats) GameResult {
+				game := s.newGame()
+				ui.StartGame(gameNumber)
+				s.beforeGame(agents)
+				result := PlayGame(out, game, tuiAgents, s.opening, s.clock, *f.maxRetries, false, gameNumber, gameStats)
+				ui.EndGame(game, result)
+				return result
+			}
+			// One game at a time, with the statistics merged as each finishes
+			PlayConcurrently(1, *f.games, io.Discard, play, s.stats, func(result GameResult) bool {
+				s.afterGame(result, agents)
+				ui.ShowStats(panel.Panel(result, s.stats))
+				return s.stop() || ui.Stopping()
+			})
+		})
+		s.out = console
+		if errors.Is(err, errAbandoned) {
+			fmt.Fprintln(s.out, "Run abandoned in the middle of a game")
+			os.Exit(130)
+		}
+		if err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(1)
+		}
+	} else if concurrency > 1 {
+		play := func(out io.Writer, gameNumber int, gameStats *GameStats) GameResult {
+			s.beforeGame(agents)
+			return PlayGame(out, s.newGame(), agents, s.opening, s.clock, *f.maxRetries, *f.debug, gameNumber, gameStats)
+		}
+		PlayConcurrently(concurrency, *f.games, s.gameOut, play, s.stats, func(result GameResult) bool {
+			s.afterGame(result, agents)
+			return s.stop()
+		})
+	} else {
+		gameNumber := 1
+		matchNumber := 1
+
+		// Game loop
+		for {
+			// Check if we've reached the game or match limit (unless unlimited)
+			if *f.games > 0 && series == nil && gameNumber > *f.games {
+				break
+			}
+			if *f.games > 0 && series != nil && matchNumber > *f.games {
+				break
+			}
+			if series != nil && series.Games == 0 {
+				fmt.Fprintf(s.gameOut, "\n##### Match %d: best of %d #####\n", matchNumber, series.Length)
+			}
+
+			game, playAgents := s.newGame(), agents
+			if live != nil {
+				playAgents = live.Agents(agents)
+				live.StartGame(gameNumber, game, agents)
+			}
+			s.beforeGame(agents)
+			result := PlayGame(s.gameOut, game, playAgents, s.opening, s.clock, *f.maxRetries, *f.debug, gameNumber, s.stats)
+			if live != nil {
+				live.EndGame(game, result)
+			}
+			s.afterGame(result, agents)
+			if series != nil {
+				series.Record(result)
+				slog.Info(fmt.Sprintf("Match %d score: %s (X-O)", matchNumber, series.Score()))
+				if series.Decided() {
+					if winner := series.Winner(); winner == "draw" {
+						slog.Info(fmt.Sprintf("🏆 Match %d is drawn %s", matchNumber, series.Score()))
+					} else {
+						slog.Info(fmt.Sprintf("🏆 Player %s (%s) wins match %d, %s", winner, agents[winner].Name(), matchNumber, series.Score()))
+					}
+					matches.Record(series)
+					series = NewSeries(*f.seriesLength)
+					matchNumber++
+				}
+			}
+
+			if s.stop() {
+				break
+			}
+
+			gameNumber++
+
+			// For unlimited games, allow graceful exit
+			if *f.games == 0 {
+				slog.Info("\nPress Ctrl+C to stop, or the next game will start in 2 seconds...")
+				time.Sleep(2 * time.Second)
+			}
+		}
+	}
+
+	if s.bar != nil {
+		s.bar.Done()
+	}
+	s.printFinalStatistics(agents, opponent, series, matches)
+	s.finish()
+	if s.webhooks != nil {
+		s.webhooks.Summary(s.stats)
+	}
+	if overlay != nil {
+		overlay.Flush()
+	}
+	if discordFeed != nil {
+		discordFeed.Flush()
+	}
+
+	if serveAddr != "" {
+		// Keep the final position up for the audience
+		fmt.Fprintf(s.out, "Still serving the live board at %s; press Ctrl+C to stop\n", webURL(serveAddr))
+		select {}
+	}
+}
+
+// printFinalStatistics prints the results of the play and serve
+// subcommands' games between the agents
+func (s *session) printFinalStatistics(agents map[string]Agent, opponent Agent, series *Series, matches MatchStats) {
+	f, stats := s.f, s.stats
+	fmt.Fprintln(s.out, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(s.out, "FINAL STATISTICS")
+	fmt.Fprintln(s.out, strings.Repeat("=", 50))
+	if *f.seriesLength > 0 {
+		fmt.Fprintf(s.out, "Matches played:     %d (best of %d)\n", matches.Total(), *f.seriesLength)
+		if matches.Total() > 0 {
+			fmt.Fprintf(s.out, "Player X matches:   %d (%s)\n", matches.XWins, agents[PlayerX].Name())
+			fmt.Fprintf(s.out, "Player O matches:   %d (%s)\n", matches.OWins, agents[PlayerO].Name())
+			fmt.Fprintf(s.out, "Drawn matches:      %d\n", matches.Draws)
+			fmt.Fprintf(s.out, "Match scores (X-O): %s\n", strings.Join(matches.Scores, ", "))
+		}
+		if series.Games > 0 {
+			fmt.Fprintf(s.out, "Unfinished match:   %s after %d games\n", series.Score(), series.Games)
+		}
+		fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	}
+	fmt.Fprintf(s.out, "Total games played: %d\n", stats.Total)
+	fmt.Fprintf(s.out, "Player X wins:      %d (%.1f%%)\n", stats.XWins, float64(stats.XWins)/float64(stats.Total)*100)
+	fmt.Fprintf(s.out, "Player O wins:      %d (%.1f%%)\n", stats.OWins, float64(stats.OWins)/float64(stats.Total)*100)
+	fmt.Fprintf(s.out, "Draws:              %d (%.1f%%)\n", stats.Draws, float64(stats.Draws)/float64(stats.Total)*100)
+	if stats.Errors > 0 {
+		fmt.Fprintf(s.out, "Errors:             %d (%.1f%%)\n", stats.Errors, float64(stats.Errors)/float64(stats.Total)*100)
+	}
+	fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	if _, ok := opponent.(*MinimaxAgent); ok && s.perfectPlay() && stats.Total > 0 {
+		// Perfect play never loses from the empty board, so a draw is the best result an LLM can get
+		fmt.Fprintf(s.out, "Against perfect play:\n")
+		fmt.Fprintf(s.out, "  Theoretical draws achieved: %d/%d (%.1f%%)\n", stats.Draws, stats.Total, float64(stats.Draws)/float64(stats.Total)*100)
+		fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	}
+	if _, ok := opponent.(*RandomAgent); ok && stats.Total > 0 && *f.human == "" {
+		llm := stats.Model(agents[PlayerX].Name())
+		fmt.Fprintf(s.out, "Against random play:\n")
+		fmt.Fprintf(s.out, "  LLM wins:   %d (%.1f%%)\n", llm.Wins, float64(llm.Wins)/float64(stats.Total)*100)
+		fmt.Fprintf(s.out, "  LLM losses: %d (%.1f%%)\n", llm.Losses, float64(llm.Losses)/float64(stats.Total)*100)
+		fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	}
+	PrintModelStats(s.out, stats)
+	PrintHeadToHead(s.out, stats)
+	PrintSignificance(s.out, stats)
+	PrintFirstMove(s.out, stats)
+	PrintMoveQuality(s.out, stats)
+	PrintOpenings(s.out, stats, s.newGame())
+	s.printRatings()
+	if *f.agentMode == "hybrid" {
+		fmt.Fprintf(s.out, "Engine overrides:   %d\n", stats.Overrides)
+		for _, player := range []string{PlayerX, PlayerO} {
+			if _, ok := agents[player].(*HybridAgent); ok && agents[PlayerX].Name() != agents[PlayerO].Name() {
+				fmt.Fprintf(s.out, "  %-24s %d\n", agents[player].Name(), stats.Model(agents[player].Name()).Overrides)
+			}
+		}
+		fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	}
+	if stats.ResponseCount > 0 {
+		avgResponseTime := stats.TotalResponseTime / time.Duration(stats.ResponseCount)
+		fmt.Fprintf(s.out, "LLM Response Times:\n")
+		fmt.Fprintf(s.out, "  Total calls:      %d\n", stats.ResponseCount)
+		fmt.Fprintf(s.out, "  Average:          %.2fs\n", avgResponseTime.Seconds())
+		fmt.Fprintf(s.out, "  Min:              %.2fs\n", stats.MinResponseTime.Seconds())
+		fmt.Fprintf(s.out, "  Max:              %.2fs\n", stats.MaxResponseTime.Seconds())
+	}
+	if stats.Usage.Total() > 0 {
+		fmt.Fprintf(s.out, "Token Usage:\n")
+		fmt.Fprintf(s.out, "  Prompt tokens:     %d\n", stats.Usage.PromptTokens)
+		fmt.Fprintf(s.out, "  Completion tokens: %d\n", stats.Usage.CompletionTokens)
+		if agents[PlayerX].Name() != agents[PlayerO].Name() {
+			for _, player := range []string{PlayerX, PlayerO} {
+				name := agents[player].Name()
+				if u := stats.Model(name).Usage; u.Total() > 0 {
+					fmt.Fprintf(s.out, "  %-24s %d tokens", name, u.Total())
+					if *f.priceIn > 0 || *f.priceOut > 0 {
+						fmt.Fprintf(s.out, " ($%.4f)", tokenCost(u, *f.priceIn, *f.priceOut))
+					}
+					fmt.Fprintln(s.out)
+				}
+			}
+		}
+	}
+	s.printCost()
+	if stats.Timeouts > 0 {
+		fmt.Fprintf(s.out, "Timed-out moves:    %d\n", stats.Timeouts)
+		if agents[PlayerX].Name() != agents[PlayerO].Name() {
+			for _, player := range []string{PlayerX, PlayerO} {
+				if m := stats.Model(agents[player].Name()); m.Timeouts > 0 {
+					fmt.Fprintf(s.out, "  %-24s %d\n", agents[player].Name(), m.Timeouts)
+				}
+			}
+		}
+	}
+	if stats.IllegalMoves > 0 || stats.ParseFailures > 0 {
+		fmt.Fprintf(s.out, "Illegal moves:      %d (%.1f%% of answers)\n", stats.IllegalMoves, rate(stats.IllegalMoves, stats.Answers)*100)
+		fmt.Fprintf(s.out, "Parse failures:     %d (%.1f%% of answers)\n", stats.ParseFailures, rate(stats.ParseFailures, stats.Answers)*100)
+		if agents[PlayerX].Name() != agents[PlayerO].Name() {
+			for _, player := range []string{PlayerX, PlayerO} {
+				if m := stats.Model(agents[player].Name()); m.Answers > 0 {
+					fmt.Fprintf(s.out, "  %-24s %.1f%% illegal, %.1f%% unparseable over %d answers\n", agents[player].Name(), m.IllegalRate()*100, m.ParseFailureRate()*100, m.Answers)
+				}
+			}
+		}
+	}
+	if stats.LostOnTime > 0 {
+		fmt.Fprintf(s.out, "Lost on time:       %d\n", stats.LostOnTime)
+	}
+	fmt.Fprintln(s.out, strings.Repeat("=", 50))
+}
+
+// perfectPlay reports whether the minimax engine plays perfectly from the
+// start of every game, so the best anyone can do against it is a draw
+func (s *session) perfectPlay() bool {
+	_, limited := s.newGame().(Evaluator)
+	return !limited && *s.f.minimaxDepth == 0 && *s.f.startPosition == "" && *s.f.randomStart == 0
+}
+
+// runTournament plays a tournament between -models in the -format asked for
+func runTournament(f *cliFlags) {
+	models := parseModels(*f.models)
+	if len(models) < 2 {
+		fmt.Println("The tournament subcommand needs at least two -models, e.g. -models llama3.2,qwen2.5,mistral")
+		os.Exit(2)
+	}
+	if *f.games < 1 {
+		fmt.Printf("Invalid -games value %d: the tournament subcommand plays at least 1 game per pairing and color\n", *f.games)
+		os.Exit(2)
+	}
+	seen := map[string]bool{}
+	for _, name := range models {
+		if seen[name] {
+			fmt.Printf("Model %s is entered in the tournament twice\n", name)
+			os.Exit(2)
+		}
+		seen[name] = true
+	}
+	switch *f.format {
+	case FormatRoundRobin, FormatKnockout, FormatDoubleElimination:
+		if *f.rounds != 0 {
+			fmt.Println("-rounds only applies to -format swiss")
+			os.Exit(2)
+		}
+	case FormatSwiss:
+		if *f.rounds < 0 {
+			fmt.Printf("Invalid -rounds value %d: must be 0 or more\n", *f.rounds)
+			os.Exit(2)
+		}
+		if *f.rounds == 0 {
+			*f.rounds = DefaultSwissRounds(len(models))
+		}
+	default:
+		fmt.Printf("Unknown tournament -format %q: use %s\n", *f.format, strings.Join(TournamentFormats, ", "))
+		os.Exit(2)
+	}
+
+	s := newSession(f, "tournament")
+	defer s.close()
+	// Entrants share player X's backend and settings
+	s.backends[PlayerO] = s.backendForX
+	var entrants []Agent
+	var entrantLLMs []*LLMAgent
+	for _, name := range models {
+		llm, entrant := s.entrant(name)
+		entrants = append(entrants, entrant)
+		entrantLLMs = append(entrantLLMs, llm)
+	}
+
+	fmt.Fprintf(s.out, "=== %s: %s tournament ===\n", s.newGame().Name(), *f.format)
+	fmt.Fprintf(s.out, "Models: %s\n", strings.Join(models, ", "))
+	s.printBackends(false)
+	s.printSettings()
+	switch *f.format {
+	case FormatKnockout, FormatDoubleElimination:
+		fmt.Fprintf(s.out, "Games per match: %d with each model as X, then up to %d sudden-death games if tied\n", *f.games, suddenDeathGames)
+	case FormatSwiss:
+		pairings := *f.rounds * (len(models) / 2) * 2
+		fmt.Fprintf(s.out, "Games to play: %d (%d rounds, %d per pairing and color)\n", pairings**f.games, *f.rounds, *f.games)
+	default:
+		pairings := len(models) * (len(models) - 1)
+		fmt.Fprintf(s.out, "Games to play: %d (%d per pairing and color, %d pairings)\n", pairings**f.games, *f.games, pairings)
+	}
+	s.prepare(entrantLLMs, false)
+
+	s.openRecorders()
+	s.startReport(fmt.Sprintf("%s: %s tournament", s.newGame().Name(), *f.format), [2]string{"Models", strings.Join(models, ", ")})
+	s.showProgress(0)
+	s.startEvents(nil, models)
+
+	tournament := NewTournament(entrants, *f.games)
+	switch *f.format {
+	case FormatSwiss:
+		tournament = NewSwissTournament(entrants, *f.games, *f.rounds)
+	case FormatKnockout, FormatDoubleElimination:
+		tournament = NewBracketTournament(entrants, *f.games, *f.format == FormatDoubleElimination)
+	}
+	tournament.BeforeGame, tournament.AfterGame, tournament.Out = s.beforeGame, s.afterGame, s.gameOut
+	tournament.Run(s.newGame, s.opening, s.clock, *f.maxRetries, *f.debug, s.stats, s.stop)
+	if s.bar != nil {
+		s.bar.Done()
+	}
+	fmt.Fprintln(s.out, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(s.out, "TOURNAMENT RESULTS")
+	fmt.Fprintln(s.out, strings.Repeat("=", 50))
+	tournament.Report(s.out)
+	fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	PrintModelStats(s.out, s.stats)
+	PrintHeadToHead(s.out, s.stats)
+	PrintSignificance(s.out, s.stats)
+	PrintFirstMove(s.out, s.stats)
+	PrintMoveQuality(s.out, s.stats)
+	PrintOpenings(s.out, s.stats, s.newGame())
+	s.printRatings()
+	s.printCost()
+	s.finish()
+	if s.webhooks != nil {
+		s.webhooks.Tournament(s.stats, tournament.Standings())
+	}
+	if s.slack != nil {
+		s.slack.Tournament(*f.format, tournament.Standings())
+	}
+}
+
+// runABTest plays player X's model with each of -prompts against the same
+// opponent and compares the prompts
+func runABTest(f *cliFlags) {
+	prompts := parseModels(*f.promptList)
+	if len(prompts) < 2 {
+		fmt.Println("The abtest subcommand needs at least two -prompts to compare, e.g. -prompts built-in,terse.tmpl")
+		os.Exit(2)
+	}
+	if *f.games < 1 || *f.promptTemplatePath != "" || *f.conversation != "" || countTrue(*f.tools, *f.jsonMoves, *f.cot) > 0 {
+		fmt.Println("The abtest subcommand plays -games games (at least 1) with each of -prompts, which are prompts for plain text answers, " +
+			"and can't be used with -prompt-template, -conversation, -tools, -json, or -cot")
+		os.Exit(2)
+	}
+	seen := map[string]bool{}
+	for _, path := range prompts {
+		if seen[path] {
+			fmt.Printf("Prompt %s is in -prompts twice\n", path)
+			os.Exit(2)
+		}
+		seen[path] = true
+	}
+
+	s := newSession(f, "abtest")
+	defer s.close()
+	opponent := s.newEngine(*f.opponentName, s.playerO)
+	// The variants are player X's model with each prompt
+	var variants []Agent
+	var names []string
+	for _, path := range prompts {
+		llm := *s.llmX
+		llm.Variant = path
+		if path != BuiltInPrompt {
+			var err error
+			llm.Template, err = LoadPromptTemplate(path)
+			if err == nil {
+				_, err = llm.Template.Render(s.newGame(), PlayerX, nil)
+			}
+			if err != nil {
+				fmt.Fprintln(s.out, err)
+				os.Exit(2)
+			}
+		}
+		variant, _ := NewLLMPlayer(*f.agentMode, &llm, s.agentOpts)
+		variants = append(variants, variant)
+		names = append(names, variant.Name())
+	}
+
+	fmt.Fprintf(s.out, "=== %s: prompt A/B test against %s ===\n", s.newGame().Name(), agentLabel(opponent))
+	fmt.Fprintf(s.out, "Prompts: %s\n", strings.Join(prompts, ", "))
+	if s.llmX.Model == s.llmO.Model || *f.opponentName != "llm" {
+		fmt.Fprintf(s.out, "Using model: %s\n", s.llmX.Model)
+	} else {
+		fmt.Fprintf(s.out, "Using models: %s (X) vs %s (O)\n", s.llmX.Model, s.llmO.Model)
+	}
+	s.printBackends(*f.opponentName == "llm")
+	s.printSettings()
+	fmt.Fprintf(s.out, "Games to play: %d (%d with each prompt)\n", len(prompts)**f.games, *f.games)
+	activeLLMs := []*LLMAgent{s.llmX}
+	sameModel := s.llmX.Model == s.llmO.Model && s.backendForX == s.backendForO && s.urlForX == s.urlForO
+	if *f.opponentName == "llm" && !sameModel {
+		activeLLMs = append(activeLLMs, s.llmO)
+	}
+	s.prepare(activeLLMs, false)
+
+	s.openRecorders()
+	s.startReport(fmt.Sprintf("%s: prompt A/B test of %s against %s", s.newGame().Name(), s.llmX.Model, opponent.Name()),
+		[2]string{"Prompts", strings.Join(prompts, ", ")})
+	s.showProgress(len(prompts) * *f.games)
+	s.startEvents(nil, names)
+
+	test := &ABTest{Prompts: prompts, Variants: variants, Opponent: opponent, Games: *f.games}
+	test.BeforeGame, test.AfterGame, test.Out = s.beforeGame, s.afterGame, s.gameOut
+	test.Run(s.newGame, s.opening, s.clock, *f.maxRetries, *f.debug, s.stats, s.stop)
+	if s.bar != nil {
+		s.bar.Done()
+	}
+	fmt.Fprintln(s.out, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(s.out, "PROMPT A/B TEST RESULTS")
+	fmt.Fprintln(s.out, strings.Repeat("=", 50))
+	test.Report(s.out, s.stats)
+	PrintModelStats(s.out, s.stats)
+	PrintFirstMove(s.out, s.stats)
+	PrintMoveQuality(s.out, s.stats)
+	PrintOpenings(s.out, s.stats, s.newGame())
+	s.printRatings()
+	s.printCost()
+	s.finish()
+	if s.webhooks != nil {
+		s.webhooks.Summary(s.stats)
+	}
+}
+
+// runBench plays player X's model against each of the reference -engines
+// and scores it against each
+func runBench(f *cliFlags) {
+	if *f.games < 1 {
+		fmt.Printf("Invalid -games value %d: the bench subcommand plays at least 1 game against each engine\n", *f.games)
+		os.Exit(2)
+	}
+	s := newSession(f, "bench")
+	defer s.close()
+	names := parseModels(*f.engines)
+	if len(names) == 0 {
+		// Every engine that plays the game
+		for _, name := range BenchEngines {
+			if ttt, ok := s.newGame().(*TicTacToe); name == "heuristic" && (!ok || !ttt.Standard()) {
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+	var engines []Agent
+	seen := map[string]bool{}
+	for _, name := range names {
+		if !slices.Contains(BenchEngines, name) {
+			fmt.Fprintf(s.out, "Unknown engine %q in -engines: use %s\n", name, strings.Join(BenchEngines, ", "))
+			os.Exit(2)
+		}
+		if seen[name] {
+			fmt.Fprintf(s.out, "Engine %s is in -engines twice\n", name)
+			os.Exit(2)
+		}
+		seen[name] = true
+		engines = append(engines, s.newEngine(name, nil))
+	}
+
+	fmt.Fprintf(s.out, "=== %s: %s against the engines ===\n", s.newGame().Name(), s.playerX.Name())
+	fmt.Fprintf(s.out, "Engines: %s\n", strings.Join(names, ", "))
+	s.printBackends(false)
+	s.printSettings()
+	fmt.Fprintf(s.out, "Games to play: %d (%d against each engine)\n", len(engines)**f.games, *f.games)
+	s.prepare([]*LLMAgent{s.llmX}, false)
+
+	s.openRecorders()
+	s.startReport(fmt.Sprintf("%s: %s against the engines", s.newGame().Name(), s.playerX.Name()),
+		[2]string{"Engines", strings.Join(names, ", ")})
+	s.showProgress(len(engines) * *f.games)
+	s.startEvents(nil, append([]string{s.playerX.Name()}, names...))
+
+	bench := &Bench{Model: s.playerX, Engines: engines, Games: *f.games}
+	bench.BeforeGame, bench.AfterGame, bench.Out = s.beforeGame, s.afterGame, s.gameOut
+	bench.Run(s.newGame, s.opening, s.clock, *f.maxRetries, *f.debug, s.stats, s.stop)
+	if s.bar != nil {
+		s.bar.Done()
+	}
+	fmt.Fprintln(s.out, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(s.out, "BENCHMARK RESULTS")
+	fmt.Fprintln(s.out, strings.Repeat("=", 50))
+	bench.Report(s.out, s.stats, s.perfectPlay())
+	PrintModelStats(s.out, s.stats)
+	PrintFirstMove(s.out, s.stats)
+	PrintMoveQuality(s.out, s.stats)
+	PrintOpenings(s.out, s.stats, s.newGame())
+	s.printRatings()
+	s.printCost()
+	s.finish()
+	if s.webhooks != nil {
+		s.webhooks.Summary(s.stats)
+	}
+}
+
+// runPuzzle poses the built-in tic-tac-toe puzzles to player X's model
+func runPuzzle(f *cliFlags) {
+	if *f.passes < 1 {
+		fmt.Printf("Invalid -passes value %d: must be 1 or more\n", *f.passes)
+		os.Exit(2)
+	}
+	// The game flags aren't the puzzle subcommand's, so this is standard
+	// tic-tac-toe
+	s := newSession(f, "puzzle")
+	defer s.close()
+
+	fmt.Fprintf(s.out, "=== %s: %s solving puzzles ===\n", s.newGame().Name(), s.playerX.Name())
+	s.printBackends(false)
+	s.printSettings()
+	if *f.passes > 1 {
+		fmt.Fprintf(s.out, "Puzzles to solve: %d (%d puzzles, %d passes)\n", len(Puzzles)**f.passes, len(Puzzles), *f.passes)
+	} else {
+		fmt.Fprintf(s.out, "Puzzles to solve: %d\n", len(Puzzles))
+	}
+	s.prepare([]*LLMAgent{s.llmX}, false)
+
+	s.showProgress(0)
+	answers := SolvePuzzles(s.gameOut, s.playerX, Puzzles, *f.passes, *f.debug, s.stop)
+	if s.bar != nil {
+		s.bar.Done()
+	}
+	fmt.Fprintln(s.out, "\n"+strings.Repeat("=", 50))
+	fmt.Fprintln(s.out, "PUZZLE RESULTS")
+	fmt.Fprintln(s.out, strings.Repeat("=", 50))
+	PrintPuzzleResults(s.out, answers)
+	s.printCost()
+}
+
+// runGRPC serves a gRPC API at -grpc-addr that plays the matches asked for
+func runGRPC(f *cliFlags) {
+	s := newSession(f, "grpc")
+	defer s.close()
+	fmt.Fprintf(s.out, "=== %s: matches over gRPC ===\n", s.newGame().Name())
+	s.printBackends(false)
+	s.printSettings()
+	// Matches' models are checked as they're asked for
+	s.prepare(nil, true)
+	s.openRecorders()
+
+	server := &MatchServer{
+		NewGame:    s.newGame,
+		Opening:    s.opening,
+		MaxRetries: *f.maxRetries,
+		Debug:      *f.debug,
+		Stats:      s.stats,
+		BeforeGame: s.beforeGame,
+		AfterGame:  s.afterGame,
+		Stop:       s.stop,
+		Out:        s.gameOut,
+	}
+	// Models share player X's backend and settings, like tournament entrants
+	server.NewPlayer = func(name string) (Agent, error) {
+		if engine, err := NewOpponent(name, nil, s.agentOpts); err == nil && engine != nil {
+			return engine, nil
+		}
+		llm, player := s.entrant(name)
+		if *f.checkModels {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err := CheckModel(ctx, llm.Backend, llm.Model)
+			cancel()
+			var missing *MissingModelError
+			if errors.As(err, &missing) {
+				return nil, err
+			}
+		}
+		return player, nil
+	}
+	if err := ServeGRPC(*f.grpcAddr, server); err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(s.out, "gRPC API at %s; press Ctrl+C to stop\n", *f.grpcAddr)
+	select {}
+}
+
+// runMatchmaker pairs remote players that join over HTTP at -match-addr
+// into games
+func runMatchmaker(f *cliFlags) {
+	if *f.matchTimeout <= 0 {
+		fmt.Printf("Invalid -match-timeout %s: must be positive\n", *f.matchTimeout)
+		os.Exit(2)
+	}
+	s := newSession(f, "matchmaker")
+	defer s.close()
+	// Remote players bring their own models and backends, and check them
+	fmt.Fprintf(s.out, "=== %s: matchmaking for remote players ===\n", s.newGame().Name())
+	s.printSettings()
+	s.prepare(nil, true)
+	s.openRecorders()
+
+	matchmaker := &Matchmaker{
+		NewGame:    s.newGame,
+		Opening:    s.opening,
+		MaxRetries: *f.maxRetries,
+		Debug:      *f.debug,
+		Stats:      s.stats,
+		Timeout:    *f.matchTimeout,
+		BeforeGame: s.beforeGame,
+		AfterGame:  s.afterGame,
+		Stop:       s.stop,
+		Out:        s.gameOut,
+	}
+	if err := ServeMatchmaker(*f.matchAddr, matchmaker); err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(s.out, "Matchmaker at %s; players join with \"llama-tac-toe join -matchmaker URL\" or POST /players; press Ctrl+C to stop\n", *f.matchAddr)
+	select {}
+}
+
+// runJoin plays player X's model in the games a -matchmaker pairs it into
+func runJoin(f *cliFlags) {
+	if *f.matchmakerURL == "" {
+		fmt.Println("The join subcommand needs the -matchmaker to join")
+		os.Exit(2)
+	}
+	s := newSession(f, "join")
+	defer s.close()
+	fmt.Fprintf(s.out, "=== %s: %s on the matchmaker at %s ===\n", s.newGame().Name(), agentLabel(s.playerX), *f.matchmakerURL)
+	fmt.Fprintf(s.out, "Using model: %s\n", s.llmX.Model)
+	s.printBackends(false)
+	s.printSettings()
+	if *f.games == 0 {
+		fmt.Fprintln(s.out, "Games to play: Unlimited")
+	} else {
+		fmt.Fprintf(s.out, "Games to play: %d\n", *f.games)
+	}
+	s.prepare([]*LLMAgent{s.llmX}, false)
+
+	// The matchmaker plays and records the games; this side only moves
+	if err := JoinMatchmaker(s.out, *f.matchmakerURL, s.playerX, s.newGame, *f.games); err != nil {
+		fmt.Fprintln(s.out, capitalize(err.Error()))
+		os.Exit(1)
+	}
+}
+
+// runLeaderboard prints the -leaderboard, for one -game if it's given
+func runLeaderboard(f *cliFlags) {
+	if *f.leaderboardFile == "" {
+		fmt.Println("The leaderboard subcommand needs a -leaderboard file")
+		os.Exit(2)
+	}
+	newGame, err := NewGame(*f.gameName, *f.variant)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	leaderboard, err := LoadLeaderboard(*f.leaderboardFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	// Show every game unless one was asked for
+	game := ""
+	if flagWasSet("game") || flagWasSet("variant") {
+		game = newGame().Name()
+	}
+	leaderboard.Print(game)
+}
+
+// runReplay steps through a game saved with -replays
+func runReplay(f *cliFlags, args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: llama-tac-toe replay [-delay 1s] FILE")
+		os.Exit(2)
+	}
+	replay, err := LoadReplay(args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	colorBoards = !*f.noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	replay.Play(os.Stdout, os.Stdin, *f.replayDelay)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a -config file's settings, in YAML or TOML. Its keys are flag
+// names and its values the flags' values, e.g. "games: 10"; a list is
+// joined with commas, e.g. a tournament's models, and a map gives key=value
+// pairs to the flags that take them, header and options-x and -o. Any other
+// map is a section grouping settings for readability, e.g. "tournament:"
+// with "models:" and "format:" under it, except "x:" and "o:", which hold a
+// player's settings: "model:" under "x:" is model-x.
+//
+// Named profiles under "profiles:", e.g. "quick-local:" and
+// "cloud-benchmark:", bundle settings in the same form, and the one chosen
+// with -profile overrides the rest of the file.
+type Config struct {
+	Path     string
+	Settings map[string][]string // values by flag name, set in turn
+	sources  map[string]string   // where each setting is from, the file or its profile
+}
+
+// configured holds the flags the environment or a config file set, which
+// the command line didn't, and where they're from
+var configured = map[string]string{}
+
+// envPrefix starts the environment variables that set flags
+const envPrefix = "LLMTTT_"
+
+// envName is the environment variable for the named flag, e.g.
+// LLMTTT_MODEL_X for model-x
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// ApplyEnvironment sets the flags the command line didn't from their
+// environment variables, so they're defaults beneath the flags, and above
+// a config file applied after
+func ApplyEnvironment() error {
+	names := map[string]string{}
+	flag.VisitAll(func(f *flag.Flag) { names[envName(f.Name)] = f.Name })
+	var variables []string
+	for _, variable := range os.Environ() {
+		if key, _, _ := strings.Cut(variable, "="); strings.HasPrefix(key, envPrefix) {
+			variables = append(variables, variable)
+		}
+	}
+	sort.Strings(variables)
+	for _, variable := range variables {
+		key, value, _ := strings.Cut(variable, "=")
+		name, ok := names[key]
+		if !ok {
+			return fmt.Errorf("unknown environment variable %s: variables are named %s and a flag, e.g. %s", key, envPrefix, envName("model-x"))
+		}
+		if commandLine[name] {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		configured[name] = key
+	}
+	return nil
+}
+
+// LoadConfig reads the config file at path, YAML if it ends in .yaml or
+// .yml and TOML if it ends in .toml, with the named profile's settings if
+// profile is set
+func LoadConfig(path, profile string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the config: %w", err)
+	}
+	var raw map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		_, err = toml.Decode(string(data), &raw)
+	default:
+		return nil, fmt.Errorf("unknown config format %q: must be .yaml, .yml, or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	profiles, ok := raw["profiles"].(map[string]any)
+	if _, set := raw["profiles"]; set && !ok {
+		return nil, fmt.Errorf("invalid profiles in %s: must be a map of profile names to settings", path)
+	}
+	delete(raw, "profiles")
+	c := &Config{Path: path, Settings: map[string][]string{}, sources: map[string]string{}}
+	if err := c.add(raw, ""); err != nil {
+		return nil, err
+	}
+	if profile == "" {
+		return c, nil
+	}
+	settings, ok := profiles[profile].(map[string]any)
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("no profile %q in %s (profiles: %s)", profile, path, orDefault(strings.Join(names, ", "), "none"))
+	}
+	chosen := &Config{Path: path + " profile " + profile, Settings: map[string][]string{}, sources: map[string]string{}}
+	if err := chosen.add(settings, ""); err != nil {
+		return nil, err
+	}
+	for name, values := range chosen.Settings {
+		c.Settings[name], c.sources[name] = values, chosen.Path
+	}
+	return c, nil
+}
+
+// add reads a section's settings, suffix naming the player of a player's
+// section, e.g. "-x"
+func (c *Config) add(section map[string]any, suffix string) error {
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := key + suffix
+		value := section[key]
+		if sub, ok := value.(map[string]any); ok && !takesPairs(name) {
+			player := suffix
+			if k := strings.ToLower(key); suffix == "" && (k == "x" || k == "o") {
+				player = "-" + k
+			}
+			if err := c.add(sub, player); err != nil {
+				return err
+			}
+			continue
+		}
+		if flag.Lookup(name) == nil || name == "config" {
+			return fmt.Errorf("unknown setting %q in %s: settings are named like flags, e.g. model-x", name, c.Path)
+		}
+		if _, ok := c.Settings[name]; ok {
+			return fmt.Errorf("%s is set twice in %s", name, c.Path)
+		}
+		values, err := configValues(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s in %s: %w", name, c.Path, err)
+		}
+		c.Settings[name], c.sources[name] = values, c.Path
+	}
+	return nil
+}
+
+// takesPairs reports whether the named flag takes key=value pairs, which a
+// map sets
+func takesPairs(name string) bool {
+	f := flag.Lookup(name)
+	if f == nil {
+		return false
+	}
+	switch f.Value.(type) {
+	case headerFlags, optionFlags:
+		return true
+	}
+	return false
+}
+
+// configValues turns a setting into the values to set its flag to
+func configValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		var pairs []string
+		for key, item := range v {
+			text, err := configScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, key+"="+text)
+		}
+		sort.Strings(pairs)
+		return pairs, nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			text, err := configScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = text
+		}
+		return []string{strings.Join(items, ",")}, nil
+	}
+	text, err := configScalar(value)
+	return []string{text}, err
+}
+
+func configScalar(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case map[string]any, []any:
+		return "", fmt.Errorf("expected a single value, got %v", v)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// Apply sets the flags the command line didn't, so flags given there win
+func (c *Config) Apply() error {
+	names := make([]string, 0, len(c.Settings))
+	for name := range c.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if commandLine[name] {
+			continue
+		}
+		for _, value := range c.Settings[name] {
+			if err := flag.Set(name, value); err != nil {
+				return fmt.Errorf("invalid %s %q in %s: %w", name, value, c.sources[name], err)
+			}
+		}
+		configured[name] = c.sources[name]
+	}
+	return nil
+}
+
+// sensitiveFlags are the flags whose values are secrets, or URLs with them
+var sensitiveFlags = map[string]bool{"header": true, "discord": true, "slack": true, "webhook": true}
+
+// PrintSettings prints the flags in effect that were given or differ from
+// their defaults, with where each was set, hiding secrets
+func PrintSettings(w io.Writer) {
+	flag.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if !flagWasSet(f.Name) && value == f.DefValue {
+			return
+		}
+		source := orDefault(configured[f.Name], "command line")
+		if !flagWasSet(f.Name) {
+			source = "derived from other settings"
+		}
+		if value != "" && (sensitiveFlags[f.Name] || strings.HasSuffix(f.Name, "key") || strings.Contains(f.Name, "key-") || strings.HasSuffix(f.Name, "token")) {
+			value = "(hidden)"
+		}
+		fmt.Fprintf(w, "  -%-22s %-30s %s\n", f.Name, strconv.Quote(value), source)
+	})
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterGame("connect4", func() Game { return NewConnectFour() })
+}
+
+const (
+	connectFourRows = 6
+	connectFourCols = 7
+)
+
+// connectFourLines lists every four-in-a-row as (row, col) cells
+var connectFourLines = func() [][4][2]int {
+	var lines [][4][2]int
+	for row := 0; row < connectFourRows; row++ {
+		for col := 0; col < connectFourCols; col++ {
+			// Right, down, down-right, and down-left from each cell
+			for _, dir := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+				endRow, endCol := row+3*dir[0], col+3*dir[1]
+				if endRow >= connectFourRows || endCol < 0 || endCol >= connectFourCols {
+					continue
+				}
+				var line [4][2]int
+				for i := range line {
+					line[i] = [2]int{row + i*dir[0], col + i*dir[1]}
+				}
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}()
+
+// ConnectFour is played on a 7-column, 6-row grid: pieces drop to the lowest
+// empty cell of the chosen column, and four in a row in any direction wins.
+// Moves are column numbers 1-7.
+type ConnectFour struct {
+	Cells [connectFourRows][connectFourCols]string // row 0 is the top
+}
+
+// NewConnectFour creates a game on an empty grid
+func NewConnectFour() *ConnectFour {
+	c := &ConnectFour{}
+	for row := range c.Cells {
+		for col := range c.Cells[row] {
+			c.Cells[row][col] = Empty
+		}
+	}
+	return c
+}
+
+func (c *ConnectFour) Name() string { return "Connect Four" }
+
+func (c *ConnectFour) Clone() Game {
+	clone := *c
+	return &clone
+}
+
+func (c *ConnectFour) Display(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  1   2   3   4   5   6   7")
+	for _, row := range c.Cells {
+		fmt.Fprintf(w, "| %s |\n", strings.Join(row[:], " | "))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 29))
+	fmt.Fprintln(w)
+}
+
+func (c *ConnectFour) CellGrid() [][]string {
+	grid := make([][]string, connectFourRows)
+	for row := range grid {
+		grid[row] = append([]string(nil), c.Cells[row][:]...)
+	}
+	return grid
+}
+
+// DisplayColor prints the board with the last piece dropped and any four in
+// a row highlighted
+func (c *ConnectFour) DisplayColor(w io.Writer, last int) {
+	winning := map[[2]int]bool{}
+	if line, ok := c.line(); ok {
+		for _, cell := range line {
+			winning[cell] = true
+		}
+	}
+	// The last piece is the top one in the column it was dropped into
+	lastRow := -1
+	for row := 0; row < connectFourRows && last >= 1 && last <= connectFourCols; row++ {
+		if c.Cells[row][last-1] != Empty {
+			lastRow = row
+			break
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  1   2   3   4   5   6   7")
+	for r, row := range c.Cells {
+		cells := make([]string, len(row))
+		for col, mark := range row {
+			cells[col] = colorCell(mark, mark, r == lastRow && col == last-1, winning[[2]int{r, col}])
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 29))
+	fmt.Fprintln(w)
+}
+
+// Legal returns the columns that are not full yet
+func (c *ConnectFour) Legal() []int {
+	var columns []int
+	for col := 0; col < connectFourCols; col++ {
+		if c.Cells[0][col] == Empty {
+			columns = append(columns, col+1)
+		}
+	}
+	return columns
+}
+
+// Play drops player's piece into a column
+func (c *ConnectFour) Play(player string, column int) bool {
+	col := column - 1
+	if col < 0 || col >= connectFourCols {
+		return false
+	}
+	for row := connectFourRows - 1; row >= 0; row-- {
+		if c.Cells[row][col] == Empty {
+			c.Cells[row][col] = player
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ConnectFour) Winner() string {
+	if line, ok := c.line(); ok {
+		return c.Cells[line[0][0]][line[0][1]]
+	}
+	if len(c.Legal()) == 0 {
+		return "draw"
+	}
+	return ""
+}
+
+// line returns a four in a row on the board, if there is one
+func (c *ConnectFour) line() ([4][2]int, bool) {
+	for _, line := range connectFourLines {
+		first := c.Cells[line[0][0]][line[0][1]]
+		if first == Empty {
+			continue
+		}
+		won := true
+		for _, cell := range line[1:] {
+			if c.Cells[cell[0]][cell[1]] != first {
+				won = false
+				break
+			}
+		}
+		if won {
+			return line, true
+		}
+	}
+	return [4][2]int{}, false
+}
+
+func (c *ConnectFour) Describe(column int) string {
+	return fmt.Sprintf("column %d", column)
+}
+
+func (c *ConnectFour) Rules(player string) string {
+	return fmt.Sprintf("You are playing Connect Four as player %s on a 7-column, 6-row grid. "+
+		"Pieces drop to the lowest empty cell of a column and four in a row in any direction wins. "+
+		"Columns are numbered 1-7 from left to right. Each turn, respond with a single column number.", player)
+}
+
+func (c *ConnectFour) Context(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	prompt.WriteString(fmt.Sprintf("You are playing Connect Four as player %s.\n", player))
+	prompt.WriteString("Pieces drop to the lowest empty cell of the chosen column. Get four in a row horizontally, vertically, or diagonally to win.\n\n")
+
+	if len(moveHistory) > 0 {
+		prompt.WriteString("Move history:\n")
+		for i, move := range moveHistory {
+			prompt.WriteString(fmt.Sprintf("%d. Player %s played column %d\n", i+1, move.Player, move.Position))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("Current board (top row first, . is empty):\n")
+	prompt.WriteString(" 1 2 3 4 5 6 7\n")
+	for _, row := range c.Cells {
+		prompt.WriteString("|")
+		for _, cell := range row {
+			if cell == Empty {
+				cell = "."
+			}
+			prompt.WriteString(cell + "|")
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("\n✅ AVAILABLE COLUMNS (CHOOSE ONE OF THESE): ")
+	prompt.WriteString(joinPositions(c.Legal()))
+	prompt.WriteString("\n")
+
+	if promptHints != HintsNone {
+		winningMoves := ImmediateWins(c, player)
+		blockingMoves := ImmediateWins(c, opponent)
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		if len(winningMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("🎯 YOU CAN WIN NOW! Play column %d to get four in a row!\n", winningMoves[0]))
+		} else if len(blockingMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("⚠️  DANGER! %s can win in column %d! You MUST BLOCK IT!\n", opponent, blockingMoves[0]))
+		} else {
+			prompt.WriteString("No immediate wins or threats detected. Play strategically.\n")
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. WIN: Complete four in a row immediately\n")
+		prompt.WriteString(fmt.Sprintf("2. BLOCK: Stop %s from completing four in a row\n", opponent))
+		prompt.WriteString(fmt.Sprintf("3. AVOID: Don't fill the cell right below one where %s would win\n", opponent))
+		prompt.WriteString("4. STRATEGIC: Otherwise, prefer the center column (4) and build threats of three\n")
+	}
+
+	return prompt.String()
+}
+
+func (c *ConnectFour) Prompt(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	prompt.WriteString(c.Context(player, moveHistory))
+
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	prompt.WriteString("1. You MUST choose ONLY from the AVAILABLE COLUMNS list above\n")
+	prompt.WriteString(fmt.Sprintf("2. ONLY respond with ONE column number from: %v\n", c.Legal()))
+	prompt.WriteString("3. Do NOT include any other text, explanation, or formatting\n")
+	prompt.WriteString("4. Your response should be a SINGLE digit only\n")
+
+	return prompt.String()
+}
+
+// ParseMove extracts the first column number from the response
+func (c *ConnectFour) ParseMove(response string) (int, error) {
+	match := regexp.MustCompile(`[1-7]`).FindString(response)
+	if match == "" {
+		return -1, fmt.Errorf("no valid column found in response: %s", strings.TrimSpace(response))
+	}
+	return strconv.Atoi(match)
+}
+
+func (c *ConnectFour) HumanHint() string {
+	return "column 1-7"
+}
+
+func (c *ConnectFour) ParseHumanMove(input string) (int, error) {
+	return c.ParseMove(input)
+}
+
+// SearchDepth is how many moves ahead minimax looks, since the full game tree is far too large
+func (c *ConnectFour) SearchDepth() int {
+	return 6
+}
+
+// Evaluate scores an unfinished position for player by its open lines: each
+// line holding only player's pieces counts for it, more the fuller it is, and
+// likewise against it for the opponent. Center pieces get a bonus since they
+// take part in the most lines.
+func (c *ConnectFour) Evaluate(player string) int {
+	weights := [4]int{0, 1, 4, 16}
+	score := 0
+	for _, line := range connectFourLines {
+		mine, theirs := 0, 0
+		for _, cell := range line {
+			switch c.Cells[cell[0]][cell[1]] {
+			case player:
+				mine++
+			case Empty:
+			default:
+				theirs++
+			}
+		}
+		if theirs == 0 {
+			score += weights[mine]
+		} else if mine == 0 {
+			score -= weights[theirs]
+		}
+	}
+	for row := range c.Cells {
+		switch c.Cells[row][connectFourCols/2] {
+		case player:
+			score += 3
+		case Empty:
+		default:
+			score -= 3
+		}
+	}
+	return score
+}
+
+// joinPositions formats moves as a comma-separated list
+func joinPositions(positions []int) string {
+	parts := make([]string, len(positions))
+	for i, pos := range positions {
+		parts[i] = strconv.Itoa(pos)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// LoadPosition reads the 42 cells row by row from the top, left to right.
+// Pieces must rest on the bottom or on another piece.
+func (c *ConnectFour) LoadPosition(board string) (string, error) {
+	cells, err := parseCells(board, connectFourRows*connectFourCols)
+	if err != nil {
+		return "", err
+	}
+	for i, cell := range cells {
+		c.Cells[i/connectFourCols][i%connectFourCols] = cell
+	}
+	for row := 0; row < connectFourRows-1; row++ {
+		for col := 0; col < connectFourCols; col++ {
+			if c.Cells[row][col] != Empty && c.Cells[row+1][col] == Empty {
+				return "", fmt.Errorf("start position has a floating piece in column %d", col+1)
+			}
+		}
+	}
+	return playerToMove(cells)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxConversations is how many games' conversations an LLM player keeps at
+// once; the least recently used is dropped for a new game beyond that
+const maxConversations = 256
+
+// Conversations keep an LLM player's chat with each game it plays, instead
+// of a new prompt for every move: the game's rules are the system message,
+// each move it's asked for is a short user turn with the moves since its
+// last and the board, and its own answers are the assistant turns, an
+// answer that isn't a legal move followed by a user turn saying so. Games
+// are told apart by their Game, which the game loop keeps for a whole game,
+// and the side played, for players copied into several seats.
+type Conversations struct {
+	mu    sync.Mutex
+	games map[seat]*conversation
+	uses  int // ever-growing count of turns, ordering conversations by last use
+}
+
+// seat is a side played in a game
+type seat struct {
+	game   Game
+	player string
+}
+
+// conversation is one game's chat
+type conversation struct {
+	messages []ChatMessage // the turns so far, answered
+	asked    int           // moves played when the model was last asked, or -1
+	pending  string        // the user turn last asked, until it's answered
+	lastUsed int
+}
+
+// NewConversations starts a player without any conversations yet
+func NewConversations() *Conversations {
+	return &Conversations{games: map[seat]*conversation{}}
+}
+
+// Next returns the conversation so far in game and the user turn asking for
+// player's next move
+func (c *Conversations) Next(game Game, player string, moveHistory []Move) ([]ChatMessage, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conv := c.get(seat{game, player})
+	switch {
+	case conv.pending != "":
+		// The last request failed before the model answered, so ask again
+	case conv.asked == len(moveHistory):
+		conv.pending = fmt.Sprintf("That isn't a legal move. Choose one of: %s", joinPositions(LegalPositions(game)))
+	default:
+		conv.pending = newTurn(game, player, moveHistory, conv.asked)
+	}
+	conv.asked = len(moveHistory)
+	return append([]ChatMessage(nil), conv.messages...), conv.pending
+}
+
+// Answer records the model's answer to the turn Next last returned for
+// player in game
+func (c *Conversations) Answer(game Game, player, answer string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conv := c.get(seat{game, player})
+	if conv.pending == "" {
+		return
+	}
+	conv.messages = appendChatMessage(conv.messages, ChatMessage{Role: "user", Content: conv.pending})
+	conv.messages = appendChatMessage(conv.messages, ChatMessage{Role: "assistant", Content: orDefault(strings.TrimSpace(answer), "(no answer)")})
+	conv.pending = ""
+}
+
+// get finds the seat's conversation, starting one if there's none
+func (c *Conversations) get(s seat) *conversation {
+	c.uses++
+	conv, ok := c.games[s]
+	if !ok {
+		if len(c.games) >= maxConversations {
+			var oldest *seat
+			for other := range c.games {
+				if oldest == nil || c.games[other].lastUsed < c.games[*oldest].lastUsed {
+					oldest = &other
+				}
+			}
+			delete(c.games, *oldest)
+		}
+		conv = &conversation{asked: -1}
+		c.games[s] = conv
+	}
+	conv.lastUsed = c.uses
+	return conv
+}
+
+// newTurn tells player the moves played since it was last asked, all of
+// them at the start of a game, and shows the board
+func newTurn(game Game, player string, moveHistory []Move, asked int) string {
+	var news []string
+	if asked < 0 {
+		news = append(news, "New game.")
+		if asked = 0; len(moveHistory) > 0 {
+			news = append(news, "The moves so far:")
+		}
+	}
+	for _, move := range moveHistory[asked:] {
+		who := "Player " + move.Player
+		if move.Player == player {
+			who = "You"
+		}
+		news = append(news, fmt.Sprintf("%s played %s.", who, game.Describe(move.Position)))
+	}
+	board := strings.TrimRight(gamePromptData(game, player, nil).Board, "\n")
+	return fmt.Sprintf("%s\n\nThe board:\n%s\n\nYour move as %s, one of: %s", strings.Join(news, " "), board, player, joinPositions(LegalPositions(game)))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Dashboard is a summary panel redrawn after every game in place of the
+// games' moves, so long runs can be followed without scrolling back through
+// them. On a terminal it redraws over itself; otherwise each panel is
+// printed after the last.
+type Dashboard struct {
+	Title    string
+	Games    int // games the run will play, 0 if unknown or unlimited
+	out      io.Writer
+	terminal bool
+	start    time.Time
+	recent   []string // results of the latest games, oldest first
+}
+
+// dashboardRecent is how many of the latest results the panel shows
+const dashboardRecent = 30
+
+// NewDashboard creates a dashboard drawn on the console at out
+func NewDashboard(out *os.File, title string, games int) *Dashboard {
+	return &Dashboard{Title: title, Games: games, out: out, terminal: isTerminal(out), start: time.Now()}
+}
+
+// Render records a finished game and redraws the panel
+func (d *Dashboard) Render(result GameResult, stats *GameStats) {
+	panel := d.Panel(result, stats)
+	if d.terminal {
+		// Move to the top left and clear the screen
+		panel = "\033[H\033[2J" + panel
+	}
+	fmt.Fprint(d.out, panel)
+}
+
+// Panel records a finished game and returns the panel's text
+func (d *Dashboard) Panel(result GameResult, stats *GameStats) string {
+	symbol := map[string]string{PlayerX: "X", PlayerO: "O", "draw": "=", "error": "!"}[result.Winner]
+	d.recent = append(d.recent, symbol)
+	if len(d.recent) > dashboardRecent {
+		d.recent = d.recent[1:]
+	}
+
+	var panel strings.Builder
+	elapsed := time.Since(d.start)
+	fmt.Fprintln(&panel, strings.Repeat("=", 50))
+	fmt.Fprintln(&panel, d.Title)
+	fmt.Fprintln(&panel, strings.Repeat("=", 50))
+	progress := fmt.Sprint(stats.Total)
+	if d.Games > 0 {
+		progress += fmt.Sprintf("/%d", d.Games)
+	}
+	fmt.Fprintf(&panel, "Games:      %s   Elapsed: %s", progress, elapsed.Round(time.Second))
+	if d.Games > 0 && stats.Total > 0 && stats.Total < d.Games {
+		remaining := elapsed / time.Duration(stats.Total) * time.Duration(d.Games-stats.Total)
+		fmt.Fprintf(&panel, "   ETA: %s", remaining.Round(time.Second))
+	}
+	fmt.Fprintln(&panel)
+	fmt.Fprintf(&panel, "X wins:     %s\n", percentOf(stats.XWins, stats.Total))
+	fmt.Fprintf(&panel, "O wins:     %s\n", percentOf(stats.OWins, stats.Total))
+	fmt.Fprintf(&panel, "Draws:      %s\n", percentOf(stats.Draws, stats.Total))
+	fmt.Fprintf(&panel, "Errors:     %s\n", percentOf(stats.Errors, stats.Total))
+	fmt.Fprintf(&panel, "Illegal:    %.1f%% of %d answers   Unparseable: %.1f%%\n",
+		rate(stats.IllegalMoves, stats.Answers)*100, stats.Answers, rate(stats.ParseFailures, stats.Answers)*100)
+	if stats.ResponseCount > 0 {
+		fmt.Fprintf(&panel, "Avg time:   %.2fs per answer", (stats.TotalResponseTime / time.Duration(stats.ResponseCount)).Seconds())
+		if stats.Usage.Total() > 0 {
+			fmt.Fprintf(&panel, "   Tokens: %d", stats.Usage.Total())
+		}
+		fmt.Fprintln(&panel)
+	}
+	if names := playedModels(stats); len(names) > 0 {
+		fmt.Fprintf(&panel, "  %-24s %12s %9s %9s\n", "Model", "W-L-D", "Illegal %", "Avg time")
+		for _, name := range names {
+			m := stats.Models[name]
+			fmt.Fprintf(&panel, "  %-24s %12s %8.1f%% %9s\n", shortName(name, 24), fmt.Sprintf("%d-%d-%d", m.Wins, m.Losses, m.Draws),
+				m.IllegalRate()*100, formatResponse(m))
+		}
+	}
+	fmt.Fprintf(&panel, "Latest:     %s  (X/O wins, = draw, ! error)\n", strings.Join(d.recent, " "))
+	fmt.Fprintln(&panel, strings.Repeat("-", 50))
+	return panel.String()
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// discordAPI is the base URL of Discord's REST API
+const discordAPI = "https://discord.com/api/v10"
+
+// discordPoll is how often a Discord channel is checked for moves
+const discordPoll = 2 * time.Second
+
+// Discord posts to a Discord channel through a webhook, or as a bot, which
+// can also read the channel's messages for its members' moves
+type Discord struct {
+	Webhook string // webhook URL; messages are posted here when set
+	Token   string // bot token, for Channel
+	Channel string // channel ID
+	Retry   RetryPolicy
+}
+
+// discordMessage is the part of a Discord message used here
+type discordMessage struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Author  struct {
+		Username string `json:"username"`
+		Bot      bool   `json:"bot"`
+	} `json:"author"`
+}
+
+// discordPost is a message to post or edit; no one is ever pinged, whatever
+// a model's name or answer says
+type discordPost struct {
+	Content         string              `json:"content"`
+	AllowedMentions map[string][]string `json:"allowed_mentions"`
+}
+
+// NewDiscord posts through webhook if it's set, and otherwise as the bot
+// with token in channel; the bot needs both, and the Message Content intent
+// to read moves
+func NewDiscord(webhook, channel, token string) (*Discord, error) {
+	if webhook != "" {
+		if u, err := url.Parse(webhook); err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("invalid Discord webhook URL %q: must be an https URL", webhook)
+		}
+	}
+	if channel != "" && token == "" {
+		return nil, fmt.Errorf("-discord-channel needs a bot token: set -discord-token or DISCORD_BOT_TOKEN")
+	}
+	return &Discord{Webhook: webhook, Token: token, Channel: channel, Retry: RetryPolicy{MaxRetries: 3, BaseDelay: time.Second}}, nil
+}
+
+// Send posts a message, returning its ID
+func (d *Discord) Send(content string) (string, error) {
+	var message discordMessage
+	target := discordAPI + "/channels/" + d.Channel + "/messages"
+	if d.Webhook != "" {
+		target = d.Webhook + "?wait=true"
+	}
+	err := d.do(http.MethodPost, target, d.Webhook == "", discordPost{Content: content, AllowedMentions: map[string][]string{"parse": {}}}, &message)
+	return message.ID, err
+}
+
+// Edit replaces the content of a message Send posted
+func (d *Discord) Edit(id, content string) error {
+	target := discordAPI + "/channels/" + d.Channel + "/messages/" + id
+	if d.Webhook != "" {
+		target = d.Webhook + "/messages/" + id
+	}
+	var message discordMessage
+	return d.do(http.MethodPatch, target, d.Webhook == "", discordPost{Content: content, AllowedMentions: map[string][]string{"parse": {}}}, &message)
+}
+
+// Messages returns the channel's messages posted after the one with ID
+// after, oldest first
+func (d *Discord) Messages(after string) ([]discordMessage, error) {
+	var messages []discordMessage
+	err := d.do(http.MethodGet, discordAPI+"/channels/"+d.Channel+"/messages?limit=50&after="+after, true, nil, &messages)
+	sort.Slice(messages, func(i, j int) bool {
+		a, _ := strconv.ParseUint(messages[i].ID, 10, 64)
+		b, _ := strconv.ParseUint(messages[j].ID, 10, 64)
+		return a < b
+	})
+	return messages, err
+}
+
+// do sends a request, as the bot if bot is set, retrying rate limits and
+// server errors
+func (d *Discord) do(method, target string, bot bool, body, out any) error {
+	headers := map[string]string{}
+	if bot {
+		headers["Authorization"] = "Bot " + d.Token
+	}
+	for retry := 1; ; retry++ {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+		var err error
+		if body == nil {
+			err = getJSON(ctx, target, headers, out)
+		} else {
+			var req *http.Request
+			if req, err = newJSONRequest(ctx, target, headers, body); err == nil {
+				req.Method = method
+				err = doJSON(req, out)
+			}
+		}
+		cancel()
+		if err == nil || !IsRetryable(err) || retry > d.Retry.MaxRetries {
+			return err
+		}
+		time.Sleep(d.Retry.Delay(retry, err))
+	}
+}
+
+// DiscordFeed keeps a message per game in a Discord channel up to date with
+// live's game: the board as a code block, whose turn it is, and the result
+// and score once it's over
+type DiscordFeed struct {
+	Discord *Discord
+	live    *Live
+
+	mu      sync.Mutex
+	game    int    // the game message is about
+	message string // ID of the game's message
+	content string // as last posted
+	failed  bool   // whether the last post failed, so failures are warned about once
+}
+
+// NewDiscordFeed posts live's games to d as they're played, in the background
+func NewDiscordFeed(d *Discord, live *Live) *DiscordFeed {
+	f := &DiscordFeed{Discord: d, live: live}
+	go func() {
+		_, changed := live.State()
+		for {
+			<-changed
+			var state LiveState
+			state, changed = live.State()
+			f.Post(state)
+		}
+	}()
+	return f
+}
+
+// Post shows a snapshot, warning if it can't
+func (f *DiscordFeed) Post(state LiveState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content := discordBoard(state)
+	if state.Game == 0 || (state.Game == f.game && content == f.content) {
+		return
+	}
+	var err error
+	if state.Game != f.game {
+		var id string
+		if id, err = f.Discord.Send(content); err == nil {
+			f.game, f.message = state.Game, id
+		}
+	} else {
+		err = f.Discord.Edit(f.message, content)
+	}
+	if err == nil {
+		f.content = content
+	}
+	if err != nil && !f.failed {
+		slog.Warn("couldn't post to Discord", "err", err)
+	}
+	f.failed = err != nil
+}
+
+// Flush posts the latest snapshot, so the channel shows the end of the run
+// however far behind the background posts are
+func (f *DiscordFeed) Flush() {
+	state, _ := f.live.State()
+	f.Post(state)
+}
+
+// discordBoard formats a snapshot as a Discord message
+func discordBoard(state LiveState) string {
+	return fmt.Sprintf("**%s, game %d**: %s (X) vs %s (O)\n```\n%s\n```\n%s\nScore: %s",
+		state.Name, state.Game, state.Players[PlayerX], state.Players[PlayerO],
+		strings.Trim(state.Board, "\n"), state.Status(), state.ScoreLine())
+}
+
+// DiscordAgent takes moves from the members of a Discord channel, the first
+// to answer "!move" and a move the game accepts, e.g. "!move 4", when it's
+// their turn
+type DiscordAgent struct {
+	Discord *Discord
+}
+
+// Name identifies the channel's players
+func (a *DiscordAgent) Name() string {
+	return "discord"
+}
+
+// ChooseMove asks the channel for a move and waits until someone sends a valid one
+func (a *DiscordAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	parse, hint := game.ParseMove, "move"
+	if input, ok := game.(humanInput); ok {
+		parse, hint = input.ParseHumanMove, input.HumanHint()
+	}
+	last, err := a.Discord.Send(fmt.Sprintf("Your move as %s: reply with `!move` and a %s", player, hint))
+	if err != nil {
+		return MoveResult{Position: -1}, fmt.Errorf("error asking Discord for a move: %w", err)
+	}
+	for {
+		time.Sleep(discordPoll)
+		messages, err := a.Discord.Messages(last)
+		if err != nil {
+			return MoveResult{Position: -1}, fmt.Errorf("error reading moves from Discord: %w", err)
+		}
+		for _, message := range messages {
+			last = message.ID
+			input, ok := strings.CutPrefix(strings.TrimSpace(message.Content), "!move")
+			if message.Author.Bot || !ok {
+				continue
+			}
+			position, err := parse(input)
+			if err == nil && !containsPosition(game.Legal(), position) {
+				err = errors.New(capitalize(game.Describe(position)) + " is not available")
+			}
+			if err != nil {
+				a.Discord.Send(fmt.Sprintf("%s: %v, try again", message.Author.Username, err))
+				continue
+			}
+			return MoveResult{Position: position}, nil
+		}
+	}
+}
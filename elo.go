@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// initialElo is the rating a player starts from
+const initialElo = 1500
+
+// EloRating is one player's rating in one game
+type EloRating struct {
+	Rating float64 `json:"rating"`
+	Games  int     `json:"games"`
+	Wins   int     `json:"wins"`
+	Draws  int     `json:"draws"`
+	Losses int     `json:"losses"`
+}
+
+// Ratings are Elo ratings kept in a JSON file between runs. Each game has
+// its own ratings, since a model's strength at tic-tac-toe says nothing about
+// Connect Four; Game picks the ones this run updates.
+type Ratings struct {
+	Path   string
+	Game   string
+	K      float64
+	ByGame map[string]map[string]*EloRating
+
+	start map[string]float64 // ratings before this run's first game, for the ladder
+}
+
+// LoadRatings reads the ratings file at path, starting afresh if it doesn't exist yet
+func LoadRatings(path, game string, k float64) (*Ratings, error) {
+	r := &Ratings{Path: path, Game: game, K: k, ByGame: map[string]map[string]*EloRating{}, start: map[string]float64{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.ByGame); err != nil {
+		return nil, fmt.Errorf("reading Elo ratings from %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Player returns name's rating in the current game, creating it if needed
+func (r *Ratings) Player(name string) *EloRating {
+	players, ok := r.ByGame[r.Game]
+	if !ok {
+		players = map[string]*EloRating{}
+		r.ByGame[r.Game] = players
+	}
+	rating, ok := players[name]
+	if !ok {
+		rating = &EloRating{Rating: initialElo}
+		players[name] = rating
+	}
+	if _, ok := r.start[name]; !ok {
+		r.start[name] = rating.Rating
+	}
+	return rating
+}
+
+// expectedScore is the score a player rated a is expected to get against one rated b
+func expectedScore(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Record updates both players' ratings after a game, scoring an unfinished
+// game as a loss for the player who failed, and saves the file. Self-play
+// is skipped, since a model can't gain rating from itself.
+func (r *Ratings) Record(result GameResult, agents map[string]Agent) error {
+	nameX, nameO := agents[PlayerX].Name(), agents[PlayerO].Name()
+	if nameX == nameO {
+		return nil
+	}
+	winner := result.Winner
+	if winner == "error" {
+		winner = OtherPlayer(result.FailedPlayer)
+	}
+	scoreX := 0.5
+	switch winner {
+	case PlayerX:
+		scoreX = 1
+	case PlayerO:
+		scoreX = 0
+	}
+
+	x, o := r.Player(nameX), r.Player(nameO)
+	change := r.K * (scoreX - expectedScore(x.Rating, o.Rating))
+	x.Rating += change
+	o.Rating -= change
+	for _, p := range []struct {
+		rating *EloRating
+		score  float64
+	}{{x, scoreX}, {o, 1 - scoreX}} {
+		p.rating.Games++
+		switch p.score {
+		case 1:
+			p.rating.Wins++
+		case 0:
+			p.rating.Losses++
+		default:
+			p.rating.Draws++
+		}
+	}
+	return r.Save()
+}
+
+// Save writes the ratings file, replacing it in one step so an interrupted
+// run can't leave it half written
+func (r *Ratings) Save() error {
+	return writeJSONFile(r.Path, r.ByGame)
+}
+
+// writeJSONFile writes v as indented JSON to path, replacing the file in one step
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// writeFileAtomic replaces the file at path with data, so readers never see
+// it half written
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Describe shows a player's rating and its change this run, e.g. "1516 (+16 this run)"
+func (r *Ratings) Describe(name string) string {
+	rating := r.Player(name).Rating
+	return fmt.Sprintf("%.0f (%+.0f this run)", rating, rating-r.start[name])
+}
+
+// Report records a game and prints both players' new ratings
+func (r *Ratings) Report(result GameResult, agents map[string]Agent) {
+	if err := r.Record(result, agents); err != nil {
+		slog.Warn("couldn't save Elo ratings", "path", r.Path, "err", err)
+	}
+	if nameX, nameO := agents[PlayerX].Name(), agents[PlayerO].Name(); nameX != nameO {
+		slog.Info(fmt.Sprintf("📈 Elo: %s %s, %s %s", nameX, r.Describe(nameX), nameO, r.Describe(nameO)))
+	}
+}
+
+// PrintLadder prints every player rated in the current game, highest first,
+// with the change from this run for those who played
+func (r *Ratings) PrintLadder(w io.Writer) {
+	players := r.ByGame[r.Game]
+	if len(players) == 0 {
+		return
+	}
+	var names []string
+	for name := range players {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		if players[names[a]].Rating != players[names[b]].Rating {
+			return players[names[a]].Rating > players[names[b]].Rating
+		}
+		return names[a] < names[b]
+	})
+
+	fmt.Fprintf(w, "Elo ladder for %s (K=%g, %s):\n", r.Game, r.K, r.Path)
+	fmt.Fprintf(w, "  %-4s %-24s %6s %6s %6s %6s %6s %6s\n", "Rank", "Player", "Elo", "Change", "Games", "W", "D", "L")
+	for rank, name := range names {
+		p := players[name]
+		change := ""
+		if start, ok := r.start[name]; ok {
+			change = fmt.Sprintf("%+.0f", p.Rating-start)
+		}
+		fmt.Fprintf(w, "  %-4d %-24s %6.0f %6s %6d %6d %6d %6d\n", rank+1, shortName(name, 24), p.Rating, change, p.Games, p.Wins, p.Draws, p.Losses)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// namedAgent is an agent that only has a name, for recording results
+type namedAgent string
+
+func (a namedAgent) Name() string { return string(a) }
+
+func (a namedAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	return MoveResult{Position: -1}, errors.New("namedAgent doesn't play")
+}
+
+func TestExpectedScore(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want float64
+	}{
+		{1500, 1500, 0.5},
+		{1600, 1400, 0.7597},
+		{1400, 1600, 0.2403},
+		{1900, 1500, 0.9091},
+	}
+	for _, tt := range tests {
+		if got := expectedScore(tt.a, tt.b); math.Abs(got-tt.want) > 0.00005 {
+			t.Errorf("expectedScore(%g, %g) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEloRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "elo.json")
+	r, err := LoadRatings(path, "tictactoe", 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agents := map[string]Agent{PlayerX: namedAgent("llama3.2"), PlayerO: namedAgent("qwen2.5")}
+
+	// Equal ratings expect half a point, so a win moves K/2 each way
+	if err := r.Record(GameResult{Winner: PlayerX}, agents); err != nil {
+		t.Fatal(err)
+	}
+	x, o := r.Player("llama3.2"), r.Player("qwen2.5")
+	if x.Rating != 1516 || o.Rating != 1484 {
+		t.Errorf("ratings after a win = %.2f and %.2f, want 1516 and 1484", x.Rating, o.Rating)
+	}
+	// O failing to move counts as X winning again, now expected to score 0.546
+	if err := r.Record(GameResult{Winner: "error", FailedPlayer: PlayerO}, agents); err != nil {
+		t.Fatal(err)
+	}
+	if want := 1516 + 32*(1-expectedScore(1516, 1484)); math.Abs(x.Rating-want) > 1e-9 || math.Abs(x.Rating+o.Rating-3000) > 1e-9 {
+		t.Errorf("ratings after a forfeit = %.2f and %.2f, want %.2f and %.2f", x.Rating, o.Rating, want, 3000-want)
+	}
+	if err := r.Record(GameResult{Winner: "draw"}, map[string]Agent{PlayerX: namedAgent("llama3.2"), PlayerO: namedAgent("llama3.2")}); err != nil {
+		t.Fatal(err)
+	}
+	if x.Games != 2 || x.Wins != 2 || o.Losses != 2 {
+		t.Errorf("records = %+v and %+v, want 2 wins and 2 losses with self-play skipped", *x, *o)
+	}
+
+	reloaded, err := LoadRatings(path, "tictactoe", 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Player("llama3.2"); *got != *x {
+		t.Errorf("reloaded rating = %+v, want %+v", *got, *x)
+	}
+	if other, _ := LoadRatings(path, "connect4", 32); other.Player("llama3.2").Rating != initialElo {
+		t.Error("a tic-tac-toe rating carried over to Connect Four")
+	}
+}
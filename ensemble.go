@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnsembleAgent asks the LLM several times per turn and plays the majority-vote move
+type EnsembleAgent struct {
+	LLM   *LLMAgent
+	Votes int
+}
+
+// Name identifies the model and the number of votes
+func (e *EnsembleAgent) Name() string {
+	return fmt.Sprintf("%s (vote %d)", e.LLM.Name(), e.Votes)
+}
+
+// ChooseMove collects the votes and returns the most popular legal move. Ties go
+// to whichever of the tied moves was proposed first.
+func (e *EnsembleAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	result := MoveResult{Position: -1}
+	counts := make(map[int]int)
+	var order []int // legal moves in the order they were first proposed
+	var answers []string
+	var firstErr error
+	legal := game.Legal()
+
+	for i := 0; i < max(e.Votes, 1); i++ {
+		vote, err := e.LLM.ChooseMove(game, player, moveHistory)
+		result.Prompt = vote.Prompt
+		result.Duration += vote.Duration
+		result.Usage.Add(vote.Usage)
+		if vote.Response != "" {
+			answers = append(answers, strings.TrimSpace(vote.Response))
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if result.Position == -1 {
+			// Remember the first parsed answer in case no vote is legal
+			result.Position = vote.Position
+		}
+		if !containsPosition(legal, vote.Position) {
+			continue
+		}
+		if counts[vote.Position] == 0 {
+			order = append(order, vote.Position)
+		}
+		counts[vote.Position]++
+	}
+	result.Response = strings.Join(answers, " | ")
+
+	if len(order) == 0 {
+		if result.Position == -1 {
+			return result, firstErr
+		}
+		return result, nil
+	}
+
+	best := order[0]
+	for _, pos := range order[1:] {
+		if counts[pos] > counts[best] {
+			best = pos
+		}
+	}
+	result.Position = best
+	result.Response += fmt.Sprintf(" => %d (%d/%d votes)", best, counts[best], e.Votes)
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONEvents writes the events of -output json, one JSON object per line:
+// "start" before the first game, "game" after each game, and "summary" at
+// the end of the run
+type JSONEvents struct {
+	Mode  string // name of the game being played, e.g. "Connect Four"
+	mu    sync.Mutex
+	enc   *json.Encoder
+	games int
+}
+
+// StartEvent begins a run
+type StartEvent struct {
+	Event   string                  `json:"event"`
+	Mode    string                  `json:"mode"`
+	Games   int                     `json:"games"`             // games to play, per pairing in a tournament; 0 for unlimited
+	Players map[string]RecordPlayer `json:"players,omitempty"` // by symbol, X or O, outside tournaments
+	Models  []string                `json:"models,omitempty"`  // entrants of a tournament
+	Started time.Time               `json:"started"`
+}
+
+// GameEvent is a finished game, recorded as in the -jsonl game log
+type GameEvent struct {
+	Event string `json:"event"`
+	GameRecord
+}
+
+// SummaryEvent ends a run with its totals
+type SummaryEvent struct {
+	Event  string                  `json:"event"`
+	Games  int                     `json:"games"`
+	XWins  int                     `json:"x_wins"`
+	OWins  int                     `json:"o_wins"`
+	Draws  int                     `json:"draws"`
+	Errors int                     `json:"errors"`
+	Models map[string]ModelSummary `json:"models"`
+}
+
+// ModelSummary is one model's results over the run. A game a player can't
+// finish counts as a loss for them and a win for their opponent in Record
+// and Score; Errors counts the games the model couldn't finish.
+type ModelSummary struct {
+	Games            int     `json:"games"`
+	Wins             int     `json:"wins"`
+	Losses           int     `json:"losses"`
+	Draws            int     `json:"draws"`
+	Errors           int     `json:"errors"`
+	Score            float64 `json:"score"` // share of points won, counting draws as half
+	IllegalRate      float64 `json:"illegal_rate"`
+	ParseFailureRate float64 `json:"parse_failure_rate"`
+	AverageSeconds   float64 `json:"average_seconds"` // mean LLM response time
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	GradedMoves      int     `json:"graded_moves,omitempty"`
+	MoveQuality      float64 `json:"move_quality,omitempty"` // average quality of graded moves, 0 to 1
+	Blunders         int     `json:"blunders,omitempty"`
+}
+
+// NewJSONEvents writes events to w
+func NewJSONEvents(w io.Writer, mode string) *JSONEvents {
+	return &JSONEvents{Mode: mode, enc: json.NewEncoder(w)}
+}
+
+func (e *JSONEvents) emit(event any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.enc.Encode(event)
+}
+
+// Start announces a run between agents, or between the models of a tournament
+func (e *JSONEvents) Start(games int, agents map[string]Agent, sides map[string]string, models []string) {
+	event := StartEvent{Event: "start", Mode: e.Mode, Games: games, Models: models, Started: time.Now().UTC()}
+	if models == nil {
+		event.Players = map[string]RecordPlayer{}
+		for player, agent := range agents {
+			event.Players[player] = RecordPlayer{Model: agent.Name(), Backend: sides[player]}
+		}
+	}
+	e.emit(event)
+}
+
+// Game reports a finished game; sides gives each player's backend
+func (e *JSONEvents) Game(result GameResult, agents map[string]Agent, sides map[string]string) {
+	e.mu.Lock()
+	e.games++
+	number := e.games
+	e.mu.Unlock()
+	e.emit(GameEvent{Event: "game", GameRecord: NewGameRecord(number, e.Mode, result, agents, sides)})
+}
+
+// Summary reports the run's totals
+func (e *JSONEvents) Summary(stats *GameStats) {
+	e.emit(NewSummaryEvent(stats))
+}
+
+// NewSummaryEvent totals a run's statistics
+func NewSummaryEvent(stats *GameStats) SummaryEvent {
+	event := SummaryEvent{Event: "summary", Games: stats.Total, XWins: stats.XWins, OWins: stats.OWins, Draws: stats.Draws,
+		Errors: stats.Errors, Models: map[string]ModelSummary{}}
+	for _, name := range playedModels(stats) {
+		m := stats.Models[name]
+		record := m.MovingFirst
+		record.add(m.MovingSecond)
+		event.Models[name] = ModelSummary{
+			Games:            m.Games,
+			Wins:             record.Wins,
+			Losses:           record.Losses,
+			Draws:            record.Draws,
+			Errors:           m.Errors,
+			Score:            record.Score(),
+			IllegalRate:      m.IllegalRate(),
+			ParseFailureRate: m.ParseFailureRate(),
+			AverageSeconds:   m.AverageResponse().Seconds(),
+			PromptTokens:     m.Usage.PromptTokens,
+			CompletionTokens: m.Usage.CompletionTokens,
+			GradedMoves:      m.GradedMoves,
+			MoveQuality:      m.Quality(),
+			Blunders:         m.Grades[Blunder],
+		}
+	}
+	return event
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Example is a solved position from an -examples file: a board in the
+// -start-position notation, the player to move, and the right answer as the
+// model should give it, with why it's right
+type Example struct {
+	Board  string `json:"board"`
+	Player string `json:"player,omitempty"` // defaults to the player the board says is to move, then X
+	Move   string `json:"move"`             // e.g. "4", or "H8" in Gomoku
+	Why    string `json:"why,omitempty"`
+}
+
+// FewShot is the solved examples a model is shown before its own position
+type FewShot struct {
+	Text  string        // the examples written out, put before text prompts
+	Turns []ChatMessage // the examples as user and assistant turns, for chats
+}
+
+// LoadFewShot reads a JSON array of examples from path and checks each is
+// a legal move in a position of the game newGame creates
+func LoadFewShot(path string, newGame func() Game) (*FewShot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the examples: %w", err)
+	}
+	var examples []Example
+	if err := json.Unmarshal(data, &examples); err != nil {
+		return nil, fmt.Errorf("invalid examples %s: %w", path, err)
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no examples in %s", path)
+	}
+
+	var text strings.Builder
+	text.WriteString("Here are some solved example positions, each with the right move:\n")
+	fewShot := &FewShot{}
+	for i, example := range examples {
+		game := newGame()
+		loader, ok := game.(PositionLoader)
+		if !ok {
+			return nil, fmt.Errorf("%s doesn't support -examples, which need positions", game.Name())
+		}
+		toMove, err := loader.LoadPosition(example.Board)
+		if err == nil && game.Winner() != "" {
+			err = fmt.Errorf("the position is already finished")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("example %d in %s: %w", i+1, path, err)
+		}
+		player := strings.ToUpper(orDefault(example.Player, orDefault(toMove, PlayerX)))
+		if player != PlayerX && player != PlayerO {
+			return nil, fmt.Errorf("example %d in %s: player must be X or O, not %q", i+1, path, example.Player)
+		}
+		position, err := game.ParseMove(example.Move)
+		if err == nil && !containsPosition(game.Legal(), position) {
+			err = fmt.Errorf("%s isn't a legal move", game.Describe(position))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("example %d in %s: %w", i+1, path, err)
+		}
+
+		board := strings.TrimRight(gamePromptData(game, player, nil).Board, "\n")
+		question := fmt.Sprintf("You are %s, to move.\n%s\nAvailable: %s", player, board, joinPositions(LegalPositions(game)))
+		answer := strings.TrimSpace(example.Move)
+		fmt.Fprintf(&text, "\nExample %d: %s\nRight move: %s", i+1, question, answer)
+		if example.Why != "" {
+			fmt.Fprintf(&text, " (%s)", strings.TrimSpace(example.Why))
+		}
+		text.WriteString("\n")
+		fewShot.Turns = append(fewShot.Turns,
+			ChatMessage{Role: "user", Content: "Example: " + question},
+			ChatMessage{Role: "assistant", Content: answer})
+	}
+	text.WriteString("\nNow your own position:\n\n")
+	fewShot.Text = text.String()
+	return fewShot, nil
+}
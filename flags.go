@@ -0,0 +1,291 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cliFlags holds the values of every flag. They're registered on the global
+// flag set, which each subcommand's own flag set shares (see cli.go), so
+// settings from the environment and -config files land here too.
+type cliFlags struct {
+	configPath         *string
+	dryRun             *bool
+	profile            *string
+	gameName           *string
+	variant            *string
+	boards             *int
+	startPosition      *string
+	swap               *bool
+	timeControl        *time.Duration
+	increment          *time.Duration
+	randomStart        *int
+	ollamaURL          *string
+	backendName        *string
+	listBackends       *bool
+	backendNameX       *string
+	backendNameO       *string
+	azureDeployment    *string
+	azureAPIVersion    *string
+	apiKey             *string
+	apiKeyX            *string
+	apiKeyO            *string
+	headers            headerFlags
+	awsRegion          *string
+	rpm                *int
+	tpm                *int
+	priceIn            *float64
+	priceOut           *float64
+	maxCost            *float64
+	breakerThreshold   *int
+	fallbackModel      *string
+	fallbackURL        *string
+	fallbackBackend    *string
+	fallbackAPIKey     *string
+	balance            *string
+	keepAlive          *string
+	checkModels        *bool
+	autoPull           *bool
+	warmup             *bool
+	tools              *bool
+	cot                *bool
+	jsonMoves          *bool
+	grammar            *bool
+	logitBias          *bool
+	stream             *bool
+	chat               *bool
+	examplesPath       *string
+	conversation       *string
+	hints              *string
+	boardFormat        *string
+	promptLang         *string
+	promptTemplatePath *string
+	maxTokens          *int
+	model              *string
+	modelX             *string
+	modelO             *string
+	systemX            *string
+	systemO            *string
+	personaX           *string
+	personaO           *string
+	urlX               *string
+	urlO               *string
+	maxRetries         *int
+	httpRetries        *int
+	proxy              *string
+	caCert             *string
+	insecureTLS        *bool
+	maxIdleConns       *int
+	timeout            *time.Duration
+	moveTimeout        *time.Duration
+	httpBackoff        *time.Duration
+	debug              *bool
+	games              *int
+	concurrency        *int
+	tuiMode            *bool
+	showDashboard      *bool
+	seriesLength       *int
+	temperature        *float64
+	topP               *float64
+	topK               *int
+	seed               *int
+	optionsX           optionFlags
+	optionsO           optionFlags
+	human              *string
+	opponentName       *string
+	mctsSimulations    *int
+	minimaxDepth       *int
+	agentMode          *string
+	votes              *int
+	eloFile            *string
+	eloK               *float64
+	glickoFile         *string
+	glickoTau          *float64
+	models             *string
+	format             *string
+	promptList         *string
+	rounds             *int
+	engines            *string
+	passes             *int
+
+	outFile         *string
+	replayDir       *string
+	transcriptDir   *string
+	imageDir        *string
+	imageFormat     *string
+	imageEveryMove  *bool
+	gifDir          *string
+	replayDelay     *time.Duration
+	webhookURLs     *string
+	jsonlFile       *string
+	reportFile      *string
+	metricsAddr     *string
+	outputFormat    *string
+	noColor         *bool
+	verbose         *bool
+	quiet           *bool
+	logPath         *string
+	webAddr         *string
+	grpcAddr        *string
+	matchAddr       *string
+	matchTimeout    *time.Duration
+	matchmakerURL   *string
+	discordWebhook  *string
+	discordChannel  *string
+	discordToken    *string
+	discordPlay     *bool
+	twitchChannel   *string
+	twitchNick      *string
+	twitchToken     *string
+	twitchWindow    *time.Duration
+	slackWebhook    *string
+	slackChannel    *string
+	slackToken      *string
+	overlayDir      *string
+	leaderboardFile *string
+}
+
+// registerFlags registers every flag on the global flag set
+func registerFlags() *cliFlags {
+	f := &cliFlags{}
+	f.configPath = flag.String("config", "", "YAML or TOML file of settings named like flags, e.g. game.yaml, for the flags not given on the command line")
+	f.dryRun = flag.Bool("dry-run", false, "Validate the settings, check every model's backend answers and has it, print the settings in effect, and exit without playing")
+	f.profile = flag.String("profile", "", "Named profile of the -config file to use, e.g. quick-local, over the rest of the file")
+	f.gameName = flag.String("game", "tictactoe", "Game to play: "+strings.Join(GameNames(), ", "))
+	f.variant = flag.String("variant", "", "Rule variant of the game, e.g. misere for tic-tac-toe (three in a row loses)")
+	f.boards = flag.Int("boards", 1, fmt.Sprintf("Number of boards for -variant notakto (1-%d)", maxNotaktoBoards))
+	f.startPosition = flag.String("start-position", "", "Start every game from this board: X, O, and . for each cell in the game's order, e.g. X...O.... for tic-tac-toe")
+	f.swap = flag.Bool("swap", false, "Swap (pie) rule: after the opening move, the second player may answer \"swap\" to take that move over")
+	f.timeControl = flag.Duration("time", 0, "Chess clock: LLM thinking time each player gets per game, e.g. 60s (0 for no clock)")
+	f.increment = flag.Duration("increment", 0, "Time added to a player's clock after each of their moves, e.g. 5s (with -time)")
+	f.randomStart = flag.Int("random-start", 0, "Pre-play this many random legal moves at the start of each game")
+	f.ollamaURL = flag.String("url", "http://localhost:11434", "Ollama/LMStudio API URL")
+	f.backendName = flag.String("backend", "ollama", "LLM API backend: "+strings.Join(ProviderNames(), ", ")+" (see -list-backends)")
+	f.listBackends = flag.Bool("list-backends", false, "List the available LLM API backends and exit")
+	f.backendNameX = flag.String("backend-x", "", "LLM API backend for player X (defaults to -backend)")
+	f.backendNameO = flag.String("backend-o", "", "LLM API backend for player O (defaults to -backend)")
+	f.azureDeployment = flag.String("azure-deployment", "", "Azure OpenAI deployment name (defaults to the model name)")
+	f.azureAPIVersion = flag.String("azure-api-version", azureDefaultAPIVersion, "Azure OpenAI api-version query parameter")
+	f.apiKey = flag.String("api-key", "", "API key for the backend (defaults to the backend's environment variable, e.g. OLLAMA_API_KEY, OPENAI_API_KEY)")
+	f.apiKeyX = flag.String("api-key-x", "", "API key for player X's backend (defaults to -api-key)")
+	f.apiKeyO = flag.String("api-key-o", "", "API key for player O's backend (defaults to -api-key)")
+	f.headers = headerFlags{}
+	flag.Var(f.headers, "header", "Extra HTTP header as key=value (repeatable)")
+	f.awsRegion = flag.String("aws-region", "", "AWS region for the bedrock backend (defaults to AWS_REGION, then us-east-1)")
+	f.rpm = flag.Int("rpm", 0, "Maximum LLM requests per minute across all players (0 for no limit)")
+	f.tpm = flag.Int("tpm", 0, "Maximum estimated LLM tokens per minute across all players (0 for no limit)")
+	f.priceIn = flag.Float64("price-in", 0, "Price in dollars per million prompt tokens, for cost estimates")
+	f.priceOut = flag.Float64("price-out", 0, "Price in dollars per million completion tokens, for cost estimates")
+	f.maxCost = flag.Float64("max-cost", 0, "Stop the run once estimated spend reaches this many dollars (0 for no budget)")
+	f.breakerThreshold = flag.Int("breaker-threshold", 5, "Consecutive backend failures before giving up on a backend for the rest of the run (0 to disable)")
+	f.fallbackModel = flag.String("fallback-model", "", "Model to switch to once a backend's circuit breaker opens")
+	f.fallbackURL = flag.String("fallback-url", "", "API URL to switch to once a backend's circuit breaker opens")
+	f.fallbackBackend = flag.String("fallback-backend", "", "Backend type for the fallback (defaults to player X's backend)")
+	f.fallbackAPIKey = flag.String("fallback-api-key", "", "API key for the fallback (defaults to player X's key on player X's backend, otherwise the fallback backend's environment variable)")
+	f.balance = flag.String("balance", "round-robin", "How requests are spread over a comma-separated -url list: round-robin or least-busy")
+	f.keepAlive = flag.String("keep-alive", "", "How long Ollama keeps the model loaded after a request, e.g. 10m or -1 for forever (defaults to the server's setting)")
+	f.checkModels = flag.Bool("check-models", true, "Check that Ollama has the requested models before starting, suggesting close matches if not")
+	f.autoPull = flag.Bool("auto-pull", false, "Pull requested models that Ollama doesn't have yet, so unattended runs can start from scratch")
+	f.warmup = flag.Bool("warmup", true, "Send a warmup request before game 1 so model load time doesn't skew response times")
+	f.tools = flag.Bool("tools", false, "Offer the model a make_move tool and read its move from the tool call")
+	f.cot = flag.Bool("cot", false, "Chain of thought: ask for step-by-step reasoning before a final \"MOVE:\" line, play that move, and keep the reasoning for -debug, -transcripts, and -jsonl")
+	f.jsonMoves = flag.Bool("json", false, "Demand moves as JSON {\"position\", \"reasoning\"} using the backend's structured output support")
+	f.grammar = flag.Bool("grammar", false, "Constrain llama.cpp output to the available positions with a GBNF grammar")
+	f.logitBias = flag.Bool("logit-bias", false, "Bias digit tokens toward the available positions on backends with logit_bias (llamacpp, openai, azure, openrouter)")
+	f.stream = flag.Bool("stream", false, "Stream LLM responses and show tokens as they arrive")
+	f.chat = flag.Bool("chat", false, "Send move history as alternating user/assistant messages (uses /api/chat on Ollama)")
+	f.examplesPath = flag.String("examples", "", "JSON file of solved example positions and their right moves to show models before their own, e.g. examples.json, for few-shot prompting")
+	f.conversation = flag.String("conversation", "", "Keep a conversation with X's, O's, or both players' LLMs through each game (x, o, or both): the rules as the system message, then a short turn a move with their own answers, to compare with a fresh prompt every move")
+	f.hints = flag.String("hints", HintsFull, "How much analysis of the position prompts give: full (threats and strategy advice), threats (only moves that win or must be blocked now), or none (the board alone), so the model's own skill is measured")
+	f.boardFormat = flag.String("board-format", BoardGrid, "How prompts show the tic-tac-toe board: "+strings.Join(BoardFormats, ", ")+", to measure which one models read most reliably")
+	f.promptLang = flag.String("prompt-lang", "en", "Language of the built-in tic-tac-toe prompt: "+strings.Join(PromptLanguages, ", ")+", to see whether models play worse when instructed in other languages")
+	f.promptTemplatePath = flag.String("prompt-template", "", "Go text/template file to build the prompt for plain text answers from, e.g. prompt.tmpl, instead of the built-in prompt (see the README for its variables)")
+	f.maxTokens = flag.Int("max-tokens", 0, "Maximum tokens per LLM response (0 for the backend default)")
+	f.model = flag.String("model", "llama3.2", "Model to use (e.g., llama3.2, llama3.1:70b, qwen2.5, mistral)")
+	f.modelX = flag.String("model-x", "", "Model for player X (defaults to -model)")
+	f.modelO = flag.String("model-o", "", "Model for player O (defaults to -model)")
+	f.systemX = flag.String("system-x", "", "System prompt for player X's LLM, e.g. a persona such as \"You are an expert coach\", or @FILE to read it from a file (tournament entrants use it too)")
+	f.systemO = flag.String("system-o", "", "System prompt for player O's LLM, or @FILE to read it from a file")
+	f.personaX = flag.String("persona-x", "", "Built-in persona for player X's LLM, put before any -system-x prompt and shown with its name in the statistics: "+strings.Join(PersonaNames(), ", "))
+	f.personaO = flag.String("persona-o", "", "Built-in persona for player O's LLM: "+strings.Join(PersonaNames(), ", "))
+	f.urlX = flag.String("url-x", "", "API URL for player X (defaults to -url)")
+	f.urlO = flag.String("url-o", "", "API URL for player O (defaults to -url)")
+	f.maxRetries = flag.Int("retries", 3, "Maximum retries for invalid moves")
+	f.httpRetries = flag.Int("http-retries", 4, "Retries with exponential backoff for connection failures, HTTP 429, and 5xx errors (separate from -retries)")
+	f.proxy = flag.String("proxy", "", "HTTP proxy URL for backend requests (defaults to HTTP_PROXY/HTTPS_PROXY)")
+	f.caCert = flag.String("ca-cert", "", "PEM file of extra CA certificates to trust for HTTPS backends")
+	f.insecureTLS = flag.Bool("insecure-tls", false, "Skip TLS certificate verification (for self-signed test servers only)")
+	f.maxIdleConns = flag.Int("max-idle-conns", 16, "Idle HTTP connections kept open per backend host for reuse")
+	f.timeout = flag.Duration("timeout", 2*time.Minute, "Timeout for each HTTP request to the LLM backend (0 for none)")
+	f.moveTimeout = flag.Duration("move-timeout", 5*time.Minute, "Overall deadline for each LLM call, including HTTP retries (0 for none)")
+	f.httpBackoff = flag.Duration("http-backoff", time.Second, "Delay before the first HTTP retry, doubled for each retry after (with jitter)")
+	f.debug = flag.Bool("debug", false, "Show full prompts sent to LLM")
+	f.games = flag.Int("games", 1, "Number of games to play, or matches with -series (0 for unlimited)")
+	f.concurrency = flag.Int("concurrency", 1, "Number of games to play at once; each game's output is printed whole when it finishes")
+	f.tuiMode = flag.Bool("tui", false, "Play in a full-screen terminal interface showing the board, moves, prompt, and statistics, with keys to pause, step, and show the full prompt")
+	f.showDashboard = flag.Bool("dashboard", false, "Show a summary panel of the run so far, redrawn after every game, instead of each game's moves")
+	f.seriesLength = flag.Int("series", 0, "Play best-of-N matches, e.g. 7, alternating the first player (0 for single games)")
+	f.temperature = flag.Float64("temperature", 0.7, "Temperature for LLM responses (0.0-2.0, higher = more random)")
+	f.topP = flag.Float64("top-p", 0, "Nucleus sampling cutoff (0.0-1.0, 0 for the backend default)")
+	f.topK = flag.Int("top-k", 0, "Sample only from the K most likely tokens (0 for the backend default)")
+	f.seed = flag.Int("seed", 0, "Random seed for reproducible LLM sampling (0 for unseeded)")
+	f.optionsX = optionFlags{}
+	flag.Var(f.optionsX, "options-x", "Generation options for player X as key=value pairs, e.g. temperature=0.2,seed=42 (overrides the global flags)")
+	f.optionsO = optionFlags{}
+	flag.Var(f.optionsO, "options-o", "Generation options for player O as key=value pairs (overrides the global flags)")
+	f.human = flag.String("human", "", "Play as X or O yourself against the LLM")
+	f.opponentName = flag.String("opponent", "llm", "Opponent for the LLM: llm, minimax, mcts, heuristic, or random")
+	f.mctsSimulations = flag.Int("mcts-simulations", 1000, "Simulations per move for the mcts opponent")
+	f.minimaxDepth = flag.Int("minimax-depth", 0, "Moves the minimax opponent searches ahead (0 for the game's default: the whole game for tic-tac-toe, less for larger games)")
+	f.agentMode = flag.String("agent", "llm", "How LLM players choose moves: llm, ensemble, reflect, or hybrid")
+	f.votes = flag.Int("votes", 5, "LLM calls per move for the ensemble agent")
+	f.eloFile = flag.String("elo", "", "JSON file of Elo ratings to update after every game and keep between runs, e.g. elo.json")
+	f.eloK = flag.Float64("elo-k", 32, "Elo K-factor: the most a rating can change in one game (with -elo)")
+	f.glickoFile = flag.String("glicko", "", "JSON file of Glicko-2 ratings, with deviation and volatility, to update after every game and keep between runs, e.g. glicko.json")
+	f.glickoTau = flag.Float64("glicko-tau", 0.5, "Glicko-2 system constant: how much a player's volatility can change (with -glicko)")
+	f.models = flag.String("models", "", "Comma-separated models to enter in the tournament subcommand")
+	f.format = flag.String("format", FormatRoundRobin, "Tournament format: "+strings.Join(TournamentFormats, ", ")+" (brackets are seeded in -models order)")
+	f.promptList = flag.String("prompts", "", "Comma-separated prompt templates the abtest subcommand compares, the first being the baseline, e.g. built-in,terse.tmpl (built-in is the built-in prompt)")
+	f.rounds = flag.Int("rounds", 0, "Rounds of a -format swiss tournament (0 for log2 of the number of models, rounded up)")
+	f.engines = flag.String("engines", "", "Comma-separated engines the bench subcommand plays player X's model against, from random, heuristic, mcts, and minimax (defaults to every one that plays the -game)")
+	f.passes = flag.Int("passes", 1, "Times the puzzle subcommand poses every puzzle, to measure how consistently a model solves them")
+
+	f.outFile = flag.String("out", "", "CSV file to write one row per game to, e.g. results.csv (models, winner, moves, duration, retries, illegal answers, tokens)")
+	f.replayDir = flag.String("replays", "", "Directory to save a replay file of every game to, e.g. replays/ (game-0001.json and so on), for the replay subcommand")
+	f.transcriptDir = flag.String("transcripts", "", "Directory to write a text transcript of every game to, e.g. transcripts/ (game-0001.txt and so on): every prompt, raw answer, retry, and timing")
+	f.imageDir = flag.String("images", "", "Directory to save a picture of every game's final position to, e.g. images/ (game-0001.svg and so on)")
+	f.imageFormat = flag.String("image-format", ImageSVG, "Format of -images pictures: svg or png")
+	f.imageEveryMove = flag.Bool("image-every-move", false, "Save a -images picture of the board after every move, not just the final position")
+	f.gifDir = flag.String("gif", "", "Directory to save an animated GIF of every game's moves to, e.g. gifs/ (game-0001.gif and so on)")
+	f.replayDelay = flag.Duration("delay", 0, "Time between moves with the replay subcommand, e.g. 1s (0 to wait for Enter)")
+	f.webhookURLs = flag.String("webhook", "", "URL to POST JSON to when each game ends and when the run or tournament finishes, for CI or chat (comma-separated for several)")
+	f.jsonlFile = flag.String("jsonl", "", "JSON Lines file to append a complete record of every game to, e.g. games.jsonl (players, moves, timings, raw LLM responses, result)")
+	f.reportFile = flag.String("report", "", "Markdown file to write a benchmark report to at the end of the run, e.g. report.md")
+	f.metricsAddr = flag.String("metrics-addr", "", "Serve Prometheus metrics at /metrics on this address, e.g. :9090, for monitoring long runs")
+	f.outputFormat = flag.String("output", "text", "Output format: text, or json for JSON Lines events on stdout (start, each game, summary) with the usual text sent to stderr")
+	f.noColor = flag.Bool("no-color", false, "Print boards without ANSI colors (also off when stdout isn't a terminal or NO_COLOR is set)")
+	f.verbose = flag.Bool("v", false, "Verbose: also log each request to the LLM")
+	f.quiet = flag.Bool("q", false, "Quiet: log only warnings, without each game's moves, and print the final statistics")
+	f.logPath = flag.String("log-file", "", "File to append a JSON log of the run to at every level, e.g. run.log, whatever -v or -q show on the console")
+	f.webAddr = flag.String("web", ":8080", "Address to serve the live web page on, with the serve subcommand")
+	f.grpcAddr = flag.String("grpc-addr", ":50051", "Address to serve the gRPC API on, with the grpc subcommand")
+	f.matchAddr = flag.String("match-addr", ":8090", "Address to serve the matchmaking API on, with the matchmaker subcommand")
+	f.matchTimeout = flag.Duration("match-timeout", 2*time.Minute, "How long a remote player may take over a move, or go without asking for its turn, with the matchmaker subcommand")
+	f.matchmakerURL = flag.String("matchmaker", "", "URL of the matchmaker to play player X's model on, with the join subcommand, e.g. http://host:8090")
+	f.discordWebhook = flag.String("discord", "", "Discord webhook URL to post each game's board to, updated as it's played, with the result and score")
+	f.discordChannel = flag.String("discord-channel", "", "Discord channel ID to post each game's board to as a bot, instead of or as well as -discord")
+	f.discordToken = flag.String("discord-token", "", "Discord bot token for -discord-channel (defaults to DISCORD_BOT_TOKEN)")
+	f.discordPlay = flag.Bool("discord-play", false, "With -human and -discord-channel, the channel's members play the human's side by sending \"!move\" and their move")
+	f.twitchChannel = flag.String("twitch", "", "With -human, Twitch channel whose chat plays the human's side by voting with \"!move\" and a move")
+	f.twitchNick = flag.String("twitch-nick", "", "Twitch account to post the votes to the chat as, with -twitch-token; without, chat is read anonymously")
+	f.twitchToken = flag.String("twitch-token", "", "OAuth token of -twitch-nick (defaults to TWITCH_OAUTH_TOKEN)")
+	f.twitchWindow = flag.Duration("twitch-window", 30*time.Second, "How long Twitch chat votes on each move, with -twitch")
+	f.slackWebhook = flag.String("slack", "", "Slack incoming webhook URL to announce each game's start and result, and a tournament's standings, in")
+	f.slackChannel = flag.String("slack-channel", "", "Slack channel to post to as a bot instead of through -slack, e.g. #llm-games")
+	f.slackToken = flag.String("slack-token", "", "Slack bot token for -slack-channel (defaults to SLACK_BOT_TOKEN)")
+	f.overlayDir = flag.String("overlay", "", "Directory to keep text files of the game in progress in for OBS text sources, e.g. overlay/ (board.txt, status.txt, score.txt, ...)")
+	f.leaderboardFile = flag.String("leaderboard", DefaultLeaderboardPath(), "File of cumulative results by model and backend, added to after every game (\"\" to disable)")
+	return f
+}
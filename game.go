@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Game is the rules engine for one game mode. The game loop, agents, and
+// statistics only work through this interface, so every mode shares the same
+// players, backends, and reports.
+type Game interface {
+	// Name is the game's display name, e.g. "Tic-Tac-Toe"
+	Name() string
+	// Clone returns an independent copy, for agents that search ahead
+	Clone() Game
+	// Display prints the board to w, the console or a game's output buffer
+	Display(w io.Writer)
+	// Legal returns the moves that can be played now, in ascending order
+	Legal() []int
+	// Play makes player's move, returning false if it is illegal
+	Play(player string, position int) bool
+	// Winner returns "X" or "O" once a player has won, "draw" once the game
+	// is over without a winner, and "" while it is in progress
+	Winner() string
+	// Describe names a move for the console and prompts, e.g. "position 4"
+	Describe(position int) string
+	// Rules explains the game and the answer format, opening chat conversations
+	Rules(player string) string
+	// Context describes the position, threats, and strategy, without any
+	// instructions on how to format the answer
+	Context(player string, moveHistory []Move) string
+	// Prompt is the complete prompt for a plain text answer
+	Prompt(player string, moveHistory []Move) string
+	// ParseMove extracts the move from a text answer
+	ParseMove(response string) (int, error)
+}
+
+var games = make(map[string]func() Game)
+
+// RegisterGame makes a game available by name for -game; registering the same name twice panics
+func RegisterGame(name string, newGame func() Game) {
+	if _, exists := games[name]; exists {
+		panic(fmt.Sprintf("game %q registered twice", name))
+	}
+	games[name] = newGame
+}
+
+// RegisterVariant makes a rule variant of a registered game available for -variant
+func RegisterVariant(game, variant string, newGame func() Game) {
+	RegisterGame(game+"/"+variant, newGame)
+}
+
+// GameNames returns the registered game names in sorted order, without variants
+func GameNames() []string {
+	var names []string
+	for name := range games {
+		if !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VariantNames returns the registered variants of a game in sorted order
+func VariantNames(game string) []string {
+	var names []string
+	for name := range games {
+		if base, variant, ok := strings.Cut(name, "/"); ok && base == game {
+			names = append(names, variant)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewGame returns the constructor for the named game, or for one of its
+// variants when variant is not empty
+func NewGame(name, variant string) (func() Game, error) {
+	if _, ok := games[name]; !ok {
+		return nil, fmt.Errorf("unknown game %q: must be one of %s", name, strings.Join(GameNames(), ", "))
+	}
+	if variant == "" {
+		return games[name], nil
+	}
+	newGame, ok := games[name+"/"+variant]
+	if !ok {
+		variants := VariantNames(name)
+		if len(variants) == 0 {
+			return nil, fmt.Errorf("%s has no variants", name)
+		}
+		return nil, fmt.Errorf("unknown %s variant %q: must be one of %s", name, variant, strings.Join(variants, ", "))
+	}
+	return newGame, nil
+}
+
+// ImmediateWins returns the moves that would win the game for player at once.
+// Called for the opponent, it returns the moves player must block.
+func ImmediateWins(game Game, player string) []int {
+	var wins []int
+	for _, position := range game.Legal() {
+		next := game.Clone()
+		if next.Play(player, position) && next.Winner() == player {
+			wins = append(wins, position)
+		}
+	}
+	return wins
+}
+
+// MarkChooser is implemented by games where players choose which mark to
+// place. Their move numbers combine a board position and a mark.
+type MarkChooser interface {
+	// Marks returns the marks a player may place, or none when the rules in
+	// play don't offer a choice
+	Marks() []string
+	EncodeMove(position int, mark string) int
+	DecodeMove(move int) (position int, mark string)
+}
+
+// ChoosesMark returns game's MarkChooser if its players choose which mark to place
+func ChoosesMark(game Game) (MarkChooser, bool) {
+	marks, ok := game.(MarkChooser)
+	return marks, ok && len(marks.Marks()) > 0
+}
+
+// LegalPositions returns the board positions of the legal moves, without
+// marks for games where players choose them
+func LegalPositions(game Game) []int {
+	marks, ok := ChoosesMark(game)
+	if !ok {
+		return game.Legal()
+	}
+	var positions []int
+	for _, move := range game.Legal() {
+		if position, _ := marks.DecodeMove(move); !containsPosition(positions, position) {
+			positions = append(positions, position)
+		}
+	}
+	sort.Ints(positions)
+	return positions
+}
+
+// TextMoves is implemented by games whose moves are written as text rather
+// than numbers, so only plain text answers can express them
+type TextMoves interface {
+	// MoveFormat describes the moves, e.g. "coordinates such as H8"
+	MoveFormat() string
+}
+
+// PositionLoader is implemented by games that can start from a given
+// position, for -start-position
+type PositionLoader interface {
+	// LoadPosition fills the board from a board string, returning the player
+	// to move, or "" when the position doesn't decide it
+	LoadPosition(board string) (toMove string, err error)
+}
+
+// parseCells reads a board string of count cells, X, O, and . (or -) for an
+// empty cell, in the game's own cell order. Spaces, slashes, and bars may
+// separate rows for readability.
+func parseCells(board string, count int) ([]string, error) {
+	var cells []string
+	for _, ch := range board {
+		switch ch {
+		case ' ', '\t', '\n', '/', '|':
+		case 'X', 'x':
+			cells = append(cells, PlayerX)
+		case 'O', 'o':
+			cells = append(cells, PlayerO)
+		case '.', '-', '_':
+			cells = append(cells, Empty)
+		default:
+			return nil, fmt.Errorf("invalid character %q in start position: use X, O, and . for empty cells", ch)
+		}
+	}
+	if len(cells) != count {
+		return nil, fmt.Errorf("start position has %d cells, want %d", len(cells), count)
+	}
+	return cells, nil
+}
+
+// playerToMove infers whose turn it is from the number of each player's
+// marks, "" when they are level and either player may start
+func playerToMove(cells []string) (string, error) {
+	xCount, oCount := 0, 0
+	for _, cell := range cells {
+		switch cell {
+		case PlayerX:
+			xCount++
+		case PlayerO:
+			oCount++
+		}
+	}
+	switch xCount - oCount {
+	case 0:
+		return "", nil
+	case 1:
+		return PlayerO, nil
+	case -1:
+		return PlayerX, nil
+	}
+	return "", fmt.Errorf("start position has %d X's and %d O's; one player can be at most one mark ahead", xCount, oCount)
+}
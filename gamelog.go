@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// GameRecord is one line of the -jsonl game log: a complete finished game
+type GameRecord struct {
+	Game             int                     `json:"game"` // number of the game in this run
+	Mode             string                  `json:"mode"`
+	Finished         time.Time               `json:"finished"`
+	Players          map[string]RecordPlayer `json:"players"` // by symbol, X or O
+	FirstPlayer      string                  `json:"first_player"`
+	Winner           string                  `json:"winner"` // "X", "O", "draw", or "error"
+	WinnerModel      string                  `json:"winner_model,omitempty"`
+	FailedPlayer     string                  `json:"failed_player,omitempty"`
+	LostOnTime       string                  `json:"lost_on_time,omitempty"`
+	Moves            []RecordMove            `json:"moves"`
+	Attempts         []RecordAttempt         `json:"attempts"`
+	Seconds          float64                 `json:"seconds"`
+	Retries          int                     `json:"retries"`
+	IllegalMoves     int                     `json:"illegal_moves"`
+	ParseFailures    int                     `json:"parse_failures"`
+	PromptTokens     int                     `json:"prompt_tokens"`
+	CompletionTokens int                     `json:"completion_tokens"`
+}
+
+// RecordPlayer names the agent playing one side
+type RecordPlayer struct {
+	Model   string `json:"model"`
+	Backend string `json:"backend"` // "engine" for minimax and the like, "human" for people
+}
+
+// RecordMove is a move played, as the game's move number
+type RecordMove struct {
+	Player   string `json:"player"`
+	Position int    `json:"position"`
+	Mark     string `json:"mark,omitempty"`
+	Grade    string `json:"grade,omitempty"` // against perfect play: optimal, safe, inaccuracy, or blunder
+}
+
+// RecordAttempt is one answer an agent gave, whether or not it was played
+type RecordAttempt struct {
+	Player    string  `json:"player"`
+	Position  int     `json:"position"`
+	Response  string  `json:"response,omitempty"`
+	Reasoning string  `json:"reasoning,omitempty"` // the model's reasoning behind the move, with -cot or -json
+	Override  string  `json:"override,omitempty"`  // why an engine replaced the LLM's choice, if it did
+	Seconds   float64 `json:"seconds"`
+	Error     string  `json:"error,omitempty"`
+	Legal     bool    `json:"legal"`
+}
+
+// GameLog appends a GameRecord per finished game to a JSON Lines file, so
+// repeated runs can share one file
+type GameLog struct {
+	Path  string
+	Mode  string // name of the game being played, e.g. "Connect Four"
+	file  *os.File
+	games int
+}
+
+// OpenGameLog opens the JSON Lines file at path for appending, creating it if needed
+func OpenGameLog(path, mode string) (*GameLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &GameLog{Path: path, Mode: mode, file: file}, nil
+}
+
+// Record writes a finished game; sides gives each player's backend
+func (l *GameLog) Record(result GameResult, agents map[string]Agent, sides map[string]string) error {
+	l.games++
+	data, err := json.Marshal(NewGameRecord(l.games, l.Mode, result, agents, sides))
+	if err != nil {
+		return err
+	}
+	_, err = l.file.Write(append(data, '\n'))
+	return err
+}
+
+// NewGameRecord describes a finished game, numbered number in the run; sides
+// gives each player's backend
+func NewGameRecord(number int, mode string, result GameResult, agents map[string]Agent, sides map[string]string) GameRecord {
+	record := GameRecord{
+		Game:             number,
+		Mode:             mode,
+		Finished:         time.Now().UTC(),
+		Players:          map[string]RecordPlayer{},
+		FirstPlayer:      result.FirstPlayer,
+		Winner:           result.Winner,
+		FailedPlayer:     result.FailedPlayer,
+		LostOnTime:       result.LostOnTime,
+		Moves:            []RecordMove{},
+		Attempts:         []RecordAttempt{},
+		Seconds:          result.Duration.Seconds(),
+		Retries:          result.Retries,
+		IllegalMoves:     result.IllegalMoves,
+		ParseFailures:    result.ParseFailures,
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
+	}
+	for player, agent := range agents {
+		record.Players[player] = RecordPlayer{Model: agent.Name(), Backend: sides[player]}
+	}
+	if agent, ok := agents[result.Winner]; ok {
+		record.WinnerModel = agent.Name()
+	}
+	for _, move := range result.Moves {
+		record.Moves = append(record.Moves, RecordMove{Player: move.Player, Position: move.Position, Mark: move.Mark})
+	}
+	for i, grade := range result.Grades {
+		record.Moves[i].Grade = grade.String()
+	}
+	for _, a := range result.Attempts {
+		record.Attempts = append(record.Attempts, RecordAttempt{
+			Player: a.Player, Position: a.Position, Response: a.Response, Reasoning: a.Reasoning, Override: a.Override, Seconds: a.Duration.Seconds(), Error: a.Error, Legal: a.Legal,
+		})
+	}
+	return record
+}
+
+// Close closes the file
+func (l *GameLog) Close() error {
+	return l.file.Close()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"os"
+	"path/filepath"
+)
+
+// GIF frame delays, in hundredths of a second
+const (
+	gifMoveDelay  = 80
+	gifFinalDelay = 300 // the final position stays up before the loop restarts
+)
+
+// GameGIFs saves every finished game as an animated GIF of its moves in Dir,
+// game-0001.gif for the run's first game and so on, for sharing
+type GameGIFs struct {
+	Dir     string
+	NewGame func() Game // creates the game the moves were played in
+	games   int
+}
+
+// NewGameGIFs saves GIFs in dir, creating it if needed. It fails for games
+// that can't be drawn.
+func NewGameGIFs(dir string, newGame func() Game) (*GameGIFs, error) {
+	if board, ok := newGame().(Drawable); !ok || board.CellGrid() == nil {
+		return nil, fmt.Errorf("%s boards can't be drawn as images", newGame().Name())
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &GameGIFs{Dir: dir, NewGame: newGame}, nil
+}
+
+// Record animates a finished game, a frame for the start and every move
+func (g *GameGIFs) Record(result GameResult) error {
+	g.games++
+	grids := gameGrids(g.NewGame(), result.Moves)
+	animation := &gif.GIF{}
+	for i, grid := range grids {
+		var previous [][]string
+		if i > 0 {
+			previous = grids[i-1]
+		}
+		delay := gifMoveDelay
+		if i == len(grids)-1 {
+			delay = gifFinalDelay
+		}
+		animation.Image = append(animation.Image, boardImage(grid, previous))
+		animation.Delay = append(animation.Delay, delay)
+	}
+	animation.Config = image.Config{ColorModel: imagePalette, Width: animation.Image[0].Rect.Dx(), Height: animation.Image[0].Rect.Dy()}
+
+	file, err := os.Create(filepath.Join(g.Dir, fmt.Sprintf("game-%04d.gif", g.games)))
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(file, animation); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"sort"
+)
+
+// Glicko-2 starting values, and the factor between the Glicko and Glicko-2 scales
+const (
+	initialGlicko     = 1500
+	initialDeviation  = 350
+	initialVolatility = 0.06
+	glickoScale       = 173.7178
+)
+
+// GlickoRating is one player's Glicko-2 rating in one game. Deviation is the
+// uncertainty of the rating: about 95% of the time the player's true strength
+// is within two deviations of it. Volatility is how erratic their results are.
+type GlickoRating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	Draws      int     `json:"draws"`
+	Losses     int     `json:"losses"`
+}
+
+// GlickoRatings are Glicko-2 ratings kept in a JSON file between runs, per
+// game like Ratings. Each game is its own rating period, so ratings move
+// after every game and deviations shrink as players play.
+type GlickoRatings struct {
+	Path   string
+	Game   string
+	Tau    float64 // how much volatility can change, usually 0.3 to 1.2
+	ByGame map[string]map[string]*GlickoRating
+
+	start map[string]float64 // ratings before this run's first game, for the ladder
+}
+
+// LoadGlickoRatings reads the ratings file at path, starting afresh if it doesn't exist yet
+func LoadGlickoRatings(path, game string, tau float64) (*GlickoRatings, error) {
+	r := &GlickoRatings{Path: path, Game: game, Tau: tau, ByGame: map[string]map[string]*GlickoRating{}, start: map[string]float64{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.ByGame); err != nil {
+		return nil, fmt.Errorf("reading Glicko-2 ratings from %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Player returns name's rating in the current game, creating it if needed
+func (r *GlickoRatings) Player(name string) *GlickoRating {
+	players, ok := r.ByGame[r.Game]
+	if !ok {
+		players = map[string]*GlickoRating{}
+		r.ByGame[r.Game] = players
+	}
+	rating, ok := players[name]
+	if !ok {
+		rating = &GlickoRating{Rating: initialGlicko, Deviation: initialDeviation, Volatility: initialVolatility}
+		players[name] = rating
+	}
+	if _, ok := r.start[name]; !ok {
+		r.start[name] = rating.Rating
+	}
+	return rating
+}
+
+// Record updates both players' ratings after a game, scoring an unfinished
+// game as a loss for the player who failed, and saves the file. Self-play
+// is skipped, since a model can't gain rating from itself.
+func (r *GlickoRatings) Record(result GameResult, agents map[string]Agent) error {
+	nameX, nameO := agents[PlayerX].Name(), agents[PlayerO].Name()
+	if nameX == nameO {
+		return nil
+	}
+	scoreX := 0.5
+	switch gameWinner(result) {
+	case PlayerX:
+		scoreX = 1
+	case PlayerO:
+		scoreX = 0
+	}
+
+	x, o := r.Player(nameX), r.Player(nameO)
+	// Both updates use the ratings from before the game
+	newX := r.update(*x, *o, scoreX)
+	newO := r.update(*o, *x, 1-scoreX)
+	*x, *o = newX, newO
+	return r.Save()
+}
+
+// update returns p's rating after scoring score against opponent, a rating
+// period of one game
+func (r *GlickoRatings) update(p, opponent GlickoRating, score float64) GlickoRating {
+	p.Rating, p.Deviation, p.Volatility = glicko2(p, []glickoResult{{Opponent: opponent, Score: score}}, r.Tau)
+	p.Games++
+	switch score {
+	case 1:
+		p.Wins++
+	case 0:
+		p.Losses++
+	default:
+		p.Draws++
+	}
+	return p
+}
+
+// glickoResult is one game of a rating period: the opponent's rating going
+// into the period, and the player's score, 1 for a win and 0.5 for a draw
+type glickoResult struct {
+	Opponent GlickoRating
+	Score    float64
+}
+
+// glicko2 returns p's rating, deviation, and volatility after a rating
+// period with results, following Glickman's "Example of the Glicko-2 system"
+func glicko2(p GlickoRating, results []glickoResult, tau float64) (rating, deviation, volatility float64) {
+	mu, phi := (p.Rating-initialGlicko)/glickoScale, p.Deviation/glickoScale
+
+	// The estimated variance from the results, and the improvement they show
+	var variance, improvement float64
+	for _, result := range results {
+		muJ, phiJ := (result.Opponent.Rating-initialGlicko)/glickoScale, result.Opponent.Deviation/glickoScale
+		g := 1 / math.Sqrt(1+3*phiJ*phiJ/(math.Pi*math.Pi))
+		expected := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		variance += g * g * expected * (1 - expected)
+		improvement += g * (result.Score - expected)
+	}
+	v := 1 / variance
+	delta := v * improvement
+
+	// Find the new volatility with the Illinois algorithm
+	a := math.Log(p.Volatility * p.Volatility)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		return ex*(delta*delta-phi*phi-v-ex)/(2*math.Pow(phi*phi+v+ex, 2)) - (x-a)/(tau*tau)
+	}
+	A, B := a, 0.0
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > 1e-6 {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	volatility = math.Exp(A / 2)
+
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*improvement
+	return newMu*glickoScale + initialGlicko, newPhi * glickoScale, volatility
+}
+
+// Save writes the ratings file, replacing it in one step so an interrupted
+// run can't leave it half written
+func (r *GlickoRatings) Save() error {
+	return writeJSONFile(r.Path, r.ByGame)
+}
+
+// Describe shows a player's rating, deviation, and change this run, e.g.
+// "1563 ±212 (+63 this run)"
+func (r *GlickoRatings) Describe(name string) string {
+	p := r.Player(name)
+	return fmt.Sprintf("%.0f ±%.0f (%+.0f this run)", p.Rating, p.Deviation, p.Rating-r.start[name])
+}
+
+// Report records a game and prints both players' new ratings
+func (r *GlickoRatings) Report(result GameResult, agents map[string]Agent) {
+	if err := r.Record(result, agents); err != nil {
+		slog.Warn("couldn't save Glicko-2 ratings", "path", r.Path, "err", err)
+	}
+	if nameX, nameO := agents[PlayerX].Name(), agents[PlayerO].Name(); nameX != nameO {
+		slog.Info(fmt.Sprintf("📈 Glicko-2: %s %s, %s %s", nameX, r.Describe(nameX), nameO, r.Describe(nameO)))
+	}
+}
+
+// PrintLadder prints every player rated in the current game, highest first,
+// with a 95% interval for their strength and the change from this run for
+// those who played
+func (r *GlickoRatings) PrintLadder(w io.Writer) {
+	players := r.ByGame[r.Game]
+	if len(players) == 0 {
+		return
+	}
+	var names []string
+	for name := range players {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		if players[names[a]].Rating != players[names[b]].Rating {
+			return players[names[a]].Rating > players[names[b]].Rating
+		}
+		return names[a] < names[b]
+	})
+
+	fmt.Fprintf(w, "Glicko-2 ladder for %s (tau=%g, %s):\n", r.Game, r.Tau, r.Path)
+	fmt.Fprintf(w, "  %-4s %-24s %6s %5s %11s %10s %6s %6s %6s %6s %6s\n", "Rank", "Player", "Rating", "RD", "95% range", "Volatility", "Change", "Games", "W", "D", "L")
+	for rank, name := range names {
+		p := players[name]
+		change := ""
+		if start, ok := r.start[name]; ok {
+			change = fmt.Sprintf("%+.0f", p.Rating-start)
+		}
+		span := fmt.Sprintf("%.0f-%.0f", p.Rating-2*p.Deviation, p.Rating+2*p.Deviation)
+		fmt.Fprintf(w, "  %-4d %-24s %6.0f %5.0f %11s %10.4f %6s %6d %6d %6d %6d\n", rank+1, shortName(name, 24), p.Rating, p.Deviation, span,
+			p.Volatility, change, p.Games, p.Wins, p.Draws, p.Losses)
+	}
+}
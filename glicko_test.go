@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestGlicko2Example checks the update against the worked example in
+// Glickman's "Example of the Glicko-2 system": a player rated 1500 with RD
+// 200 beats a 1400 and loses to a 1550 and a 1700 in one rating period
+func TestGlicko2Example(t *testing.T) {
+	player := GlickoRating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	results := []glickoResult{
+		{Opponent: GlickoRating{Rating: 1400, Deviation: 30}, Score: 1},
+		{Opponent: GlickoRating{Rating: 1550, Deviation: 100}, Score: 0},
+		{Opponent: GlickoRating{Rating: 1700, Deviation: 300}, Score: 0},
+	}
+	rating, deviation, volatility := glicko2(player, results, 0.5)
+	if math.Abs(rating-1464.06) > 0.01 {
+		t.Errorf("rating = %.4f, want 1464.06", rating)
+	}
+	if math.Abs(deviation-151.52) > 0.01 {
+		t.Errorf("deviation = %.4f, want 151.52", deviation)
+	}
+	if math.Abs(volatility-0.05999) > 0.00001 {
+		t.Errorf("volatility = %.6f, want 0.05999", volatility)
+	}
+}
+
+func TestGlickoUpdateOneGame(t *testing.T) {
+	r := &GlickoRatings{Tau: 0.5}
+	fresh := GlickoRating{Rating: initialGlicko, Deviation: initialDeviation, Volatility: initialVolatility}
+
+	winner := r.update(fresh, fresh, 1)
+	loser := r.update(fresh, fresh, 0)
+	if winner.Rating <= initialGlicko || loser.Rating >= initialGlicko {
+		t.Errorf("ratings after a game between equals = %.1f and %.1f, want one up and one down", winner.Rating, loser.Rating)
+	}
+	if math.Abs((winner.Rating-initialGlicko)+(loser.Rating-initialGlicko)) > 1e-9 {
+		t.Errorf("rating changes %+.4f and %+.4f aren't opposite", winner.Rating-initialGlicko, loser.Rating-initialGlicko)
+	}
+	if winner.Deviation >= initialDeviation {
+		t.Errorf("deviation after a game = %.1f, want less than %d", winner.Deviation, initialDeviation)
+	}
+	if winner.Games != 1 || winner.Wins != 1 || loser.Losses != 1 {
+		t.Errorf("records = %+v and %+v, want a win and a loss", winner, loser)
+	}
+	if draw := r.update(fresh, fresh, 0.5); draw.Rating != initialGlicko || draw.Draws != 1 {
+		t.Errorf("rating after a draw between equals = %.4f with %d draws, want %d with 1", draw.Rating, draw.Draws, initialGlicko)
+	}
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterGame("gomoku", func() Game { return NewGomoku() })
+}
+
+const (
+	gomokuSize = 15
+	gomokuRun  = 5
+)
+
+// gomokuDirections are the four line directions through a cell: across, down,
+// and the two diagonals
+var gomokuDirections = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// Gomoku is five in a row on a 15x15 board; five or more stones in a line
+// win. Moves are numbered row*15+col from the top left, and written as
+// coordinates with columns A-O and rows 1-15 from the bottom, so H8 is the
+// center.
+type Gomoku struct {
+	Cells  [gomokuSize][gomokuSize]string
+	moves  int
+	winner string
+}
+
+// NewGomoku creates a game on an empty board
+func NewGomoku() *Gomoku {
+	g := &Gomoku{}
+	for r := range g.Cells {
+		for c := range g.Cells[r] {
+			g.Cells[r][c] = Empty
+		}
+	}
+	return g
+}
+
+// gomokuCoordinate writes a move as a coordinate such as H8
+func gomokuCoordinate(position int) string {
+	r, c := position/gomokuSize, position%gomokuSize
+	return fmt.Sprintf("%c%d", 'A'+c, gomokuSize-r)
+}
+
+func onGomokuBoard(r, c int) bool {
+	return r >= 0 && r < gomokuSize && c >= 0 && c < gomokuSize
+}
+
+// run counts the stones of player in the line through (r, c) in direction
+// (dr, dc), counting (r, c) itself as player's, and how many of the two cells
+// just past the ends are empty
+func (g *Gomoku) run(r, c, dr, dc int, player string) (length, openEnds int) {
+	length = 1
+	for _, sign := range []int{1, -1} {
+		rr, cc := r+sign*dr, c+sign*dc
+		for onGomokuBoard(rr, cc) && g.Cells[rr][cc] == player {
+			length++
+			rr, cc = rr+sign*dr, cc+sign*dc
+		}
+		if onGomokuBoard(rr, cc) && g.Cells[rr][cc] == Empty {
+			openEnds++
+		}
+	}
+	return length, openEnds
+}
+
+func (g *Gomoku) Name() string { return "Gomoku" }
+
+func (g *Gomoku) Clone() Game {
+	clone := *g
+	return &clone
+}
+
+func (g *Gomoku) Display(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, g.boardText())
+}
+
+func (g *Gomoku) CellGrid() [][]string {
+	grid := make([][]string, gomokuSize)
+	for r := range grid {
+		grid[r] = append([]string(nil), g.Cells[r][:]...)
+	}
+	return grid
+}
+
+// DisplayColor prints the board with the last stone and any five in a row
+// highlighted
+func (g *Gomoku) DisplayColor(w io.Writer, last int) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, g.drawBoard(func(r, c int, cell string) string {
+		mark := g.Cells[r][c]
+		return colorCell(cell, mark, r*gomokuSize+c == last, g.winning(r, c))
+	}))
+}
+
+// winning reports whether the stone at r, c is part of five or more in a row
+func (g *Gomoku) winning(r, c int) bool {
+	player := g.Cells[r][c]
+	if player == Empty || g.winner == "" {
+		return false
+	}
+	for _, d := range gomokuDirections {
+		if length, _ := g.run(r, c, d[0], d[1], player); length >= gomokuRun {
+			return true
+		}
+	}
+	return false
+}
+
+// boardText draws the board with column letters and row numbers, "." for empty cells
+func (g *Gomoku) boardText() string {
+	return g.drawBoard(func(r, c int, cell string) string { return cell })
+}
+
+// drawBoard draws the board, each cell's text passed through style
+func (g *Gomoku) drawBoard(style func(r, c int, cell string) string) string {
+	var text strings.Builder
+	text.WriteString("   ")
+	for c := 0; c < gomokuSize; c++ {
+		text.WriteString(fmt.Sprintf(" %c", 'A'+c))
+	}
+	text.WriteString("\n")
+	for r := range g.Cells {
+		text.WriteString(fmt.Sprintf("%3d", gomokuSize-r))
+		for c, cell := range g.Cells[r] {
+			if cell == Empty {
+				cell = "."
+			}
+			text.WriteString(" " + style(r, c, cell))
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+func (g *Gomoku) Legal() []int {
+	if g.winner != "" {
+		return nil
+	}
+	var positions []int
+	for r := range g.Cells {
+		for c := range g.Cells[r] {
+			if g.Cells[r][c] == Empty {
+				positions = append(positions, r*gomokuSize+c)
+			}
+		}
+	}
+	return positions
+}
+
+// Play places the stone and checks only the four lines through it for five
+func (g *Gomoku) Play(player string, position int) bool {
+	r, c := position/gomokuSize, position%gomokuSize
+	if position < 0 || !onGomokuBoard(r, c) || g.Cells[r][c] != Empty || g.winner != "" {
+		return false
+	}
+	g.Cells[r][c] = player
+	g.moves++
+	for _, d := range gomokuDirections {
+		if length, _ := g.run(r, c, d[0], d[1], player); length >= gomokuRun {
+			g.winner = player
+		}
+	}
+	return true
+}
+
+func (g *Gomoku) Winner() string {
+	if g.winner != "" {
+		return g.winner
+	}
+	if g.moves == gomokuSize*gomokuSize {
+		return "draw"
+	}
+	return ""
+}
+
+func (g *Gomoku) Describe(position int) string {
+	if position < 0 || position >= gomokuSize*gomokuSize {
+		return fmt.Sprintf("position %d", position)
+	}
+	return gomokuCoordinate(position)
+}
+
+func (g *Gomoku) Rules(player string) string {
+	return fmt.Sprintf("You are playing Gomoku as player %s on a 15x15 board. Five or more stones in a row, across, down, or diagonally, wins. "+
+		"Cells are coordinates with columns A-O and rows 1-15 counted from the bottom, so H8 is the center. "+
+		"Each turn, respond with a single coordinate such as H8.", player)
+}
+
+// gomokuThreats sorts the empty cells by the strongest line a stone of player
+// there would make: five, an open four (both ends empty, which can't be
+// stopped), or an open three. Only unbroken lines are counted.
+func (g *Gomoku) gomokuThreats(player string) (fives, openFours, openThrees []int) {
+	for _, position := range g.Legal() {
+		r, c := position/gomokuSize, position%gomokuSize
+		best, open := 0, 0
+		for _, d := range gomokuDirections {
+			length, ends := g.run(r, c, d[0], d[1], player)
+			if length > best || (length == best && ends > open) {
+				best, open = length, ends
+			}
+		}
+		switch {
+		case best >= gomokuRun:
+			fives = append(fives, position)
+		case best == 4 && open == 2:
+			openFours = append(openFours, position)
+		case best == 3 && open == 2:
+			openThrees = append(openThrees, position)
+		}
+	}
+	return fives, openFours, openThrees
+}
+
+func gomokuCoordinates(positions []int) string {
+	names := make([]string, len(positions))
+	for i, position := range positions {
+		names[i] = gomokuCoordinate(position)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (g *Gomoku) Context(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	prompt.WriteString(fmt.Sprintf("You are playing Gomoku as player %s.\n", player))
+	prompt.WriteString("The board is 15x15. Get FIVE or more stones in a row, across, down, or diagonally, to win.\n")
+	prompt.WriteString("Cells are coordinates: a column letter A-O and a row number 1-15 counted from the bottom. H8 is the center.\n\n")
+
+	if len(moveHistory) > 0 {
+		prompt.WriteString("Move history:\n")
+		for i, move := range moveHistory {
+			prompt.WriteString(fmt.Sprintf("%d. Player %s played %s\n", i+1, move.Player, g.Describe(move.Position)))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("Current board (. is an empty cell):\n")
+	prompt.WriteString(g.boardText())
+	prompt.WriteString("\n✅ AVAILABLE POSITIONS: any empty cell (.)\n")
+
+	if promptHints != HintsNone {
+		fives, openFours, openThrees := g.gomokuThreats(player)
+		blockFives, blockFours, _ := g.gomokuThreats(opponent)
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		switch {
+		case len(fives) > 0:
+			prompt.WriteString(fmt.Sprintf("🎯 YOU CAN WIN NOW! Play %s to get five in a row!\n", gomokuCoordinate(fives[0])))
+		case len(blockFives) > 0:
+			prompt.WriteString(fmt.Sprintf("⚠️  DANGER! %s can get five in a row at %s! You MUST BLOCK IT!\n", opponent, gomokuCoordinate(blockFives[0])))
+			if len(blockFives) > 1 {
+				prompt.WriteString(fmt.Sprintf("%s threatens several wins at once: %s\n", opponent, gomokuCoordinates(blockFives)))
+			}
+		case len(openFours) > 0:
+			prompt.WriteString(fmt.Sprintf("🎯 Play %s to make an OPEN FOUR, with both ends empty, which %s can't stop!\n", gomokuCoordinate(openFours[0]), opponent))
+		case len(blockFours) > 0:
+			prompt.WriteString(fmt.Sprintf("⚠️  DANGER! %s has an open three and would make an unstoppable open four at %s. BLOCK one end now!\n",
+				opponent, gomokuCoordinates(blockFours)))
+		default:
+			prompt.WriteString("No immediate wins or threats detected. Play strategically.\n")
+		}
+		if len(fives) == 0 && len(blockFives) == 0 && len(openThrees) > 0 {
+			prompt.WriteString(fmt.Sprintf("💡 You can make an open three at: %s\n", gomokuCoordinates(openThrees)))
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. WIN: Complete five in a row immediately\n")
+		prompt.WriteString(fmt.Sprintf("2. BLOCK: Stop %s's four in a row, then any open three before it becomes an open four\n", opponent))
+		prompt.WriteString("3. ATTACK: Make open fours, and build two open threes at once so only one can be blocked\n")
+		prompt.WriteString("4. STRATEGIC: Otherwise play next to your own stones, near the center\n")
+	}
+
+	return prompt.String()
+}
+
+func (g *Gomoku) Prompt(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	prompt.WriteString(g.Context(player, moveHistory))
+
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	prompt.WriteString("1. You MUST choose an EMPTY cell (.); cells with an X or O are taken\n")
+	prompt.WriteString("2. ONLY respond with ONE coordinate: the column letter then the row number, e.g. H8\n")
+	prompt.WriteString("3. Do NOT include any other text, explanation, or formatting\n")
+
+	return prompt.String()
+}
+
+var gomokuMove = regexp.MustCompile(`(?i)\b([a-o])\s*-?\s*(1[0-5]|[1-9])\b`)
+
+// ParseMove extracts a coordinate such as H8, h8, or "H 8"
+func (g *Gomoku) ParseMove(response string) (int, error) {
+	match := gomokuMove.FindStringSubmatch(response)
+	if match == nil {
+		return -1, fmt.Errorf("no valid coordinate found in response: %s", strings.TrimSpace(response))
+	}
+	c := int(strings.ToUpper(match[1])[0] - 'A')
+	row, _ := strconv.Atoi(match[2])
+	return (gomokuSize-row)*gomokuSize + c, nil
+}
+
+// MoveFormat explains why the structured answer modes don't apply
+func (g *Gomoku) MoveFormat() string {
+	return "coordinates such as H8"
+}
+
+func (g *Gomoku) HumanHint() string {
+	return "coordinate, e.g. H8"
+}
+
+func (g *Gomoku) ParseHumanMove(input string) (int, error) {
+	return g.ParseMove(input)
+}
+
+// SearchDepth keeps minimax to two moves ahead, since each move has up to 225 replies
+func (g *Gomoku) SearchDepth() int {
+	return 2
+}
+
+// Evaluate scores an unfinished position for player by every window of five
+// cells, each worth more the more of player's stones it holds, less the same
+// for the opponent's
+func (g *Gomoku) Evaluate(player string) int {
+	weights := [gomokuRun]int{0, 1, 4, 16, 64}
+	score := 0
+	for r := range g.Cells {
+		for c := range g.Cells[r] {
+			for _, d := range gomokuDirections {
+				endR, endC := r+(gomokuRun-1)*d[0], c+(gomokuRun-1)*d[1]
+				if !onGomokuBoard(endR, endC) {
+					continue
+				}
+				mine, theirs := 0, 0
+				for i := 0; i < gomokuRun; i++ {
+					switch g.Cells[r+i*d[0]][c+i*d[1]] {
+					case player:
+						mine++
+					case Empty:
+					default:
+						theirs++
+					}
+				}
+				if theirs == 0 {
+					score += weights[mine]
+				} else if mine == 0 {
+					score -= weights[theirs]
+				}
+			}
+		}
+	}
+	return score
+}
+
+// LoadPosition reads the 225 cells row by row from the top (row 15), left to right
+func (g *Gomoku) LoadPosition(board string) (string, error) {
+	cells, err := parseCells(board, gomokuSize*gomokuSize)
+	if err != nil {
+		return "", err
+	}
+	for i, cell := range cells {
+		g.Cells[i/gomokuSize][i%gomokuSize] = cell
+	}
+	g.moves = 0
+	for r := range g.Cells {
+		for c, cell := range g.Cells[r] {
+			if cell == Empty {
+				continue
+			}
+			g.moves++
+			for _, d := range gomokuDirections {
+				if length, _ := g.run(r, c, d[0], d[1], cell); length >= gomokuRun {
+					g.winner = cell
+				}
+			}
+		}
+	}
+	return playerToMove(cells)
+}
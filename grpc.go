@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/brianhealey/llama-tac-toe/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MatchServer runs matches for the gRPC API of the grpc subcommand. Every
+// match plays the server's game with its settings, like a tournament's
+// entrants, and matches run side by side.
+type MatchServer struct {
+	api.UnimplementedLlamaTacToeServer
+
+	NewGame    func() Game
+	NewPlayer  func(name string) (Agent, error) // a model, or an engine such as minimax
+	Opening    Opening
+	MaxRetries int
+	Debug      bool
+	Stats      *GameStats // the run's statistics, which every game is added to
+	// BeforeGame, if set, is called as every game starts, e.g. to announce it
+	BeforeGame func(agents map[string]Agent)
+	// AfterGame, if set, is called with every finished game, e.g. to
+	// record ratings and the leaderboard
+	AfterGame func(result GameResult, agents map[string]Agent)
+	// Stop, if set, is asked after every game whether matches must end
+	// early, e.g. because the budget is spent
+	Stop func() bool
+	// Out is where games print their moves, each game's all at once when
+	// it finishes, os.Stdout if nil
+	Out io.Writer
+
+	mu        sync.Mutex // also held while a finished game is recorded
+	matches   map[string]*match
+	standings standingsTable
+}
+
+// ServeGRPC serves the API on addr, e.g. ":50051", in the background
+func ServeGRPC(addr string, server *MatchServer) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("can't serve the gRPC API: %w", err)
+	}
+	server.matches, server.standings = map[string]*match{}, standingsTable{}
+	s := grpc.NewServer()
+	api.RegisterLlamaTacToeServer(s, server)
+	go s.Serve(listener)
+	return nil
+}
+
+func (s *MatchServer) CreateMatch(_ context.Context, req *api.CreateMatchRequest) (*api.Match, error) {
+	if req.PlayerX == "" || req.PlayerO == "" {
+		return nil, status.Error(codes.InvalidArgument, "player_x and player_o are required")
+	}
+	if req.Games < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid games %d: must be at least 1", req.Games)
+	}
+	agents := map[string]Agent{}
+	for player, name := range map[string]string{PlayerX: req.PlayerX, PlayerO: req.PlayerO} {
+		agent, err := s.NewPlayer(name)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		agents[player] = agent
+	}
+
+	s.mu.Lock()
+	m := &match{
+		info: &api.Match{
+			Id:      fmt.Sprintf("match-%d", len(s.matches)+1),
+			Game:    s.NewGame().Name(),
+			PlayerX: req.PlayerX,
+			PlayerO: req.PlayerO,
+			Games:   max(req.Games, 1),
+		},
+		changed:   make(chan struct{}),
+		standings: standingsTable{},
+	}
+	s.matches[m.info.Id] = m
+	s.mu.Unlock()
+	slog.Info(fmt.Sprintf("Match %s: %s (X) vs %s (O), %d games", m.info.Id, m.info.PlayerX, m.info.PlayerO, m.info.Games))
+	go s.play(m, agents)
+	return m.info, nil
+}
+
+// play plays a match's games one after another
+func (s *MatchServer) play(m *match, agents map[string]Agent) {
+	playAgents := map[string]Agent{}
+	for player, agent := range agents {
+		playAgents[player] = &matchAgent{Agent: agent, match: m}
+	}
+	finished := &api.MatchFinished{}
+	for number := 1; number <= int(m.info.Games); number++ {
+		game := s.NewGame()
+		m.startGame(number, s.NewGame())
+		var out bytes.Buffer
+		gameStats := NewGameStats()
+		if s.BeforeGame != nil {
+			s.BeforeGame(agents)
+		}
+		result := PlayGame(&out, game, playAgents, s.Opening, nil, s.MaxRetries, s.Debug, number, gameStats)
+		m.endGame(number, result)
+
+		s.mu.Lock()
+		s.out().Write(out.Bytes())
+		s.Stats.Merge(gameStats)
+		if s.AfterGame != nil {
+			s.AfterGame(result, agents)
+		}
+		s.standings.record(m.info.PlayerX, m.info.PlayerO, result)
+		m.standings.record(m.info.PlayerX, m.info.PlayerO, result)
+		stop := s.Stop != nil && s.Stop()
+		s.mu.Unlock()
+
+		finished.Games++
+		switch result.Winner {
+		case PlayerX:
+			finished.XWins++
+		case PlayerO:
+			finished.OWins++
+		case "draw":
+			finished.Draws++
+		default:
+			finished.Errors++
+		}
+		if stop {
+			break
+		}
+	}
+	m.send(&api.Event{Event: &api.Event_MatchFinished{MatchFinished: finished}}, true)
+	slog.Info(fmt.Sprintf("Match %s finished: %d-%d with %d draws (X-O)", m.info.Id, finished.XWins, finished.OWins, finished.Draws))
+}
+
+func (s *MatchServer) out() io.Writer {
+	if s.Out == nil {
+		return os.Stdout
+	}
+	return s.Out
+}
+
+func (s *MatchServer) match(id string) (*match, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.matches[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no match %q", id)
+	}
+	return m, nil
+}
+
+func (s *MatchServer) StreamEvents(req *api.StreamEventsRequest, stream grpc.ServerStreamingServer[api.Event]) error {
+	m, err := s.match(req.MatchId)
+	if err != nil {
+		return err
+	}
+	for sent := 0; ; {
+		events, finished, changed := m.since(sent)
+		for _, event := range events {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+		sent += len(events)
+		if finished {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func (s *MatchServer) GetStandings(_ context.Context, req *api.GetStandingsRequest) (*api.Standings, error) {
+	table := s.standings
+	if req.MatchId != "" {
+		m, err := s.match(req.MatchId)
+		if err != nil {
+			return nil, err
+		}
+		table = m.standings
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	standings := &api.Standings{}
+	for _, st := range table.ranked() {
+		standings.Standings = append(standings.Standings, &api.Standing{
+			Name:   st.Name,
+			Games:  int32(st.Games),
+			Wins:   int32(st.Wins),
+			Draws:  int32(st.Draws),
+			Losses: int32(st.Losses),
+			Points: st.Points,
+		})
+	}
+	return standings, nil
+}
+
+// match is a match's progress, kept as the events it has sent so a stream
+// can start at any time
+type match struct {
+	info      *api.Match
+	standings standingsTable // guarded by the server's mu
+
+	mu       sync.Mutex
+	events   []*api.Event
+	finished bool
+	changed  chan struct{} // closed and replaced on every event
+
+	// The game in progress, replayed from the moves its players are shown,
+	// for the boards of move events
+	number int
+	board  Game
+	moves  int
+}
+
+// send adds an event, the last one if finished
+func (m *match) send(event *api.Event, finished bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	event.MatchId = m.info.Id
+	m.events = append(m.events, event)
+	m.finished = finished
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+// since returns the events after the first n, whether there will be no
+// more, and a channel closed at the next event
+func (m *match) since(n int) ([]*api.Event, bool, <-chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.events[n:], m.finished, m.changed
+}
+
+// startGame begins a game on board, a copy of the one played on
+func (m *match) startGame(number int, board Game) {
+	m.number, m.board, m.moves = number, board, 0
+	m.send(&api.Event{Event: &api.Event_GameStarted{GameStarted: &api.GameStarted{Game: int32(number), Board: showBoard(board)}}}, false)
+}
+
+// played sends the moves in history not sent yet
+func (m *match) played(history []Move) {
+	for _, move := range history[m.moves:] {
+		m.board.Play(move.Player, move.Position)
+		m.moves++
+		m.send(&api.Event{Event: &api.Event_MovePlayed{MovePlayed: &api.MovePlayed{
+			Game:        int32(m.number),
+			Move:        int32(m.moves),
+			Player:      move.Player,
+			Position:    int32(move.Position),
+			Description: m.board.Describe(move.Position),
+			Board:       showBoard(m.board),
+		}}}, false)
+	}
+}
+
+func (m *match) endGame(number int, result GameResult) {
+	m.played(result.Moves)
+	m.send(&api.Event{Event: &api.Event_GameFinished{GameFinished: &api.GameFinished{
+		Game:    int32(number),
+		Winner:  result.Winner,
+		Result:  describeResult(result),
+		Moves:   int32(len(result.Moves)),
+		Seconds: result.Duration.Seconds(),
+	}}}, false)
+}
+
+// matchAgent sends the moves played so far each time its agent is asked
+// for a move
+type matchAgent struct {
+	Agent
+	match *match
+}
+
+func (a *matchAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	a.match.played(moveHistory)
+	return a.Agent.ChooseMove(game, player, moveHistory)
+}
+
+// standingsTable keeps players' records by name, scored like a tournament
+type standingsTable map[string]*Standing
+
+// record scores a finished game between the players of X and O
+func (t standingsTable) record(x, o string, result GameResult) {
+	winner := result.Winner
+	if winner == "error" {
+		winner = OtherPlayer(result.FailedPlayer)
+	}
+	records := map[string]*Standing{}
+	for player, name := range map[string]string{PlayerX: x, PlayerO: o} {
+		if t[name] == nil {
+			t[name] = &Standing{Name: name}
+		}
+		records[player] = t[name]
+		records[player].Games++
+	}
+	switch winner {
+	case PlayerX, PlayerO:
+		records[winner].Wins++
+		records[winner].Points++
+		records[OtherPlayer(winner)].Losses++
+	default:
+		records[PlayerX].Draws++
+		records[PlayerO].Draws++
+		records[PlayerX].Points += 0.5
+		records[PlayerO].Points += 0.5
+	}
+}
+
+// ranked returns the records by points, then wins, then name
+func (t standingsTable) ranked() []Standing {
+	var standings []Standing
+	for _, s := range t {
+		standings = append(standings, *s)
+	}
+	sort.Slice(standings, func(a, b int) bool {
+		sa, sb := standings[a], standings[b]
+		if sa.Points != sb.Points {
+			return sa.Points > sb.Points
+		}
+		if sa.Wins != sb.Wins {
+			return sa.Wins > sb.Wins
+		}
+		return sa.Name < sb.Name
+	})
+	return standings
+}
@@ -0,0 +1,112 @@
+package main
+
+import "errors"
+
+// HeuristicAgent plays the classic rule-based strategy: win, block, fork,
+// block a fork, center, opposite corner, corner, then edge
+type HeuristicAgent struct{}
+
+// Name identifies the heuristic agent
+func (h *HeuristicAgent) Name() string {
+	return "heuristic"
+}
+
+// ChooseMove applies the rules in priority order and plays the first that matches
+func (h *HeuristicAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	ttt, ok := game.(*TicTacToe)
+	if !ok || !ttt.Standard() {
+		return MoveResult{Position: -1}, errors.New("the heuristic agent only plays standard tic-tac-toe")
+	}
+	return MoveResult{Position: HeuristicMove(ttt.Board, player)}, nil
+}
+
+// HeuristicMove returns the position chosen by the rule-based strategy
+func HeuristicMove(board Board, player string) int {
+	opponent := OtherPlayer(player)
+
+	winningMoves, blockingMoves := DetectThreats(board, player)
+	if len(winningMoves) > 0 {
+		return winningMoves[0]
+	}
+	if len(blockingMoves) > 0 {
+		return blockingMoves[0]
+	}
+
+	if forks := forkPositions(board, player); len(forks) > 0 {
+		return forks[0]
+	}
+	if forks := forkPositions(board, opponent); len(forks) == 1 {
+		return forks[0]
+	} else if len(forks) > 1 {
+		// Blocking one fork leaves the other, so force a reply instead
+		if pos := forcingMove(board, player); pos >= 0 {
+			return pos
+		}
+		return forks[0]
+	}
+
+	if board[1][1] == Empty {
+		return 4
+	}
+
+	// Opposite corner
+	for _, pair := range [][2]int{{0, 8}, {2, 6}, {6, 2}, {8, 0}} {
+		corner, opposite := pair[0], pair[1]
+		if board[corner/3][corner%3] == opponent && board[opposite/3][opposite%3] == Empty {
+			return opposite
+		}
+	}
+
+	for _, pos := range []int{0, 2, 6, 8, 1, 3, 5, 7} {
+		if board[pos/3][pos%3] == Empty {
+			return pos
+		}
+	}
+	return -1
+}
+
+// forkPositions returns the empty positions where player would create two winning threats at once
+func forkPositions(board Board, player string) []int {
+	var forks []int
+	for _, pos := range AvailablePositions(board) {
+		board[pos/3][pos%3] = player
+		winningMoves, _ := DetectThreats(board, player)
+		board[pos/3][pos%3] = Empty
+		if countDistinct(winningMoves) >= 2 {
+			forks = append(forks, pos)
+		}
+	}
+	return forks
+}
+
+// forcingMove returns a position where player makes two in a row, forcing
+// the opponent to block somewhere that doesn't give them a fork, or -1
+func forcingMove(board Board, player string) int {
+	opponent := OtherPlayer(player)
+	for _, pos := range AvailablePositions(board) {
+		board[pos/3][pos%3] = player
+		winningMoves, _ := DetectThreats(board, player)
+		forced := len(winningMoves) > 0
+		for _, reply := range winningMoves {
+			for _, fork := range forkPositions(board, opponent) {
+				if fork == reply {
+					forced = false
+				}
+			}
+		}
+		board[pos/3][pos%3] = Empty
+		if forced {
+			return pos
+		}
+	}
+	return -1
+}
+
+// countDistinct returns the number of unique values in positions
+func countDistinct(positions []int) int {
+	seen := make(map[int]bool)
+	for _, pos := range positions {
+		seen[pos] = true
+	}
+	return len(seen)
+}
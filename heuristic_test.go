@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestHeuristicRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		board  string
+		player string
+		want   int
+	}{
+		{"wins before blocking", "XX./OO./...", PlayerO, 5},
+		{"blocks", "XX./.O./...", PlayerO, 2},
+		{"forks", "XO./.X./..O", PlayerX, 3},
+		{"blocks a fork", "X../.O./.X.", PlayerO, 6},
+		{"takes the centre", "X../.../...", PlayerO, 4},
+		{"takes the opposite corner", "O../.X./...", PlayerX, 8},
+		{"takes a corner", ".../.O./...", PlayerX, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if position := HeuristicMove(loadTicTacToe(t, tt.board).Board, tt.player); position != tt.want {
+				t.Errorf("HeuristicMove = %d, want %d", position, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeuristicOppositeCorners(t *testing.T) {
+	// X corner, O centre, X opposite corner: X forks at either other corner,
+	// so O has to make a threat on an edge
+	game := loadTicTacToe(t, "X../.O./..X")
+	position := HeuristicMove(game.Board, PlayerO)
+	if position != 1 && position != 3 && position != 5 && position != 7 {
+		t.Fatalf("HeuristicMove = %d, want an edge", position)
+	}
+	game.Play(PlayerO, position)
+	if position, _ := BestMove(game, PlayerX); position >= 0 {
+		game.Play(PlayerX, position)
+	}
+	if _, score := BestMove(game, PlayerO); score < 0 {
+		t.Errorf("O loses after playing %d:\n%s", position, showBoard(game))
+	}
+}
+
+func TestHeuristicNeverLoses(t *testing.T) {
+	for _, heuristic := range []string{PlayerX, PlayerO} {
+		for _, first := range []string{PlayerX, PlayerO} {
+			var play func(game *TicTacToe, toMove string)
+			play = func(game *TicTacToe, toMove string) {
+				if winner := game.Winner(); winner != "" {
+					if winner == OtherPlayer(heuristic) {
+						t.Fatalf("heuristic as %s lost with %s moving first:\n%s", heuristic, first, showBoard(game))
+					}
+					return
+				}
+				if toMove == heuristic {
+					next := game.Clone().(*TicTacToe)
+					next.Play(toMove, HeuristicMove(game.Board, toMove))
+					play(next, OtherPlayer(toMove))
+					return
+				}
+				for _, position := range game.Legal() {
+					next := game.Clone().(*TicTacToe)
+					next.Play(toMove, position)
+					play(next, OtherPlayer(toMove))
+				}
+			}
+			play(NewTicTacToe(), first)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// httpClient is shared by every backend so connections to the same server are
+// kept alive and reused across moves and games
+var httpClient = &http.Client{Transport: newTransport()}
+
+// HTTPClientConfig holds the settings for the shared HTTP client
+type HTTPClientConfig struct {
+	Proxy          string // proxy URL; empty uses HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	CACert         string // PEM file of extra CA certificates to trust
+	InsecureTLS    bool   // skip TLS certificate verification
+	MaxIdlePerHost int    // idle connections kept per host; 0 uses the default
+}
+
+// newTransport returns a transport tuned for many sequential requests to a few hosts
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	// The standard library keeps only 2 idle connections per host, too few
+	// when several players or games share a server
+	transport.MaxIdleConnsPerHost = 16
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
+
+// ConfigureHTTPClient replaces the shared HTTP client with one built from cfg
+func ConfigureHTTPClient(cfg HTTPClientConfig) error {
+	transport := newTransport()
+	if cfg.MaxIdlePerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdlePerHost
+	}
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL %q: %w", cfg.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CACert != "" || cfg.InsecureTLS {
+		tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureTLS}
+		if cfg.CACert != "" {
+			pem, err := os.ReadFile(cfg.CACert)
+			if err != nil {
+				return fmt.Errorf("error reading CA certificate: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("no certificates found in %s", cfg.CACert)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	httpClient = &http.Client{Transport: transport}
+	return nil
+}
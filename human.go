@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HumanAgent reads moves from a person at the console
+type HumanAgent struct {
+	Reader *bufio.Reader
+	Out    io.Writer // where the prompts go
+}
+
+// Name identifies the human player
+func (h *HumanAgent) Name() string {
+	return "human"
+}
+
+// humanInput is implemented by games that accept friendlier console input
+// than the answers they parse from LLMs
+type humanInput interface {
+	HumanHint() string // describes the accepted input, e.g. "column 1-7"
+	ParseHumanMove(input string) (int, error)
+}
+
+// ChooseMove prompts until the person enters a valid move
+func (h *HumanAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	parse, hint := game.ParseMove, "move"
+	if input, ok := game.(humanInput); ok {
+		parse, hint = input.ParseHumanMove, input.HumanHint()
+	}
+	for {
+		fmt.Fprintf(h.Out, "Your move as %s (%s): ", player, hint)
+		line, err := h.Reader.ReadString('\n')
+		if err != nil && strings.TrimSpace(line) == "" {
+			fmt.Fprintln(h.Out)
+			return MoveResult{Position: -1}, fmt.Errorf("error reading input: %w", err)
+		}
+
+		position, err := parse(line)
+		if err != nil {
+			fmt.Fprintf(h.Out, "%v, try again\n", err)
+			continue
+		}
+		if !containsPosition(game.Legal(), position) {
+			fmt.Fprintf(h.Out, "%s is not available, try again\n", capitalize(game.Describe(position)))
+			continue
+		}
+		return MoveResult{Position: position}, nil
+	}
+}
+
+// ParseHumanMove accepts either a position number (0-8) or a row and column (0-2 each)
+func ParseHumanMove(input string) (row, col int, err error) {
+	fields := regexp.MustCompile(`\d+`).FindAllString(input, -1)
+	switch len(fields) {
+	case 1:
+		position, _ := strconv.Atoi(fields[0])
+		if position < 0 || position > 8 {
+			return -1, -1, fmt.Errorf("position %d is out of bounds", position)
+		}
+		return position / 3, position % 3, nil
+	case 2:
+		row, _ = strconv.Atoi(fields[0])
+		col, _ = strconv.Atoi(fields[1])
+		if row < 0 || row > 2 || col < 0 || col > 2 {
+			return -1, -1, fmt.Errorf("row %d, col %d is out of bounds", row, col)
+		}
+		return row, col, nil
+	}
+	return -1, -1, fmt.Errorf("could not understand %q", strings.TrimSpace(input))
+}
@@ -0,0 +1,50 @@
+package main
+
+import "fmt"
+
+// HybridAgent lets the LLM choose moves but has minimax step in when the LLM
+// misses an immediate win or fails to block an immediate loss
+type HybridAgent struct {
+	LLM *LLMAgent
+}
+
+// Name identifies the model and the hybrid mode
+func (h *HybridAgent) Name() string {
+	return h.LLM.Name() + " (hybrid)"
+}
+
+// ChooseMove asks the LLM and overrides its answer only on missed immediate
+// wins and blocks
+func (h *HybridAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	result, err := h.LLM.ChooseMove(game, player, moveHistory)
+	if err != nil || !containsPosition(game.Legal(), result.Position) {
+		// Illegal answers are retried like any other LLM's
+		return result, err
+	}
+
+	winningMoves, blockingMoves := ImmediateWins(game, player), ImmediateWins(game, OtherPlayer(player))
+	missed := ""
+	if len(winningMoves) > 0 && !containsPosition(winningMoves, result.Position) {
+		missed = "win"
+	} else if len(winningMoves) == 0 && len(blockingMoves) > 0 && !containsPosition(blockingMoves, result.Position) {
+		missed = "block"
+	}
+	if missed == "" {
+		return result, nil
+	}
+
+	best, _ := BestMove(game, player)
+	result.Override = fmt.Sprintf("missed %s, playing %d instead of %d", missed, best, result.Position)
+	result.Position = best
+	return result, nil
+}
+
+// containsPosition reports whether pos is in positions
+func containsPosition(positions []int, pos int) bool {
+	for _, p := range positions {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
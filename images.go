@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Drawable is a game whose board can be drawn as an image
+type Drawable interface {
+	// CellGrid returns the board by row, top first: each cell Empty or a
+	// mark, or "" for the gap between boards drawn side by side
+	CellGrid() [][]string
+}
+
+// Image formats for -image-format
+const (
+	ImageSVG = "svg"
+	ImagePNG = "png"
+)
+
+// BoardImages writes pictures of every finished game's board to Dir, for
+// reports and blog posts: the final position as game-0001.svg, or with
+// EveryMove the start and each move as game-0001-move-00.svg and so on
+type BoardImages struct {
+	Dir       string
+	Format    string // ImageSVG or ImagePNG
+	EveryMove bool
+	NewGame   func() Game // creates the game the moves were played in
+	games     int
+}
+
+// NewBoardImages writes images to dir, creating it if needed. It fails for
+// games that can't be drawn.
+func NewBoardImages(dir, format string, everyMove bool, newGame func() Game) (*BoardImages, error) {
+	if format != ImageSVG && format != ImagePNG {
+		return nil, fmt.Errorf("invalid image format %q: must be %s or %s", format, ImageSVG, ImagePNG)
+	}
+	if board, ok := newGame().(Drawable); !ok || board.CellGrid() == nil {
+		return nil, fmt.Errorf("%s boards can't be drawn as images", newGame().Name())
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &BoardImages{Dir: dir, Format: format, EveryMove: everyMove, NewGame: newGame}, nil
+}
+
+// Record draws a finished game
+func (b *BoardImages) Record(result GameResult) error {
+	b.games++
+	game := b.NewGame()
+	name, title := fmt.Sprintf("game-%04d", b.games), fmt.Sprintf("%s, game %d", game.Name(), b.games)
+	write := func(name, caption string, grid, previous [][]string) error {
+		path := filepath.Join(b.Dir, name+"."+b.Format)
+		if b.Format == ImagePNG {
+			return writePNG(path, boardImage(grid, previous))
+		}
+		return os.WriteFile(path, []byte(boardSVG(grid, previous, caption)), 0o644)
+	}
+
+	grids := gameGrids(game, result.Moves)
+	last := len(grids) - 1
+	final := fmt.Sprintf("%s: %s after %d moves", title, describeResult(result), len(result.Moves))
+	if !b.EveryMove {
+		var previous [][]string
+		if last > 0 {
+			previous = grids[last-1]
+		}
+		return write(name, final, grids[last], previous)
+	}
+	if err := write(name+"-move-00", title+": start", grids[0], nil); err != nil {
+		return err
+	}
+	for i, move := range result.Moves {
+		caption := fmt.Sprintf("%s, move %d: %s plays %s", title, i+1, move.Player, game.Describe(move.Position))
+		if i+1 == last {
+			caption = final
+		}
+		if err := write(fmt.Sprintf("%s-move-%02d", name, i+1), caption, grids[i+1], grids[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gameGrids plays moves on game, returning its board before the first move
+// and after each
+func gameGrids(game Game, moves []Move) [][][]string {
+	grids := [][][]string{game.(Drawable).CellGrid()}
+	for _, move := range moves {
+		game.Play(move.Player, move.Position)
+		grids = append(grids, game.(Drawable).CellGrid())
+	}
+	return grids
+}
+
+// Board image colors
+var (
+	imageBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	imageCell       = color.RGBA{0xf4, 0xf4, 0xf4, 0xff}
+	imageGrid       = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	imageLast       = color.RGBA{0xff, 0xe9, 0x8a, 0xff} // the cells the latest move marked
+	imageX          = color.RGBA{0xd3, 0x2f, 0x2f, 0xff}
+	imageO          = color.RGBA{0x19, 0x76, 0xd2, 0xff}
+	imageText       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+)
+
+// imagePalette holds every color a board image uses, for PNG and GIF frames
+var imagePalette = color.Palette{imageBackground, imageCell, imageGrid, imageLast, imageX, imageO, imageText}
+
+// cellSize is the side of a cell in pixels, smaller for larger boards
+func cellSize(grid [][]string) int {
+	cells := len(grid)
+	for _, row := range grid {
+		cells = max(cells, len(row))
+	}
+	return min(max(480/max(cells, 1), 24), 120)
+}
+
+// changed reports whether a cell was marked by the move from previous
+func changed(grid, previous [][]string, row, col int) bool {
+	if previous == nil || grid[row][col] == Empty || grid[row][col] == "" {
+		return false
+	}
+	return row >= len(previous) || col >= len(previous[row]) || previous[row][col] != grid[row][col]
+}
+
+// boardImage draws a grid as a paletted image, the cells the last move
+// marked highlighted; previous is the grid before it, or nil
+func boardImage(grid, previous [][]string) *image.Paletted {
+	size, margin := cellSize(grid), 8
+	cols := 0
+	for _, row := range grid {
+		cols = max(cols, len(row))
+	}
+	img := image.NewPaletted(image.Rect(0, 0, cols*size+2*margin, len(grid)*size+2*margin), imagePalette)
+	fill := func(x0, y0, x1, y1 int, c color.Color) {
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				img.Set(x, y, c)
+			}
+		}
+	}
+	fill(0, 0, img.Rect.Dx(), img.Rect.Dy(), imageBackground)
+	stroke := max(size/12, 2)
+	for r, row := range grid {
+		for c, mark := range row {
+			if mark == "" {
+				continue
+			}
+			x0, y0 := margin+c*size, margin+r*size
+			background := imageCell
+			if changed(grid, previous, r, c) {
+				background = imageLast
+			}
+			fill(x0, y0, x0+size, y0+size, imageGrid)
+			fill(x0+1, y0+1, x0+size-1, y0+size-1, background)
+
+			// Marks are drawn pixel by pixel from their distance to the
+			// cell's center: X as two strokes, O as a ring
+			cx, cy, radius := float64(x0)+float64(size)/2, float64(y0)+float64(size)/2, float64(size)*0.32
+			for y := y0 + 1; y < y0+size-1; y++ {
+				for x := x0 + 1; x < x0+size-1; x++ {
+					dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+					switch mark {
+					case PlayerX:
+						if math.Abs(dx) <= radius && math.Abs(dy) <= radius &&
+							(math.Abs(dx-dy) <= float64(stroke)*0.7 || math.Abs(dx+dy) <= float64(stroke)*0.7) {
+							img.Set(x, y, imageX)
+						}
+					case PlayerO:
+						if math.Abs(math.Hypot(dx, dy)-radius) <= float64(stroke)/2 {
+							img.Set(x, y, imageO)
+						}
+					}
+				}
+			}
+		}
+	}
+	return img
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// boardSVG draws a grid as an SVG image with a caption underneath, the
+// cells the last move marked highlighted; previous is the grid before it,
+// or nil
+func boardSVG(grid, previous [][]string, caption string) string {
+	size, margin := cellSize(grid), 8
+	cols := 0
+	for _, row := range grid {
+		cols = max(cols, len(row))
+	}
+	// The caption may be wider than a small board
+	width, height := max(cols*size, len(caption)*7)+2*margin, len(grid)*size+2*margin+24
+	hex := func(c color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+	stroke := max(size/12, 2)
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(imageBackground))
+	for r, row := range grid {
+		for c, mark := range row {
+			if mark == "" {
+				continue
+			}
+			x, y := margin+c*size, margin+r*size
+			background := imageCell
+			if changed(grid, previous, r, c) {
+				background = imageLast
+			}
+			fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="%s"/>`+"\n", x, y, size, size, hex(background), hex(imageGrid))
+			cx, cy, radius := float64(x)+float64(size)/2, float64(y)+float64(size)/2, float64(size)*0.32
+			switch mark {
+			case PlayerX:
+				fmt.Fprintf(&svg, `<path d="M%.1f %.1fL%.1f %.1fM%.1f %.1fL%.1f %.1f" stroke="%s" stroke-width="%d" stroke-linecap="round"/>`+"\n",
+					cx-radius, cy-radius, cx+radius, cy+radius, cx+radius, cy-radius, cx-radius, cy+radius, hex(imageX), stroke)
+			case PlayerO:
+				fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="%s" stroke-width="%d"/>`+"\n", cx, cy, radius, hex(imageO), stroke)
+			}
+		}
+	}
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" font-family="sans-serif" font-size="13" fill="%s">%s</text>`+"\n",
+		margin, height-10, hex(imageText), xmlEscape(caption))
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+var xmlReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func xmlEscape(s string) string {
+	return xmlReplacer.Replace(s)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterVariant("tictactoe", "infinite", func() Game {
+		return &InfiniteTicTacToe{TicTacToe: *NewTicTacToe()}
+	})
+}
+
+// infiniteMarks is how many marks each player may have on the board
+const infiniteMarks = 3
+
+// InfiniteTicTacToe is sliding tic-tac-toe: each player keeps at most three
+// marks, and placing a fourth removes that player's oldest, so the board
+// never fills up and no game is drawn
+type InfiniteTicTacToe struct {
+	TicTacToe
+	placed []int // positions of the marks on the board, oldest first
+}
+
+// marksOf returns player's positions, oldest first
+func (t *InfiniteTicTacToe) marksOf(player string) []int {
+	var marks []int
+	for _, pos := range t.placed {
+		if t.Board[pos/3][pos%3] == player {
+			marks = append(marks, pos)
+		}
+	}
+	return marks
+}
+
+// nextToVanish returns the position of player's mark that their next move
+// removes, reporting false while they have fewer than three
+func (t *InfiniteTicTacToe) nextToVanish(player string) (int, bool) {
+	marks := t.marksOf(player)
+	if len(marks) < infiniteMarks {
+		return -1, false
+	}
+	return marks[0], true
+}
+
+func (t *InfiniteTicTacToe) Name() string { return "Infinite Tic-Tac-Toe" }
+
+func (t *InfiniteTicTacToe) Clone() Game {
+	clone := *t
+	clone.placed = append([]int(nil), t.placed...)
+	return &clone
+}
+
+// Play places the mark, then removes player's oldest if they now have four
+func (t *InfiniteTicTacToe) Play(player string, position int) bool {
+	oldest, full := t.nextToVanish(player)
+	if !t.TicTacToe.Play(player, position) {
+		return false
+	}
+	t.placed = append(t.placed, position)
+	if full {
+		t.Board[oldest/3][oldest%3] = Empty
+		for i, pos := range t.placed {
+			if pos == oldest {
+				t.placed = append(t.placed[:i], t.placed[i+1:]...)
+				break
+			}
+		}
+	}
+	return true
+}
+
+func (t *InfiniteTicTacToe) Rules(player string) string {
+	return fmt.Sprintf("You are playing Infinite Tic-Tac-Toe as player %s: each player may only have three marks on the board, "+
+		"and placing a fourth removes your OLDEST mark. Three in a row wins. Positions are numbered 0-8, left to right and top to bottom. "+
+		"Each turn, respond with a single position number.", player)
+}
+
+func (t *InfiniteTicTacToe) Context(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	prompt.WriteString(fmt.Sprintf("You are playing Infinite Tic-Tac-Toe as player %s.\n", player))
+	prompt.WriteString("Each player may only have THREE marks on the board. When you place a fourth, your OLDEST mark vanishes. " +
+		"Three in a row (row, column, or diagonal) wins, and there are no draws.\n\n")
+	prompt.WriteString(DescribeBoard(t.Board, moveHistory))
+
+	prompt.WriteString("\n*** VANISHING MARKS ***\n")
+	if pos, ok := t.nextToVanish(player); ok {
+		prompt.WriteString(fmt.Sprintf("⌛ Your mark at position %d VANISHES when you play this move, so it can't help you complete a line now.\n", pos))
+	} else {
+		prompt.WriteString(fmt.Sprintf("You have %d of your 3 marks on the board; none vanishes this move.\n", len(t.marksOf(player))))
+	}
+	if pos, ok := t.nextToVanish(opponent); ok {
+		prompt.WriteString(fmt.Sprintf("⌛ %s's mark at position %d vanishes when %s moves next.\n", opponent, pos, opponent))
+	}
+
+	if promptHints != HintsNone {
+		// The generic checks play the move out, so they already allow for the vanishing mark
+		winningMoves := ImmediateWins(t, player)
+		blockingMoves := ImmediateWins(t, opponent)
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		if len(winningMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("🎯 YOU CAN WIN NOW! Play position %d to get three in a row!\n", winningMoves[0]))
+		} else if len(blockingMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("⚠️  DANGER! %s can win next move at position %d, even after their oldest mark vanishes! You MUST BLOCK IT!\n", opponent, blockingMoves[0]))
+		} else {
+			prompt.WriteString("No immediate wins or threats detected. Play strategically.\n")
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. WIN: Complete three in a row with the marks that will still be on the board\n")
+		prompt.WriteString(fmt.Sprintf("2. BLOCK: Stop %s's line, remembering which of their marks vanishes first\n", opponent))
+		prompt.WriteString("3. STRATEGIC: Build lines from your newest marks, since your oldest disappears next\n")
+	}
+
+	return prompt.String()
+}
+
+func (t *InfiniteTicTacToe) Prompt(player string, moveHistory []Move) string {
+	return t.Context(player, moveHistory) + AnswerInstructions(t.Board)
+}
+
+// LoadPosition is not supported, since a board doesn't say which marks are oldest
+func (t *InfiniteTicTacToe) LoadPosition(board string) (string, error) {
+	return "", fmt.Errorf("infinite tic-tac-toe can't start from a board, which doesn't say which marks are oldest; use -random-start")
+}
+
+// SearchDepth limits minimax, since a game can go on forever
+func (t *InfiniteTicTacToe) SearchDepth() int {
+	return 8
+}
+
+// Evaluate counts the lines player could complete, less those the opponent
+// could, ignoring which marks are about to vanish
+func (t *InfiniteTicTacToe) Evaluate(player string) int {
+	wins, blocks := DetectThreats(t.Board, player)
+	return len(distinctPositions(wins)) - len(distinctPositions(blocks))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// LeaderboardEntry is one player's cumulative results in one game
+type LeaderboardEntry struct {
+	Model      string    `json:"model"`
+	Backend    string    `json:"backend"` // "engine" for minimax and the like, "human" for people
+	Games      int       `json:"games"`
+	Wins       int       `json:"wins"`
+	Draws      int       `json:"draws"`
+	Losses     int       `json:"losses"`
+	Errors     int       `json:"errors"` // games lost by failing to make a valid move
+	LastPlayed time.Time `json:"last_played"`
+}
+
+// Score is the share of points won, counting draws as half
+func (e *LeaderboardEntry) Score() float64 {
+	if e.Games == 0 {
+		return 0
+	}
+	return (float64(e.Wins) + float64(e.Draws)/2) / float64(e.Games)
+}
+
+// Leaderboard is every run's results, kept in a JSON file by game and then
+// by backend and model, so repeated runs keep adding to the same table
+type Leaderboard struct {
+	Path   string
+	ByGame map[string]map[string]*LeaderboardEntry
+}
+
+// DefaultLeaderboardPath is the leaderboard file in the user's config
+// directory, or in the working directory if there isn't one
+func DefaultLeaderboardPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "leaderboard.json"
+	}
+	return filepath.Join(dir, "llama-tac-toe", "leaderboard.json")
+}
+
+// LoadLeaderboard reads the leaderboard at path, starting afresh if it doesn't exist yet
+func LoadLeaderboard(path string) (*Leaderboard, error) {
+	l := &Leaderboard{Path: path, ByGame: map[string]map[string]*LeaderboardEntry{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &l.ByGame); err != nil {
+		return nil, fmt.Errorf("reading the leaderboard from %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// entry returns the entry for model on backend in game, creating it if needed
+func (l *Leaderboard) entry(game, backend, model string) *LeaderboardEntry {
+	players, ok := l.ByGame[game]
+	if !ok {
+		players = map[string]*LeaderboardEntry{}
+		l.ByGame[game] = players
+	}
+	key := backend + "/" + model
+	e, ok := players[key]
+	if !ok {
+		e = &LeaderboardEntry{Model: model, Backend: backend}
+		players[key] = e
+	}
+	return e
+}
+
+// Record adds a finished game for both players, given the backend each side
+// used, and saves the file. Self-play is skipped, as in the per-model
+// statistics.
+func (l *Leaderboard) Record(game string, result GameResult, agents map[string]Agent, backends map[string]string) error {
+	if agents[PlayerX].Name() == agents[PlayerO].Name() && backends[PlayerX] == backends[PlayerO] {
+		return nil
+	}
+	now := time.Now().UTC()
+	for _, player := range []string{PlayerX, PlayerO} {
+		e := l.entry(game, backends[player], agents[player].Name())
+		e.Games++
+		e.LastPlayed = now
+		switch result.Winner {
+		case player:
+			e.Wins++
+		case OtherPlayer(player):
+			e.Losses++
+		case "draw":
+			e.Draws++
+		case "error":
+			if result.FailedPlayer == player {
+				e.Errors++
+				e.Losses++
+			} else {
+				e.Wins++
+			}
+		}
+	}
+	return l.Save()
+}
+
+// Save writes the leaderboard, replacing the file in one step
+func (l *Leaderboard) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l.ByGame, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.Path), filepath.Base(l.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.Path)
+}
+
+// Print shows the leaderboard of each game, or just of game if it isn't
+// empty, ranking players by the share of points they've won
+func (l *Leaderboard) Print(game string) {
+	var games []string
+	for name := range l.ByGame {
+		if game == "" || name == game {
+			games = append(games, name)
+		}
+	}
+	if len(games) == 0 {
+		fmt.Printf("No games recorded in %s yet\n", l.Path)
+		return
+	}
+	sort.Strings(games)
+
+	fmt.Printf("Leaderboard (%s)\n", l.Path)
+	for _, name := range games {
+		var entries []*LeaderboardEntry
+		for _, e := range l.ByGame[name] {
+			entries = append(entries, e)
+		}
+		sort.Slice(entries, func(a, b int) bool {
+			if entries[a].Score() != entries[b].Score() {
+				return entries[a].Score() > entries[b].Score()
+			}
+			if entries[a].Games != entries[b].Games {
+				return entries[a].Games > entries[b].Games
+			}
+			return entries[a].Backend+"/"+entries[a].Model < entries[b].Backend+"/"+entries[b].Model
+		})
+
+		fmt.Printf("\n%s:\n", name)
+		fmt.Printf("  %-4s %-24s %-12s %6s %6s %6s %6s %6s %7s  %s\n", "Rank", "Model", "Backend", "Games", "W", "D", "L", "Errors", "Score", "Last played")
+		for rank, e := range entries {
+			fmt.Printf("  %-4d %-24s %-12s %6d %6d %6d %6d %6d %6.1f%%  %s\n", rank+1, shortName(e.Model, 24), shortName(e.Backend, 12),
+				e.Games, e.Wins, e.Draws, e.Losses, e.Errors, e.Score()*100, e.LastPlayed.Local().Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+// sideBackend labels the backend an agent plays on for the leaderboard: the
+// side's LLM backend, or "engine", "human", and "remote" for the players
+// with no model here
+func sideBackend(agent Agent, backend string) string {
+	switch agent.(type) {
+	case *HumanAgent, *DiscordAgent, *TwitchAgent:
+		return "human"
+	case *MinimaxAgent, *RandomAgent, *MCTSAgent, *HeuristicAgent:
+		return "engine"
+	case *RemoteAgent:
+		return "remote"
+	}
+	return backend
+}
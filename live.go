@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LiveState is a snapshot of the game in progress, for live views of a run
+type LiveState struct {
+	Version  int               `json:"version"` // goes up by one with every change
+	Game     int               `json:"game"`    // number of the game in this run, 0 before the first
+	Name     string            `json:"name"`    // e.g. "Tic-Tac-Toe"
+	Players  map[string]string `json:"players"` // model by symbol, X or O
+	Board    string            `json:"board"`   // as printed on the console
+	Moves    []string          `json:"moves"`   // moves played so far, described
+	ToMove   string            `json:"to_move,omitempty"`
+	Thinking bool              `json:"thinking"`           // whether the player to move is choosing their move
+	Prompt   string            `json:"prompt,omitempty"`   // latest prompt sent to an LLM
+	Response string            `json:"response,omitempty"` // the LLM's answer to it, its "thoughts"
+	Model    string            `json:"model,omitempty"`    // model that gave the answer
+	Result   string            `json:"result,omitempty"`   // how the game ended, once it has
+	Score    map[string]int    `json:"score"`              // finished games by result: X, O, draw, or error
+	Updated  time.Time         `json:"updated"`
+}
+
+// Status describes what's happening in a line, e.g. "Game 3: llama3.2 (X)
+// is thinking…", as the web page does
+func (s LiveState) Status() string {
+	switch {
+	case s.Game == 0:
+		return "Waiting for the first game…"
+	case s.Result != "":
+		return fmt.Sprintf("Game %d: %s", s.Game, s.Result)
+	case s.Thinking:
+		return fmt.Sprintf("Game %d: %s (%s) is thinking…", s.Game, s.Players[s.ToMove], s.ToMove)
+	case s.ToMove != "":
+		return fmt.Sprintf("Game %d: %s (%s) to move", s.Game, s.Players[s.ToMove], s.ToMove)
+	}
+	return fmt.Sprintf("Game %d", s.Game)
+}
+
+// ScoreLine sums up the finished games, e.g. "X 3  O 1  Draws 2"
+func (s LiveState) ScoreLine() string {
+	line := fmt.Sprintf("X %d  O %d  Draws %d", s.Score[PlayerX], s.Score[PlayerO], s.Score["draw"])
+	if errors := s.Score["error"]; errors > 0 {
+		line += fmt.Sprintf("  Errors %d", errors)
+	}
+	return line
+}
+
+// liveHistory is how many of the latest snapshots Live keeps for streams
+const liveHistory = 100
+
+// Live follows the game in progress through the agents it wraps, keeping a
+// snapshot for web pages and overlays and telling them when it changes
+type Live struct {
+	mu      sync.Mutex
+	state   LiveState
+	recent  []LiveState   // the latest snapshots, oldest first
+	changed chan struct{} // closed and replaced on every change
+}
+
+// NewLive creates a live view with no game yet
+func NewLive() *Live {
+	return &Live{state: LiveState{Players: map[string]string{}, Score: map[string]int{}, Updated: time.Now()}, changed: make(chan struct{})}
+}
+
+// State returns the latest snapshot, and a channel closed at the next change
+func (l *Live) State() (LiveState, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.snapshot(), l.changed
+}
+
+// Since returns the snapshots after version, oldest first, and a channel
+// closed at the next change. Snapshots too old to be kept are left out.
+func (l *Live) Since(version int) ([]LiveState, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if version >= l.state.Version {
+		return nil, l.changed
+	}
+	if len(l.recent) == 0 || l.recent[0].Version > version+1 {
+		// Before the first change or too far behind: start from the latest
+		return []LiveState{l.snapshot()}, l.changed
+	}
+	return append([]LiveState(nil), l.recent[version+1-l.recent[0].Version:]...), l.changed
+}
+
+func (l *Live) snapshot() LiveState {
+	state := l.state
+	state.Players, state.Score = copyMap(l.state.Players), copyMap(l.state.Score)
+	state.Moves = append([]string(nil), l.state.Moves...)
+	return state
+}
+
+// update changes the snapshot and tells anyone waiting for a change
+func (l *Live) update(change func(s *LiveState)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	change(&l.state)
+	l.state.Version++
+	l.state.Updated = time.Now()
+	l.recent = append(l.recent, l.snapshot())
+	if len(l.recent) > liveHistory {
+		l.recent = l.recent[1:]
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Agents wraps agents so the live view follows their moves
+func (l *Live) Agents(agents map[string]Agent) map[string]Agent {
+	wrapped := make(map[string]Agent, len(agents))
+	for player, agent := range agents {
+		wrapped[player] = &liveAgent{Agent: agent, live: l}
+	}
+	return wrapped
+}
+
+// StartGame shows a new game about to be played
+func (l *Live) StartGame(number int, game Game, agents map[string]Agent) {
+	board := showBoard(game)
+	l.update(func(s *LiveState) {
+		s.Game, s.Name, s.Board = number, game.Name(), board
+		s.Moves, s.ToMove, s.Thinking, s.Result = nil, "", false, ""
+		s.Prompt, s.Response, s.Model = "", "", ""
+		for player, agent := range agents {
+			s.Players[player] = agent.Name()
+		}
+	})
+}
+
+// EndGame shows a finished game's final position and result
+func (l *Live) EndGame(game Game, result GameResult) {
+	board, moves := showBoard(game), describeMoves(game, result.Moves)
+	l.update(func(s *LiveState) {
+		s.Board, s.Moves, s.ToMove, s.Thinking = board, moves, "", false
+		s.Result = describeResult(result)
+		s.Score[result.Winner]++
+	})
+}
+
+func showBoard(game Game) string {
+	var board bytes.Buffer
+	game.Display(&board)
+	return board.String()
+}
+
+// describeMoves names each move played, e.g. "X position 4"
+func describeMoves(game Game, history []Move) []string {
+	moves := make([]string, len(history))
+	for i, move := range history {
+		moves[i] = move.Player + " " + game.Describe(move.Position)
+		if move.Mark != "" {
+			moves[i] += " (" + move.Mark + ")"
+		}
+	}
+	return moves
+}
+
+func copyMap[V any](m map[string]V) map[string]V {
+	c := make(map[string]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// liveAgent reports an agent's moves to a live view
+type liveAgent struct {
+	Agent
+	live *Live
+}
+
+func (a *liveAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	board, moves := showBoard(game), describeMoves(game, moveHistory)
+	a.live.update(func(s *LiveState) {
+		s.Board, s.Moves, s.ToMove, s.Thinking = board, moves, player, true
+	})
+	result, err := a.Agent.ChooseMove(game, player, moveHistory)
+	a.live.update(func(s *LiveState) {
+		s.Thinking = false
+		if result.Prompt != "" {
+			s.Prompt, s.Response, s.Model = result.Prompt, result.Response, a.Name()
+			if err != nil && result.Response == "" {
+				s.Response = "(" + err.Error() + ")"
+			}
+		}
+	})
+	return result, err
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+const llamaCppDefaultURL = "http://localhost:8080"
+
+func init() {
+	RegisterProvider(&BasicProvider{
+		ID:   "llamacpp",
+		Info: "llama.cpp HTTP server /completion",
+		URL:  llamaCppDefaultURL,
+		Factory: func(cfg BackendConfig) (Backend, error) {
+			return &LlamaCppBackend{URL: cfg.URL, APIKey: cfg.APIKey, Headers: cfg.Headers}, nil
+		},
+	})
+}
+
+type LlamaCppRequest struct {
+	Prompt      string             `json:"prompt"`
+	Temperature float64            `json:"temperature"`
+	TopP        float64            `json:"top_p,omitempty"`
+	TopK        int                `json:"top_k,omitempty"`
+	Seed        int                `json:"seed,omitempty"`
+	NPredict    int                `json:"n_predict,omitempty"`
+	JSONSchema  map[string]any     `json:"json_schema,omitempty"`
+	Grammar     string             `json:"grammar,omitempty"`
+	LogitBias   map[string]float64 `json:"logit_bias,omitempty"`
+	Stream      bool               `json:"stream"`
+}
+
+// LlamaCppChatRequest adds llama.cpp's top_k and grammar extensions to a chat completions
+// request. llama.cpp tokenizes logit_bias keys itself, so they stay as text.
+type LlamaCppChatRequest struct {
+	OpenAIRequest
+	TopK      int                `json:"top_k,omitempty"`
+	Grammar   string             `json:"grammar,omitempty"`
+	LogitBias map[string]float64 `json:"logit_bias,omitempty"`
+}
+
+type LlamaCppResponse struct {
+	Content         string `json:"content"`
+	TokensEvaluated int    `json:"tokens_evaluated"`
+	TokensPredicted int    `json:"tokens_predicted"`
+}
+
+func (r LlamaCppResponse) usage() Usage {
+	return Usage{PromptTokens: r.TokensEvaluated, CompletionTokens: r.TokensPredicted}
+}
+
+// LlamaCppBackend talks to the llama.cpp HTTP server's /completion endpoint.
+// The server hosts a single model, so the request's model is not sent.
+type LlamaCppBackend struct {
+	URL     string
+	APIKey  string // matches the server's --api-key option
+	Headers map[string]string
+}
+
+// Generate sends the prompt, prefixed by any system prompt, for raw completion
+func (l *LlamaCppBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	baseURL := strings.TrimSuffix(l.URL, "/")
+	headers := bearerHeaders(l.APIKey, l.Headers)
+	if len(req.Messages) > 0 || len(req.Tools) > 0 {
+		// Conversations and tool calls go through the server's
+		// OpenAI-compatible endpoint so the model's chat template is applied
+		body := LlamaCppChatRequest{OpenAIRequest: newOpenAIRequest(req), TopK: req.TopK, Grammar: req.Grammar, LogitBias: req.LogitBias}
+		return postChatCompletion(ctx, baseURL+"/v1/chat/completions", headers, body, req.OnToken)
+	}
+
+	prompt := req.Prompt
+	if req.System != "" {
+		prompt = req.System + "\n\n" + prompt
+	}
+	reqBody := LlamaCppRequest{
+		Prompt:      prompt,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		TopK:        req.TopK,
+		Seed:        req.Seed,
+		NPredict:    req.MaxTokens,
+		JSONSchema:  req.Format,
+		Grammar:     req.Grammar,
+		LogitBias:   req.LogitBias,
+		Stream:      req.OnToken != nil,
+	}
+
+	if reqBody.Stream {
+		// Token counts arrive with the final chunk
+		var usage Usage
+		text, err := streamText(ctx, baseURL+"/completion", headers, reqBody, true, req.OnToken, func(data []byte) (string, error) {
+			var chunk LlamaCppResponse
+			err := json.Unmarshal(data, &chunk)
+			usage.Add(chunk.usage())
+			return chunk.Content, err
+		})
+		return LLMResponse{Text: text, Usage: usage}, err
+	}
+
+	var llamaResp LlamaCppResponse
+	if err := postJSON(ctx, baseURL+"/completion", headers, reqBody, &llamaResp); err != nil {
+		return LLMResponse{}, err
+	}
+	return LLMResponse{Text: llamaResp.Content, Usage: llamaResp.usage()}, nil
+}
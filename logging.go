@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// logLevel is the least severe level shown on the console: info by default,
+// debug with -v, and warnings with -q
+var logLevel = new(slog.LevelVar)
+
+// logFile, when -log-file is set, records everything logged at every level
+// as JSON lines
+var logFile slog.Handler
+
+// SetupLogging makes the default logger write to stdout at level, and to the
+// file at path too unless it's "". The returned function closes the file.
+func SetupLogging(level slog.Level, path string) (func() error, error) {
+	logLevel.Set(level)
+	closeFile := func() error { return nil }
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("can't open the log file: %w", err)
+		}
+		logFile = slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})
+		closeFile = file.Close
+	}
+	slog.SetDefault(newLogger(console{}))
+	return closeFile, nil
+}
+
+// LogTo moves the console's messages to out, as -output json does to keep
+// stdout for its events
+func LogTo(out io.Writer) {
+	slog.SetDefault(newLogger(console{out}))
+}
+
+// newLogger logs to w on the console and to the log file, if any; attrs are
+// added to the file's records only, e.g. the number of the game
+func newLogger(w io.Writer, attrs ...slog.Attr) *slog.Logger {
+	handlers := []slog.Handler{&consoleHandler{mu: new(sync.Mutex), out: w}}
+	if logFile != nil {
+		handlers = append(handlers, logFile.WithAttrs(attrs))
+	}
+	return slog.New(multiHandler(handlers))
+}
+
+// gameLogger logs a game's progress to out, where the game prints its board
+func gameLogger(out io.Writer, gameNumber int) *slog.Logger {
+	return newLogger(out, slog.Int("game", gameNumber))
+}
+
+// console writes to out, or if it's nil to whatever os.Stdout is at the time,
+// which -tui redirects after logging is set up, moving any progress bar out
+// of the way
+type console struct {
+	out io.Writer
+}
+
+func (c console) Write(p []byte) (int, error) {
+	if bar := progress; bar != nil {
+		bar.Clear()
+		defer bar.Redraw()
+	}
+	if c.out != nil {
+		return c.out.Write(p)
+	}
+	return os.Stdout.Write(p)
+}
+
+// consoleHandler writes records for people to read: the message, then any
+// attributes as key=value, with warnings and errors labelled as such
+type consoleHandler struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	attrs  string // preformatted, each with a leading space
+	prefix string // of the keys of attributes added from now on, for groups
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= logLevel.Level()
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var line strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+		line.WriteString("Error: ")
+	case r.Level >= slog.LevelWarn:
+		line.WriteString("Warning: ")
+	}
+	line.WriteString(r.Message)
+	line.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&line, h.prefix, a)
+		return true
+	})
+	line.WriteByte('\n')
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, line.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var line strings.Builder
+	for _, a := range attrs {
+		writeAttr(&line, h.prefix, a)
+	}
+	c := *h
+	c.attrs += line.String()
+	return &c
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.prefix += name + "."
+	return &c
+}
+
+// writeAttr writes " key=value", quoting values that need it
+func writeAttr(line *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, member := range a.Value.Group() {
+			writeAttr(line, prefix+a.Key+".", member)
+		}
+		return
+	}
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(line, " %s%s=%s", prefix, a.Key, value)
+}
+
+// multiHandler sends each record to every handler that takes its level
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
+	"log/slog"
+	"math/rand/v2"
+	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,18 +20,8 @@ type Board [3][3]string
 
 type Move struct {
 	Player   string
-	Position int
-}
-
-type OllamaRequest struct {
-	Model       string  `json:"model"`
-	Prompt      string  `json:"prompt"`
-	Stream      bool    `json:"stream"`
-	Temperature float64 `json:"temperature,omitempty"`
-}
-
-type OllamaResponse struct {
-	Response string `json:"response"`
+	Position int    // the game's move number, e.g. a tic-tac-toe position or Connect Four column
+	Mark     string // mark placed, in games where players choose it (see MarkChooser)
 }
 
 const (
@@ -37,17 +30,39 @@ const (
 	Empty   = " "
 )
 
-// DisplayBoard prints the current board state to the console
-func DisplayBoard(board Board) {
-	fmt.Println("\n  0 | 1 | 2")
-	fmt.Println(" -----------")
+// OtherPlayer returns the opponent of the given player
+func OtherPlayer(player string) string {
+	if player == PlayerX {
+		return PlayerO
+	}
+	return PlayerX
+}
+
+// DisplayBoard prints the current board state to w
+func DisplayBoard(w io.Writer, board Board) {
+	displayCells(w, func(row, col int) string { return board[row][col] })
+}
+
+// boardGrid returns a board's cells by row, for drawing it
+func boardGrid(board Board) [][]string {
+	grid := make([][]string, 3)
+	for row := range grid {
+		grid[row] = append([]string(nil), board[row][:]...)
+	}
+	return grid
+}
+
+// displayCells prints a 3x3 board whose cells read as cell returns them
+func displayCells(w io.Writer, cell func(row, col int) string) {
+	fmt.Fprintln(w, "\n  0 | 1 | 2")
+	fmt.Fprintln(w, " -----------")
 	for i := 0; i < 3; i++ {
-		fmt.Printf("%d %s | %s | %s\n", i, board[i][0], board[i][1], board[i][2])
+		fmt.Fprintf(w, "%d %s | %s | %s\n", i, cell(i, 0), cell(i, 1), cell(i, 2))
 		if i < 2 {
-			fmt.Println(" -----------")
+			fmt.Fprintln(w, " -----------")
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 // InitBoard creates a new empty board
@@ -108,6 +123,32 @@ func IsValidMove(board Board, row, col int) bool {
 	return board[row][col] == Empty
 }
 
+// AvailablePositions returns the empty positions (0-8) on the board
+func AvailablePositions(board Board) []int {
+	var positions []int
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if board[i][j] == Empty {
+				positions = append(positions, i*3+j)
+			}
+		}
+	}
+	return positions
+}
+
+// TakenPositions returns the occupied positions (0-8) on the board
+func TakenPositions(board Board) []int {
+	var positions []int
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if board[i][j] != Empty {
+				positions = append(positions, i*3+j)
+			}
+		}
+	}
+	return positions
+}
+
 // MakeMove places a player's mark on the board
 func MakeMove(board *Board, player string, row, col int) bool {
 	if IsValidMove(*board, row, col) {
@@ -117,6 +158,16 @@ func MakeMove(board *Board, player string, row, col int) bool {
 	return false
 }
 
+// ticTacToeLines lists all winning combinations: [3]int{pos1, pos2, pos3}
+var ticTacToeLines = [][3]int{
+	// Rows
+	{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+	// Columns
+	{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+	// Diagonals
+	{0, 4, 8}, {2, 4, 6},
+}
+
 // DetectThreats analyzes the board for winning and blocking opportunities
 func DetectThreats(board Board, player string) (winningMoves []int, blockingMoves []int) {
 	opponent := PlayerO
@@ -124,17 +175,7 @@ func DetectThreats(board Board, player string) (winningMoves []int, blockingMove
 		opponent = PlayerX
 	}
 
-	// All winning combinations: [3]int{pos1, pos2, pos3}
-	winningCombinations := [][3]int{
-		// Rows
-		{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
-		// Columns
-		{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
-		// Diagonals
-		{0, 4, 8}, {2, 4, 6},
-	}
-
-	for _, combo := range winningCombinations {
+	for _, combo := range ticTacToeLines {
 		pos1, pos2, pos3 := combo[0], combo[1], combo[2]
 		row1, col1 := pos1/3, pos1%3
 		row2, col2 := pos2/3, pos2%3
@@ -208,153 +249,98 @@ func DetectThreats(board Board, player string) (winningMoves []int, blockingMove
 	return winningMoves, blockingMoves
 }
 
-// BuildPrompt creates the prompt for the LLM with game history
+// BuildPrompt creates the prompt for the LLM with game history, from the
+// built-in template in prompts/tictactoe.tmpl
 func BuildPrompt(board Board, player string, moveHistory []Move) string {
-	var prompt strings.Builder
-
-	prompt.WriteString(fmt.Sprintf("You are playing Tic-Tac-Toe as player %s.\n\n", player))
-
-	// Show move history
-	if len(moveHistory) > 0 {
-		prompt.WriteString("Move history:\n")
-		for i, move := range moveHistory {
-			prompt.WriteString(fmt.Sprintf("%d. Player %s played position %d\n",
-				i+1, move.Player, move.Position))
-		}
-		prompt.WriteString("\n")
-	}
-
-	// Show current board state with position numbers for empty spaces
-	prompt.WriteString("Current board (empty spaces show their position number):\n")
-	prompt.WriteString("-------------\n")
-	for i := 0; i < 3; i++ {
-		prompt.WriteString("| ")
-		for j := 0; j < 3; j++ {
-			if board[i][j] == Empty {
-				prompt.WriteString(fmt.Sprintf("%d ", i*3+j))
-			} else {
-				prompt.WriteString(fmt.Sprintf("%s ", board[i][j]))
-			}
-			prompt.WriteString("| ")
-		}
-		prompt.WriteString("\n-------------\n")
-	}
-
-	// List available positions explicitly
-	var availablePositions []int
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			if board[i][j] == Empty {
-				availablePositions = append(availablePositions, i*3+j)
-			}
-		}
-	}
-
-	// List taken positions
-	var takenPositions []int
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			if board[i][j] != Empty {
-				takenPositions = append(takenPositions, i*3+j)
-			}
-		}
-	}
-
-	if len(takenPositions) > 0 {
-		prompt.WriteString("\n⛔ POSITIONS ALREADY TAKEN (DO NOT USE): ")
-		for i, pos := range takenPositions {
-			if i > 0 {
-				prompt.WriteString(", ")
-			}
-			prompt.WriteString(fmt.Sprintf("%d", pos))
-		}
-		prompt.WriteString("\n")
-	}
+	return renderPrompt("tictactoe.tmpl", boardPromptData(board, player, moveHistory))
+}
 
-	prompt.WriteString("\n✅ AVAILABLE POSITIONS (CHOOSE ONE OF THESE): ")
-	for i, pos := range availablePositions {
-		if i > 0 {
-			prompt.WriteString(", ")
-		}
-		prompt.WriteString(fmt.Sprintf("%d", pos))
-	}
-	prompt.WriteString("\n")
+// AnswerInstructions tells the LLM to answer with a single available position
+func AnswerInstructions(board Board) string {
+	return renderPrompt("instructions", boardPromptData(board, "", nil))
+}
 
-	// Detect threats on the board
-	winningMoves, blockingMoves := DetectThreats(board, player)
+// BuildToolPrompt creates a prompt asking the LLM to answer by calling the make_move tool
+func BuildToolPrompt(game Game, player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	prompt.WriteString(game.Context(player, moveHistory))
 
-	// Determine opponent
-	opponent := PlayerO
-	if player == PlayerO {
-		opponent = PlayerX
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	prompt.WriteString("1. You MUST choose ONLY from the AVAILABLE list above\n")
+	prompt.WriteString(fmt.Sprintf("2. Make your move by calling the make_move tool with one position from: %v\n", LegalPositions(game)))
+	if marks, ok := ChoosesMark(game); ok {
+		prompt.WriteString(fmt.Sprintf("   and the mark to place, one of: %v\n", marks.Marks()))
 	}
+	prompt.WriteString("3. Do NOT answer in text; call the tool exactly once\n")
 
-	// Explicitly tell the LLM about threats
-	prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
-	if len(winningMoves) > 0 {
-		prompt.WriteString(fmt.Sprintf("🎯 YOU CAN WIN NOW! Play position %d to win immediately!\n", winningMoves[0]))
-		prompt.WriteString(fmt.Sprintf("WINNING MOVE DETECTED: Position %d will give you three in a row!\n", winningMoves[0]))
-	} else if len(blockingMoves) > 0 {
-		prompt.WriteString(fmt.Sprintf("⚠️  DANGER! %s can win with position %d! You MUST BLOCK IT!\n", opponent, blockingMoves[0]))
-		prompt.WriteString(fmt.Sprintf("BLOCKING REQUIRED: If you don't play position %d, %s will win next turn!\n", blockingMoves[0], opponent))
-	} else {
-		prompt.WriteString("No immediate wins or threats detected. Play strategically.\n")
-		prompt.WriteString("Best strategy: Take center (4) if available, then corners (0,2,6,8), then edges (1,3,5,7)\n")
-	}
-	prompt.WriteString("*** END ANALYSIS ***\n")
+	return prompt.String()
+}
 
-	prompt.WriteString("\nSTRATEGY PRIORITY:\n")
-	prompt.WriteString("1. WIN: Play winning moves immediately\n")
-	prompt.WriteString(fmt.Sprintf("2. BLOCK: Block %s's winning moves immediately\n", opponent))
-	prompt.WriteString("3. STRATEGIC: Otherwise, prefer center (4), then corners (0,2,6,8), then edges (1,3,5,7)\n")
+// BuildJSONPrompt creates a prompt asking the LLM to answer with a JSON move and its reasoning
+func BuildJSONPrompt(game Game, player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	prompt.WriteString(game.Context(player, moveHistory))
 
 	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
-	prompt.WriteString("1. You MUST choose ONLY from the AVAILABLE POSITIONS list above\n")
-	if len(takenPositions) > 0 {
-		prompt.WriteString(fmt.Sprintf("2. NEVER choose positions that are taken: %v\n", takenPositions))
+	prompt.WriteString("1. You MUST choose ONLY from the AVAILABLE list above\n")
+	if marks, ok := ChoosesMark(game); ok {
+		prompt.WriteString(fmt.Sprintf("2. Respond with ONLY a JSON object: {\"position\": <number>, \"mark\": <one of %v>, \"reasoning\": \"<one sentence>\"}\n", marks.Marks()))
+	} else {
+		prompt.WriteString("2. Respond with ONLY a JSON object: {\"position\": <number>, \"reasoning\": \"<one sentence>\"}\n")
 	}
-	prompt.WriteString(fmt.Sprintf("3. ONLY respond with ONE number from: %v\n", availablePositions))
-	prompt.WriteString("4. Do NOT include any other text, explanation, or formatting\n")
-	prompt.WriteString("5. Your response should be a SINGLE digit only\n")
+	prompt.WriteString(fmt.Sprintf("3. The position MUST be one of: %v\n", LegalPositions(game)))
+	prompt.WriteString("4. Do NOT include any text outside the JSON object\n")
 
 	return prompt.String()
 }
 
-// CallLLM makes a request to Ollama API and returns the response and duration
-func CallLLM(prompt string, ollamaURL string, model string, temperature float64) (string, time.Duration, error) {
-	startTime := time.Now()
-
-	reqBody := OllamaRequest{
-		Model:       model,
-		Prompt:      prompt,
-		Stream:      false,
-		Temperature: temperature,
-	}
+// BuildReasoningPrompt creates a prompt asking the LLM to think the position
+// through step by step, then give its move on a final line
+func BuildReasoningPrompt(game Game, player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	prompt.WriteString(game.Context(player, moveHistory))
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", 0, err
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	prompt.WriteString("1. Think step by step: look for a move that wins now, then for an opponent's win you must block, then weigh the other moves\n")
+	prompt.WriteString(fmt.Sprintf("2. You MUST choose ONLY from the AVAILABLE list above: %v\n", LegalPositions(game)))
+	prompt.WriteString("3. End with your move alone on the last line as: MOVE: <move>\n")
+	if _, ok := ChoosesMark(game); ok {
+		prompt.WriteString("   where the move is the mark and the position, e.g. MOVE: O4\n")
+	} else if text, ok := game.(TextMoves); ok {
+		prompt.WriteString(fmt.Sprintf("   where the move is in %s\n", text.MoveFormat()))
 	}
 
-	resp, err := http.Post(ollamaURL+"/api/generate", "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", 0, err
-	}
-	defer resp.Body.Close()
+	return prompt.String()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", 0, err
+// BuildChatHistory turns the move history into a conversation from player's
+// point of view: its own moves are assistant turns and the opponent's moves
+// are user turns, after an opening user message explaining the game
+func BuildChatHistory(game Game, player string, moveHistory []Move) []ChatMessage {
+	if len(moveHistory) == 0 {
+		return nil
+	}
+	messages := []ChatMessage{{Role: "user", Content: game.Rules(player)}}
+	for _, move := range moveHistory {
+		if move.Player == player {
+			messages = appendChatMessage(messages, ChatMessage{Role: "assistant", Content: strconv.Itoa(move.Position)})
+		} else {
+			messages = appendChatMessage(messages, ChatMessage{Role: "user", Content: fmt.Sprintf("Player %s played %s.", move.Player, game.Describe(move.Position))})
+		}
 	}
+	return messages
+}
 
-	var ollamaResp OllamaResponse
-	err = json.Unmarshal(body, &ollamaResp)
-	if err != nil {
-		return "", 0, err
-	}
+// BuildGameContext describes the game state, threats, and strategy, without
+// any instructions on how to format the answer
+func BuildGameContext(board Board, player string, moveHistory []Move) string {
+	return renderPrompt("context", boardPromptData(board, player, moveHistory))
+}
 
-	duration := time.Since(startTime)
-	return ollamaResp.Response, duration, nil
+// DescribeBoard shows the move history, the board with numbered empty
+// positions, and the taken and available positions
+func DescribeBoard(board Board, moveHistory []Move) string {
+	return renderPrompt("board", boardPromptData(board, "", moveHistory))
 }
 
 // ParseMove extracts the position from LLM response
@@ -378,114 +364,253 @@ func ParseMove(response string) (int, error) {
 	return position, nil
 }
 
-type GameStats struct {
-	XWins              int
-	OWins              int
-	Draws              int
-	Errors             int
-	Total              int
-	TotalResponseTime  time.Duration
-	MinResponseTime    time.Duration
-	MaxResponseTime    time.Duration
-	ResponseCount      int
+// ParseFinalMove extracts the move from a response that reasons first and
+// states its move last, preferring an explicit "MOVE: n" line
+func ParseFinalMove(game Game, response string) (int, error) {
+	if matches := finalMovePattern.FindAllStringSubmatch(response, -1); len(matches) > 0 {
+		if position, err := game.ParseMove(matches[len(matches)-1][1]); err == nil {
+			return position, nil
+		}
+	}
+
+	// Fall back to the last non-empty line
+	lines := strings.Split(strings.TrimSpace(response), "\n")
+	position, err := game.ParseMove(lines[len(lines)-1])
+	if err != nil {
+		return -1, fmt.Errorf("no final move found in response: %s", strings.TrimSpace(response))
+	}
+	return position, nil
+}
+
+// finalMovePattern matches a "MOVE: n" line, capturing the move
+var finalMovePattern = regexp.MustCompile(`(?i)move\s*:\s*\**\s*(\S+)`)
+
+// finalMoveReasoning is what a response that reasons first says before its
+// final move: up to the last "MOVE:", or all but the last line without one
+func finalMoveReasoning(response string) string {
+	response = strings.TrimSpace(response)
+	if matches := finalMovePattern.FindAllStringIndex(response, -1); len(matches) > 0 {
+		return strings.TrimSpace(response[:matches[len(matches)-1][0]])
+	}
+	if i := strings.LastIndex(response, "\n"); i >= 0 {
+		return strings.TrimSpace(response[:i])
+	}
+	return ""
 }
 
-// PlayGame runs a single game and returns the winner ("X", "O", "draw", or "error")
-func PlayGame(ollamaURL, model string, maxRetries int, debug bool, gameNumber int, temperature float64, stats *GameStats) string {
-	// Initialize game
-	board := InitBoard()
+// Opening sets up the start of a game before the agents take over
+type Opening struct {
+	// ToMove is the player to move first from a -start-position, or "" to
+	// alternate the starting player by game number
+	ToMove string
+	// RandomMoves is the number of random legal moves to pre-play
+	RandomMoves int
+}
+
+// PlayGame runs a single game, printing its progress to out, and returns how it ended
+func PlayGame(out io.Writer, game Game, agents map[string]Agent, opening Opening, clock *Clock, maxRetries int, debug bool, gameNumber int, stats *GameStats) GameResult {
 	var moveHistory []Move
+	var usage Usage
+	var attempts []Attempt
+	start := time.Now()
+	retries, illegalMoves, parseFailures := 0, 0, 0
 	// Alternate starting player: odd games start with X, even games start with O
 	currentPlayer := PlayerX
 	if gameNumber%2 == 0 {
 		currentPlayer = PlayerO
 	}
+	if opening.ToMove != "" {
+		currentPlayer = opening.ToMove
+	}
+	firstPlayer := currentPlayer
+	// The position after any random opening, which the agents' moves are
+	// graded against perfect play from
+	var initial Game
+	opened := 0
+	logger := gameLogger(out, gameNumber)
+	finish := func(result GameResult) GameResult {
+		result.FirstPlayer, result.Moves, result.Attempts, result.Usage = firstPlayer, moveHistory, attempts, usage
+		result.Duration, result.Retries = time.Since(start), retries
+		result.IllegalMoves, result.ParseFailures = illegalMoves, parseFailures
+		if Solvable(initial) {
+			// Random opening moves stay ungraded
+			result.Grades = append(make([]MoveGrade, opened), GradeMoves(initial, moveHistory[opened:])...)
+			for i, grade := range result.Grades {
+				move := moveHistory[i]
+				switch grade {
+				case Inaccuracy:
+					logger.Info(fmt.Sprintf("Inaccuracy: Player %s playing %s lets a won game slip to a draw", move.Player, game.Describe(move.Position)))
+				case Blunder:
+					logger.Info(fmt.Sprintf("⚠️  Blunder: Player %s playing %s lets the opponent force a win", move.Player, game.Describe(move.Position)))
+				}
+			}
+		}
+		return result
+	}
 
 	if gameNumber > 0 {
-		fmt.Printf("\n=== Game %d (Starting player: %s) ===\n", gameNumber, currentPlayer)
+		fmt.Fprintf(out, "\n=== Game %d (Starting player: %s) ===\n", gameNumber, currentPlayer)
+	}
+	if clock != nil {
+		clock.Reset()
+	}
+
+	// Pre-play random moves, never one that ends the game
+	for i := 0; i < opening.RandomMoves; i++ {
+		var candidates []int
+		for _, position := range game.Legal() {
+			next := game.Clone()
+			if next.Play(currentPlayer, position) && next.Winner() == "" {
+				candidates = append(candidates, position)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		position := candidates[rand.IntN(len(candidates))]
+		game.Play(currentPlayer, position)
+		move := Move{Player: currentPlayer, Position: position}
+		if marks, ok := ChoosesMark(game); ok {
+			_, move.Mark = marks.DecodeMove(position)
+		}
+		moveHistory = append(moveHistory, move)
+		logger.Info(fmt.Sprintf("Random opening: Player %s plays %s", currentPlayer, game.Describe(position)))
+		currentPlayer = OtherPlayer(currentPlayer)
 	}
+	initial, opened = game.Clone(), len(moveHistory)
 
-	DisplayBoard(board)
+	displayGame(out, game, moveHistory)
 
 	// Game loop
 	for {
-		fmt.Printf("\n--- Player %s's turn ---\n", currentPlayer)
-
-		// Build prompt with move history
-		prompt := BuildPrompt(board, currentPlayer, moveHistory)
-
-		if debug {
-			fmt.Println("\n========== PROMPT DEBUG ==========")
-			fmt.Println(prompt)
-			fmt.Println("==================================\n")
-		}
+		fmt.Fprintf(out, "\n--- Player %s's turn ---\n", currentPlayer)
 
+		agent := agents[currentPlayer]
 		var position int
 		validMove := false
+		lastPrompt := ""
 
-		// Try to get a valid move from LLM
+		// Try to get a valid move from the agent
 		for retry := 0; retry < maxRetries; retry++ {
-			fmt.Printf("Requesting move from LLM (attempt %d/%d)...\n", retry+1, maxRetries)
+			if retry > 0 {
+				retries++
+			}
+			if _, isHuman := agent.(*HumanAgent); !isHuman {
+				logger.Debug(fmt.Sprintf("Requesting move from %s (attempt %d/%d)...", agent.Name(), retry+1, maxRetries))
+			}
 
-			response, duration, err := CallLLM(prompt, ollamaURL, model, temperature)
+			result, err := agent.ChooseMove(game, currentPlayer, moveHistory)
+			attempt := Attempt{Player: currentPlayer, Position: result.Position, Response: result.Response, Duration: result.Duration, Reasoning: result.Reasoning,
+				Override: result.Override, Prompt: result.Prompt, Transcript: result.Transcript, Usage: result.Usage}
 			if err != nil {
-				fmt.Printf("Error calling LLM: %v\n", err)
-				continue
+				attempt.Error = err.Error()
+			}
+			attempts = append(attempts, attempt)
+			if err == nil || result.Response != "" {
+				stats.Answers++
+				stats.Model(agent.Name()).Answers++
 			}
 
-			// Track response time
-			stats.TotalResponseTime += duration
-			stats.ResponseCount++
-			if stats.MinResponseTime == 0 || duration < stats.MinResponseTime {
-				stats.MinResponseTime = duration
+			if debug && result.Prompt != "" && result.Prompt != lastPrompt {
+				fmt.Fprintln(out, "\n========== PROMPT DEBUG ==========")
+				fmt.Fprintln(out, result.Prompt)
+				fmt.Fprintln(out, "==================================")
+				fmt.Fprintln(out)
+				lastPrompt = result.Prompt
 			}
-			if duration > stats.MaxResponseTime {
-				stats.MaxResponseTime = duration
+			if debug && result.Transcript != "" {
+				fmt.Fprintln(out, "\n========== TRANSCRIPT DEBUG ==========")
+				fmt.Fprint(out, result.Transcript)
+				fmt.Fprintln(out, "======================================")
+				fmt.Fprintln(out)
 			}
 
-			fmt.Printf("LLM response: %s (%.2fs)\n", strings.TrimSpace(response), duration.Seconds())
+			// Track response time and tokens, which are spent even on bad answers
+			if result.Duration > 0 {
+				stats.RecordResponse(agent.Name(), result.Duration)
+			}
+			usage.Add(result.Usage)
+			stats.Usage.Add(result.Usage)
+			stats.Model(agent.Name()).Usage.Add(result.Usage)
+			if result.Response != "" {
+				logger.Info(fmt.Sprintf("LLM response: %s (%.2fs)", strings.TrimSpace(result.Response), result.Duration.Seconds()),
+					"model", agent.Name(), "player", currentPlayer)
+			}
+			if clock != nil && clock.Charge(currentPlayer, result.Duration) {
+				winner := OtherPlayer(currentPlayer)
+				logger.Info(fmt.Sprintf("⏱️  Player %s ran out of time. Player %s wins on time!", currentPlayer, winner))
+				logger.Info(fmt.Sprintf("Total moves played: %d", len(moveHistory)))
+				return finish(GameResult{Winner: winner, LostOnTime: currentPlayer})
+			}
 
-			position, err = ParseMove(response)
 			if err != nil {
-				fmt.Printf("Error parsing move: %v\n", err)
+				logger.Info(capitalize(err.Error()), "model", agent.Name(), "player", currentPlayer)
+				if errors.Is(err, context.DeadlineExceeded) {
+					stats.Timeouts++
+					stats.Model(agent.Name()).Timeouts++
+				} else if result.Response != "" {
+					// The model answered, but not with a move
+					parseFailures++
+					stats.ParseFailures++
+					stats.Model(agent.Name()).ParseFailures++
+				}
 				continue
 			}
 
-			row := position / 3
-			col := position % 3
-
-			if MakeMove(&board, currentPlayer, row, col) {
+			position = result.Position
+			if game.Play(currentPlayer, position) {
 				validMove = true
-				moveHistory = append(moveHistory, Move{Player: currentPlayer, Position: position})
-				fmt.Printf("Player %s plays position %d (row %d, col %d)\n", currentPlayer, position, row, col)
+				attempts[len(attempts)-1].Legal = true
+				if result.Override != "" {
+					logger.Info(fmt.Sprintf("Engine override: %s", result.Override), "model", agent.Name(), "player", currentPlayer)
+					stats.Overrides++
+					stats.Model(agent.Name()).Overrides++
+				}
+				move := Move{Player: currentPlayer, Position: position}
+				cell := position
+				if marks, ok := ChoosesMark(game); ok {
+					cell, move.Mark = marks.DecodeMove(position)
+				}
+				if len(moveHistory) == 0 && opening.ToMove == "" {
+					// The game's first move, from the empty board
+					stats.Model(agent.Name()).recordOpening(cell, 1)
+				}
+				moveHistory = append(moveHistory, move)
+				logger.Info(fmt.Sprintf("Player %s plays %s", currentPlayer, game.Describe(position)))
+				if clock != nil {
+					clock.Moved(currentPlayer)
+					logger.Info(fmt.Sprintf("⏱️  Clock: %s", clock))
+				}
 				break
 			} else {
-				fmt.Printf("Invalid move: position %d is already taken or out of bounds\n", position)
+				logger.Info(fmt.Sprintf("Invalid move: %s is already taken or out of bounds", game.Describe(position)),
+					"model", agent.Name(), "player", currentPlayer)
+				illegalMoves++
+				stats.IllegalMoves++
+				stats.Model(agent.Name()).IllegalMoves++
 			}
 		}
 
 		if !validMove {
-			fmt.Printf("Player %s failed to make a valid move after %d attempts. Game over.\n", currentPlayer, maxRetries)
-			fmt.Printf("Total moves played: %d\n", len(moveHistory))
-			return "error"
+			logger.Info(fmt.Sprintf("Player %s failed to make a valid move after %d attempts. Game over.", currentPlayer, maxRetries))
+			logger.Info(fmt.Sprintf("Total moves played: %d", len(moveHistory)))
+			return finish(GameResult{Winner: "error", FailedPlayer: currentPlayer})
 		}
 
 		// Display updated board
-		DisplayBoard(board)
-
-		// Check for winner
-		winner := CheckWinner(board)
-		if winner != "" {
-			fmt.Printf("🎉 Player %s wins!\n", winner)
-			fmt.Printf("Total moves played: %d\n", len(moveHistory))
-			return winner
-		}
+		displayGame(out, game, moveHistory)
 
-		// Check for draw
-		if IsBoardFull(board) {
-			fmt.Println("🤝 It's a draw!")
-			fmt.Printf("Total moves played: %d\n", len(moveHistory))
-			return "draw"
+		// Check for a winner or a draw
+		switch winner := game.Winner(); winner {
+		case "":
+		case "draw":
+			logger.Info("🤝 It's a draw!")
+			logger.Info(fmt.Sprintf("Total moves played: %d", len(moveHistory)))
+			return finish(GameResult{Winner: "draw"})
+		default:
+			logger.Info(fmt.Sprintf("🎉 Player %s wins!", winner))
+			logger.Info(fmt.Sprintf("Total moves played: %d", len(moveHistory)))
+			return finish(GameResult{Winner: winner})
 		}
 
 		// Switch player
@@ -497,80 +622,273 @@ func PlayGame(ollamaURL, model string, maxRetries int, debug bool, gameNumber in
 	}
 }
 
-func main() {
-	// Configuration flags
-	ollamaURL := flag.String("url", "http://localhost:11434", "Ollama/LMStudio API URL")
-	model := flag.String("model", "llama3.2", "Model to use (e.g., llama3.2, llama3.1:70b, qwen2.5, mistral)")
-	maxRetries := flag.Int("retries", 3, "Maximum retries for invalid moves")
-	debug := flag.Bool("debug", false, "Show full prompts sent to LLM")
-	games := flag.Int("games", 1, "Number of games to play (0 for unlimited)")
-	temperature := flag.Float64("temperature", 0.7, "Temperature for LLM responses (0.0-2.0, higher = more random)")
-	flag.Parse()
-
-	fmt.Println("=== Tic-Tac-Toe: LLM vs LLM ===")
-	fmt.Printf("Using model: %s\n", *model)
-	fmt.Printf("Ollama URL: %s\n", *ollamaURL)
-	fmt.Printf("Max retries: %d\n", *maxRetries)
-	fmt.Printf("Temperature: %.2f\n", *temperature)
-	if *games == 0 {
-		fmt.Println("Games to play: Unlimited")
-	} else {
-		fmt.Printf("Games to play: %d\n", *games)
+// agentLabel returns a short description of an agent for the banner
+func agentLabel(agent Agent) string {
+	switch agent.(type) {
+	case *HumanAgent:
+		return "Human"
+	case *DiscordAgent:
+		return "Discord"
+	case *TwitchAgent:
+		return "Twitch"
+	case *RemoteAgent:
+		return "Remote"
+	case *MinimaxAgent:
+		return "Minimax"
+	case *RandomAgent:
+		return "Random"
+	case *MCTSAgent:
+		return "MCTS"
+	case *HeuristicAgent:
+		return "Heuristic"
+	}
+	return "LLM"
+}
+
+// headerFlags collects repeated -header key=value flags
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	var pairs []string
+	for k, v := range h {
+		pairs = append(pairs, k+"="+v)
 	}
+	return strings.Join(pairs, ", ")
+}
 
-	stats := GameStats{}
-	gameNumber := 1
+func (h headerFlags) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(k) == "" {
+		return fmt.Errorf("header must be key=value, got %q", value)
+	}
+	h[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	return nil
+}
 
-	// Game loop
-	for {
-		// Check if we've reached the game limit (unless unlimited)
-		if *games > 0 && gameNumber > *games {
-			break
+// optionFlags collects per-player generation options given as comma-separated
+// key=value pairs, e.g. -options-x temperature=0.2,seed=42
+type optionFlags map[string]string
+
+func (o optionFlags) String() string {
+	return headerFlags(o).String()
+}
+
+func (o optionFlags) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("option must be key=value, got %q", pair)
 		}
+		o[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return nil
+}
 
-		result := PlayGame(*ollamaURL, *model, *maxRetries, *debug, gameNumber, *temperature, &stats)
+// apply sets each option on the agent, in key order so errors are deterministic
+func (o optionFlags) apply(llm *LLMAgent) error {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := llm.SetOption(k, o[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		// Update statistics
-		stats.Total++
-		switch result {
-		case PlayerX:
-			stats.XWins++
-		case PlayerO:
-			stats.OWins++
-		case "draw":
-			stats.Draws++
-		case "error":
-			stats.Errors++
+// readSystemPrompt is a -system-x or -system-o prompt: the text given, or
+// with @FILE, the file's contents
+func readSystemPrompt(value string) (string, error) {
+	path, ok := strings.CutPrefix(value, "@")
+	if !ok {
+		return strings.TrimSpace(value), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("can't read the system prompt: %w", err)
+	}
+	prompt := strings.TrimSpace(string(data))
+	if prompt == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return prompt, nil
+}
+
+// applyPersona puts the named built-in persona, if any, at the start of
+// llm's system prompt
+func applyPersona(llm *LLMAgent, name string) error {
+	if name == "" {
+		return nil
+	}
+	persona, err := LookupPersona(name)
+	if err != nil {
+		return err
+	}
+	llm.Persona = persona.Name
+	llm.System = strings.TrimSpace(persona.Prompt + "\n\n" + llm.System)
+	return nil
+}
+
+// describeSystemPrompt is a -system-x or -system-o flag for the banner
+func describeSystemPrompt(value string) string {
+	switch {
+	case value == "":
+		return "(none)"
+	case strings.HasPrefix(value, "@"):
+		return value[1:]
+	case len([]rune(value)) > 40:
+		return strconv.Quote(string([]rune(value)[:37]) + "...")
+	}
+	return strconv.Quote(value)
+}
+
+// flagWasSet reports whether the named flag was given, on the command line,
+// in the environment, or in the -config file
+func flagWasSet(name string) bool {
+	return commandLine[name] || configured[name] != ""
+}
+
+// orDefault returns value, or fallback when value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// tripped reports whether any circuit breaker has opened with no fallback
+func tripped(breakers []*CircuitBreaker) bool {
+	for _, b := range breakers {
+		if b.Tripped() {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenCost prices token usage in dollars, given prices per million tokens
+func tokenCost(u Usage, priceIn, priceOut float64) float64 {
+	return (float64(u.PromptTokens)*priceIn + float64(u.CompletionTokens)*priceOut) / 1e6
+}
+
+// countTrue reports how many of the given flags are set
+func countTrue(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
 		}
+	}
+	return n
+}
 
-		gameNumber++
+// capitalize upper-cases the first letter of an error message for console output
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
 
-		// For unlimited games, allow graceful exit
-		if *games == 0 {
-			fmt.Println("\nPress Ctrl+C to stop, or the next game will start in 2 seconds...")
-			time.Sleep(2 * time.Second)
+func main() {
+	f := registerFlags()
+
+	// Subcommands: "llama-tac-toe SUBCOMMAND [flags]", play without one,
+	// each taking the flags that apply to it (see cli.go)
+	sub, args, err := ParseCommandLine(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if err := ApplyEnvironment(); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if *f.profile != "" && *f.configPath == "" {
+		fmt.Println("-profile needs the -config file that defines it")
+		os.Exit(2)
+	}
+	if *f.configPath != "" {
+		config, err := LoadConfig(*f.configPath, *f.profile)
+		if err == nil {
+			err = config.Apply()
 		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	sub.IgnoreOtherFlags()
+
+	if *f.verbose && *f.quiet {
+		fmt.Println("-v and -q can't be used together")
+		os.Exit(2)
+	}
+	level := slog.LevelInfo
+	if *f.verbose {
+		level = slog.LevelDebug
+	} else if *f.quiet {
+		level = slog.LevelWarn
+	}
+	closeLog, err := SetupLogging(level, *f.logPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer closeLog()
+
+	if *f.listBackends {
+		ListProviders()
+		return
+	}
+	switch sub.Name {
+	case "play":
+		runPlay(f)
+	case "tournament":
+		runTournament(f)
+	case "abtest":
+		runABTest(f)
+	case "serve":
+		runServe(f)
+	case "grpc":
+		runGRPC(f)
+	case "matchmaker":
+		runMatchmaker(f)
+	case "join":
+		runJoin(f)
+	case "bench":
+		runBench(f)
+	case "puzzle":
+		runPuzzle(f)
+	case "leaderboard":
+		runLeaderboard(f)
+	case "replay":
+		runReplay(f, args)
 	}
+}
+
+// webURL returns the URL of a page served on addr, e.g. "http://localhost:8080/" for ":8080"
+func webURL(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	return "http://" + addr + "/"
+}
 
-	// Print final statistics
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("FINAL STATISTICS")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("Total games played: %d\n", stats.Total)
-	fmt.Printf("Player X wins:      %d (%.1f%%)\n", stats.XWins, float64(stats.XWins)/float64(stats.Total)*100)
-	fmt.Printf("Player O wins:      %d (%.1f%%)\n", stats.OWins, float64(stats.OWins)/float64(stats.Total)*100)
-	fmt.Printf("Draws:              %d (%.1f%%)\n", stats.Draws, float64(stats.Draws)/float64(stats.Total)*100)
-	if stats.Errors > 0 {
-		fmt.Printf("Errors:             %d (%.1f%%)\n", stats.Errors, float64(stats.Errors)/float64(stats.Total)*100)
-	}
-	fmt.Println(strings.Repeat("-", 50))
-	if stats.ResponseCount > 0 {
-		avgResponseTime := stats.TotalResponseTime / time.Duration(stats.ResponseCount)
-		fmt.Printf("LLM Response Times:\n")
-		fmt.Printf("  Total calls:      %d\n", stats.ResponseCount)
-		fmt.Printf("  Average:          %.2fs\n", avgResponseTime.Seconds())
-		fmt.Printf("  Min:              %.2fs\n", stats.MinResponseTime.Seconds())
-		fmt.Printf("  Max:              %.2fs\n", stats.MaxResponseTime.Seconds())
-	}
-	fmt.Println(strings.Repeat("=", 50))
+// writeReport writes the Markdown report, if one was asked for
+func writeReport(w io.Writer, report *MarkdownReport, stats *GameStats) {
+	if report == nil {
+		return
+	}
+	if err := report.Write(stats); err != nil {
+		slog.Warn("couldn't write the report", "path", report.Path, "err", err)
+		return
+	}
+	fmt.Fprintf(w, "Report written to %s\n", report.Path)
 }
@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// matchPoll is how long a request for a player's turn waits for something
+// to happen before answering with the turn as it is
+const matchPoll = 25 * time.Second
+
+// Matchmaker pairs players that join over HTTP, such as other instances
+// with the join subcommand or custom bots, into games of the server's game,
+// in the order they joined, and plays them side by side. Players keep
+// being paired until they leave or have played the games they asked for.
+//
+// The API, all JSON:
+//
+//	POST   /players               join: {"name", "games"} gives {"id", "name"}
+//	GET    /players/{id}/turn     the player's RemoteTurn, once its version is past ?after=
+//	POST   /players/{id}/move     play {"position"} when the turn's status is "move"
+//	DELETE /players/{id}          leave, forfeiting any game in progress
+//	GET    /standings             players ranked by points, like a tournament
+type Matchmaker struct {
+	NewGame    func() Game
+	Opening    Opening
+	MaxRetries int
+	Debug      bool
+	Stats      *GameStats // the run's statistics, which every game is added to
+	// Timeout is how long a player may take over a move, or stay away
+	// while waiting for an opponent, before being dropped
+	Timeout time.Duration
+	// BeforeGame, if set, is called as every game starts, e.g. to announce it
+	BeforeGame func(agents map[string]Agent)
+	// AfterGame, if set, is called with every finished game, e.g. to
+	// record ratings and the leaderboard
+	AfterGame func(result GameResult, agents map[string]Agent)
+	// Stop, if set, is asked after every game whether matchmaking must
+	// end, e.g. because the budget is spent
+	Stop func() bool
+	// Out is where games print their moves, each game's all at once when
+	// it finishes, os.Stdout if nil
+	Out io.Writer
+
+	mu        sync.Mutex // also held while a finished game is recorded
+	players   map[string]*remotePlayer
+	queue     []*remotePlayer
+	games     int
+	stopped   bool
+	standings standingsTable
+}
+
+// RemoteTurn is what a player is to do, as /players/{id}/turn answers
+type RemoteTurn struct {
+	Version int `json:"version"` // goes up by one with every change
+	// Status is "queued" while waiting for an opponent, "waiting" during the
+	// opponent's turn, "move" when the player is to move, and "done" once
+	// the player won't be paired again
+	Status   string       `json:"status"`
+	Game     int          `json:"game,omitempty"` // number of the game on the server
+	Name     string       `json:"name,omitempty"` // e.g. "Tic-Tac-Toe"
+	Player   string       `json:"player,omitempty"`
+	Opponent string       `json:"opponent,omitempty"`
+	Board    string       `json:"board,omitempty"` // as printed on the console
+	Moves    []RecordMove `json:"moves,omitempty"`
+	Legal    []int        `json:"legal,omitempty"`
+	Finished int          `json:"finished"`       // games the player has finished
+	Last     *RemoteGame  `json:"last,omitempty"` // the last of them
+}
+
+// RemoteGame is a finished game, from one player's side
+type RemoteGame struct {
+	Game     int    `json:"game"`
+	Player   string `json:"player"`
+	Opponent string `json:"opponent"`
+	Winner   string `json:"winner"` // "X", "O", "draw", or "error"
+	Result   string `json:"result"` // e.g. "X wins"
+	Board    string `json:"board"`
+}
+
+// joinRequest and joinResponse are the bodies of POST /players
+type joinRequest struct {
+	Name  string `json:"name"`
+	Games int    `json:"games,omitempty"` // games to play, 0 for no limit
+}
+
+type joinResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// moveRequest is the body of POST /players/{id}/move
+type moveRequest struct {
+	Position int `json:"position"`
+}
+
+// remotePlayer is a player that joined, and its turn
+type remotePlayer struct {
+	id, name string
+	games    int       // games to play, 0 for no limit
+	seen     time.Time // when it last asked for its turn; guarded by the matchmaker's mu
+	done     bool      // whether it's left or been dropped; guarded by the matchmaker's mu
+
+	mu      sync.Mutex
+	turn    RemoteTurn
+	changed chan struct{} // closed and replaced on every change to turn
+	moves   chan int      // the move asked for, once played
+	left    chan struct{} // closed when the player leaves
+}
+
+// ServeMatchmaker serves the matchmaking API on addr, e.g. ":8090", in the background
+func ServeMatchmaker(addr string, m *Matchmaker) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("can't serve the matchmaker: %w", err)
+	}
+	m.players, m.standings = map[string]*remotePlayer{}, standingsTable{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /players", m.join)
+	mux.HandleFunc("GET /players/{id}/turn", m.turn)
+	mux.HandleFunc("POST /players/{id}/move", m.move)
+	mux.HandleFunc("DELETE /players/{id}", m.leave)
+	mux.HandleFunc("GET /standings", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		standings := m.standings.ranked()
+		m.mu.Unlock()
+		writeJSON(w, append([]Standing{}, standings...))
+	})
+	go http.Serve(listener, mux)
+	return nil
+}
+
+func (m *Matchmaker) join(w http.ResponseWriter, r *http.Request) {
+	var req joinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		http.Error(w, `a name is required, e.g. {"name": "llama3.2"}`, http.StatusBadRequest)
+		return
+	}
+	if req.Games < 0 {
+		http.Error(w, fmt.Sprintf("invalid games %d: must be at least 0", req.Games), http.StatusBadRequest)
+		return
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	p := &remotePlayer{
+		id:      hex.EncodeToString(id),
+		name:    strings.TrimSpace(req.Name),
+		games:   req.Games,
+		seen:    time.Now(),
+		turn:    RemoteTurn{Version: 1, Status: "queued"},
+		changed: make(chan struct{}),
+		moves:   make(chan int, 1),
+		left:    make(chan struct{}),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		http.Error(w, "matchmaking has ended", http.StatusServiceUnavailable)
+		return
+	}
+	m.players[p.id] = p
+	m.queue = append(m.queue, p)
+	slog.Info(fmt.Sprintf("%s joined", p.name))
+	m.pair()
+	writeJSON(w, joinResponse{ID: p.id, Name: p.name})
+}
+
+// player finds the player a request is about, answering 404 if there's none
+func (m *Matchmaker) player(w http.ResponseWriter, r *http.Request) *remotePlayer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.players[r.PathValue("id")]
+	if !ok {
+		http.Error(w, "no such player; join first", http.StatusNotFound)
+		return nil
+	}
+	p.seen = time.Now()
+	return p
+}
+
+func (m *Matchmaker) turn(w http.ResponseWriter, r *http.Request) {
+	p := m.player(w, r)
+	if p == nil {
+		return
+	}
+	after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+	timeout := time.After(matchPoll)
+	for {
+		turn, changed := p.state()
+		if turn.Version > after {
+			writeJSON(w, turn)
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			writeJSON(w, turn)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (m *Matchmaker) move(w http.ResponseWriter, r *http.Request) {
+	p := m.player(w, r)
+	if p == nil {
+		return
+	}
+	var req moveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `a position is required, e.g. {"position": 4}`, http.StatusBadRequest)
+		return
+	}
+	yours := false
+	p.update(func(t *RemoteTurn) {
+		if yours = t.Status == "move"; yours {
+			t.Status = "waiting"
+		}
+	})
+	if !yours {
+		http.Error(w, "it's not your turn", http.StatusConflict)
+		return
+	}
+	p.moves <- req.Position
+	turn, _ := p.state()
+	writeJSON(w, turn)
+}
+
+func (m *Matchmaker) leave(w http.ResponseWriter, r *http.Request) {
+	p := m.player(w, r)
+	if p == nil {
+		return
+	}
+	m.mu.Lock()
+	m.remove(p)
+	m.mu.Unlock()
+	slog.Info(fmt.Sprintf("%s left", p.name))
+	writeJSON(w, struct{}{})
+}
+
+// remove takes a player out of matchmaking, ending its game in progress;
+// its turn stays "done" for it to see. m.mu must be held.
+func (m *Matchmaker) remove(p *remotePlayer) {
+	if p.done {
+		return
+	}
+	p.done = true
+	for i, queued := range m.queue {
+		if queued == p {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			break
+		}
+	}
+	close(p.left)
+	p.update(func(t *RemoteTurn) {
+		t.Status, t.Game, t.Name, t.Player, t.Opponent = "done", 0, "", "", ""
+		t.Board, t.Moves, t.Legal = "", nil, nil
+	})
+}
+
+// pair starts a game for every two players in the queue, the first to join
+// playing X, after dropping those that stopped asking for their turn; m.mu
+// must be held
+func (m *Matchmaker) pair() {
+	for _, p := range append([]*remotePlayer(nil), m.queue...) {
+		if time.Since(p.seen) > m.Timeout {
+			slog.Info(fmt.Sprintf("%s hasn't been seen for %s; dropping it", p.name, m.Timeout))
+			m.remove(p)
+		}
+	}
+	for len(m.queue) >= 2 {
+		x, o := m.queue[0], m.queue[1]
+		m.queue = m.queue[2:]
+		m.games++
+		go m.play(m.games, x, o)
+	}
+}
+
+// play plays a game between two players, then queues them again
+func (m *Matchmaker) play(number int, x, o *remotePlayer) {
+	game := m.NewGame()
+	agents := map[string]Agent{
+		PlayerX: &RemoteAgent{player: x, timeout: m.Timeout},
+		PlayerO: &RemoteAgent{player: o, timeout: m.Timeout},
+	}
+	for player, p := range map[string]*remotePlayer{PlayerX: x, PlayerO: o} {
+		opponent := agents[OtherPlayer(player)].Name()
+		p.update(func(t *RemoteTurn) {
+			t.Status, t.Game, t.Name, t.Player, t.Opponent = "waiting", number, game.Name(), player, opponent
+			t.Board, t.Moves, t.Legal = showBoard(game), nil, nil
+		})
+	}
+	slog.Info(fmt.Sprintf("Game %d: %s (X) vs %s (O)", number, x.name, o.name))
+
+	var out bytes.Buffer
+	gameStats := NewGameStats()
+	if m.BeforeGame != nil {
+		m.BeforeGame(agents)
+	}
+	result := PlayGame(&out, game, agents, m.Opening, nil, m.MaxRetries, m.Debug, number, gameStats)
+
+	final := m.NewGame()
+	for _, move := range result.Moves {
+		final.Play(move.Player, move.Position)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.out().Write(out.Bytes())
+	m.Stats.Merge(gameStats)
+	if m.AfterGame != nil {
+		m.AfterGame(result, agents)
+	}
+	m.standings.record(x.name, o.name, result)
+	if !m.stopped && m.Stop != nil && m.Stop() {
+		m.stopped = true
+		for _, p := range append([]*remotePlayer(nil), m.queue...) {
+			m.remove(p)
+		}
+	}
+	for player, p := range map[string]*remotePlayer{PlayerX: x, PlayerO: o} {
+		last := &RemoteGame{Game: number, Player: player, Opponent: agents[OtherPlayer(player)].Name(),
+			Winner: result.Winner, Result: describeResult(result), Board: showBoard(final)}
+		finished := 0
+		p.update(func(t *RemoteTurn) {
+			t.Finished++
+			t.Last, finished = last, t.Finished
+			if !p.done {
+				t.Status, t.Game, t.Name, t.Player, t.Opponent = "queued", 0, "", "", ""
+				t.Board, t.Moves, t.Legal = "", nil, nil
+			}
+		})
+		if m.stopped || (p.games > 0 && finished >= p.games) {
+			m.remove(p)
+		} else if !p.done {
+			m.queue = append(m.queue, p)
+		}
+	}
+	m.pair()
+}
+
+func (m *Matchmaker) out() io.Writer {
+	if m.Out == nil {
+		return os.Stdout
+	}
+	return m.Out
+}
+
+// state returns the player's turn and a channel closed at its next change
+func (p *remotePlayer) state() (RemoteTurn, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.turn, p.changed
+}
+
+// update changes the player's turn and tells anyone waiting for it
+func (p *remotePlayer) update(change func(t *RemoteTurn)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	change(&p.turn)
+	p.turn.Version++
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// RemoteAgent is a player that joined the matchmaker, asked for its moves
+// through its turn
+type RemoteAgent struct {
+	player  *remotePlayer
+	timeout time.Duration
+}
+
+// Name is the name the player joined with
+func (a *RemoteAgent) Name() string {
+	return a.player.name
+}
+
+// ChooseMove asks the player to move and waits until it does, leaves, or
+// runs out of time
+func (a *RemoteAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	start := time.Now()
+	select {
+	case <-a.player.moves:
+		// A move played after the last one was asked for; it's stale now
+	default:
+	}
+	moves := make([]RecordMove, len(moveHistory))
+	for i, move := range moveHistory {
+		moves[i] = RecordMove{Player: move.Player, Position: move.Position, Mark: move.Mark}
+	}
+	board, legal := showBoard(game), game.Legal()
+	a.player.update(func(t *RemoteTurn) {
+		t.Status, t.Board, t.Moves, t.Legal = "move", board, moves, legal
+	})
+	select {
+	case position := <-a.player.moves:
+		return MoveResult{Position: position, Duration: time.Since(start)}, nil
+	case <-a.player.left:
+		return MoveResult{Position: -1}, errors.New(a.player.name + " left")
+	case <-time.After(a.timeout):
+		a.player.update(func(t *RemoteTurn) {
+			if t.Status == "move" {
+				t.Status = "waiting"
+			}
+		})
+		return MoveResult{Position: -1, Duration: time.Since(start)}, fmt.Errorf("%s didn't move within %s", a.player.name, a.timeout)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(v)
+}
+
+// JoinMatchmaker joins the matchmaker at server as agent and plays the games
+// it's paired into, games of them or until the server ends matchmaking if 0
+func JoinMatchmaker(out io.Writer, server string, agent Agent, newGame func() Game, games int) error {
+	server = strings.TrimSuffix(server, "/")
+	var joined joinResponse
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	err := postJSON(ctx, server+"/players", nil, joinRequest{Name: agent.Name(), Games: games}, &joined)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("can't join the matchmaker at %s: %w", server, err)
+	}
+	fmt.Fprintf(out, "Joined the matchmaker at %s as %s; waiting for an opponent\n", server, joined.Name)
+	player := server + "/players/" + joined.ID
+	finished, version, failures := 0, 0, 0
+	for {
+		var turn RemoteTurn
+		ctx, cancel := context.WithTimeout(context.Background(), matchPoll+webhookTimeout)
+		err := getJSON(ctx, fmt.Sprintf("%s/turn?after=%d", player, version), nil, &turn)
+		cancel()
+		if err != nil {
+			if failures++; !IsRetryable(err) || failures > 5 {
+				return fmt.Errorf("lost the matchmaker: %w", err)
+			}
+			time.Sleep(time.Duration(failures) * time.Second)
+			continue
+		}
+		failures, version = 0, turn.Version
+		if turn.Finished > finished && turn.Last != nil {
+			finished = turn.Finished
+			fmt.Fprintf(out, "Game %d vs %s as %s: %s\n%s\n", turn.Last.Game, turn.Last.Opponent, turn.Last.Player, turn.Last.Result, turn.Last.Board)
+		}
+		switch turn.Status {
+		case "done":
+			fmt.Fprintf(out, "Played %d games\n", finished)
+			return nil
+		case "move":
+			game := newGame()
+			if game.Name() != turn.Name {
+				return fmt.Errorf("the matchmaker plays %s, not %s; set -game to match", turn.Name, game.Name())
+			}
+			history := make([]Move, len(turn.Moves))
+			for i, move := range turn.Moves {
+				game.Play(move.Player, move.Position)
+				history[i] = Move{Player: move.Player, Position: move.Position, Mark: move.Mark}
+			}
+			result, err := agent.ChooseMove(game, turn.Player, history)
+			if err != nil {
+				slog.Warn("couldn't choose a move", "err", err)
+			} else {
+				fmt.Fprintf(out, "Game %d vs %s as %s: %s\n", turn.Game, turn.Opponent, turn.Player, game.Describe(result.Position))
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+			err = postJSON(ctx, player+"/move", nil, moveRequest{Position: result.Position}, &turn)
+			cancel()
+			if err != nil {
+				slog.Warn("couldn't send the move", "err", err)
+			} else {
+				version = turn.Version
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// MCTSAgent chooses moves with Monte Carlo Tree Search using random playouts
+type MCTSAgent struct {
+	Simulations int
+}
+
+// Name identifies the MCTS agent
+func (m *MCTSAgent) Name() string {
+	return "mcts"
+}
+
+// mctsNode is one position in the search tree
+type mctsNode struct {
+	game     Game
+	toMove   string // player to move in this position
+	position int    // move that led here from the parent
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []int
+	visits   int
+	score    float64 // results from the perspective of the player who moved into this node
+}
+
+// ChooseMove runs the configured number of simulations and plays the most visited move
+func (m *MCTSAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	root := newMCTSNode(game, player, -1, nil)
+	for i := 0; i < max(m.Simulations, 1); i++ {
+		node := root
+
+		// Selection: descend through fully expanded nodes
+		for len(node.untried) == 0 && len(node.children) > 0 {
+			node = node.bestChild()
+		}
+
+		// Expansion: add one unexplored move
+		if len(node.untried) > 0 {
+			idx := rand.IntN(len(node.untried))
+			pos := node.untried[idx]
+			node.untried = append(node.untried[:idx], node.untried[idx+1:]...)
+			next := node.game.Clone()
+			next.Play(node.toMove, pos)
+			child := newMCTSNode(next, OtherPlayer(node.toMove), pos, node)
+			node.children = append(node.children, child)
+			node = child
+		}
+
+		// Simulation and backpropagation
+		winner := randomPlayout(node.game, node.toMove)
+		for ; node != nil; node = node.parent {
+			node.visits++
+			mover := OtherPlayer(node.toMove)
+			switch winner {
+			case mover:
+				node.score++
+			case "draw":
+				node.score += 0.5
+			}
+		}
+	}
+
+	if len(root.children) == 0 {
+		// The game is over, so there's no move, as with minimax
+		return MoveResult{Position: -1}, nil
+	}
+	best := root.children[0]
+	for _, child := range root.children[1:] {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+	return MoveResult{Position: best.position}, nil
+}
+
+func newMCTSNode(game Game, toMove string, position int, parent *mctsNode) *mctsNode {
+	node := &mctsNode{game: game, toMove: toMove, position: position, parent: parent}
+	if game.Winner() == "" {
+		node.untried = game.Legal()
+	}
+	return node
+}
+
+// bestChild picks the child with the highest UCT value
+func (n *mctsNode) bestChild() *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	for _, child := range n.children {
+		value := child.score/float64(child.visits) +
+			math.Sqrt2*math.Sqrt(math.Log(float64(n.visits))/float64(child.visits))
+		if value > bestValue {
+			best, bestValue = child, value
+		}
+	}
+	return best
+}
+
+// randomPlayout plays random moves to the end and returns "X", "O", or "draw"
+func randomPlayout(game Game, toMove string) string {
+	game = game.Clone()
+	for {
+		if winner := game.Winner(); winner != "" {
+			return winner
+		}
+		available := game.Legal()
+		game.Play(toMove, available[rand.IntN(len(available))])
+		toMove = OtherPlayer(toMove)
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+func TestMCTSFinishedGame(t *testing.T) {
+	for _, board := range []string{"XXX/OO./...", "XOX/XOO/OXX"} {
+		result, err := (&MCTSAgent{Simulations: 100}).ChooseMove(loadTicTacToe(t, board), PlayerO, nil)
+		if err != nil || result.Position != -1 {
+			t.Errorf("ChooseMove on %s = %d, %v, want -1 with no legal moves", board, result.Position, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the LLM response time histogram
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Metrics counts games, results, illegal answers, LLM response times, and
+// backend errors for Prometheus to scrape from /metrics. Games are counted as
+// they finish.
+type Metrics struct {
+	mu            sync.Mutex
+	games         map[string]int    // by result: X, O, draw, or error
+	results       map[[3]string]int // by model, player, and outcome: win, loss, draw, or error
+	illegal       map[[2]string]int // by model and player
+	backendErrors map[[2]string]int // by model and player
+	latency       map[[2]string]*histogram
+}
+
+// histogram is a cumulative Prometheus histogram over latencyBuckets
+type histogram struct {
+	counts []int // one per bucket, each counting observations at or below its bound
+	count  int
+	sum    float64
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range latencyBuckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// NewMetrics creates empty metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		games:         map[string]int{},
+		results:       map[[3]string]int{},
+		illegal:       map[[2]string]int{},
+		backendErrors: map[[2]string]int{},
+		latency:       map[[2]string]*histogram{},
+	}
+}
+
+// Record adds a finished game
+func (m *Metrics) Record(result GameResult, agents map[string]Agent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.games[result.Winner]++
+	for _, player := range []string{PlayerX, PlayerO} {
+		outcome := ""
+		switch result.Winner {
+		case player:
+			outcome = "win"
+		case OtherPlayer(player):
+			outcome = "loss"
+		case "draw":
+			outcome = "draw"
+		case "error":
+			// A game a player can't finish is forfeited to the other
+			outcome = "win"
+			if result.FailedPlayer == player {
+				outcome = "error"
+			}
+		}
+		m.results[[3]string{agents[player].Name(), player, outcome}]++
+	}
+	for _, a := range result.Attempts {
+		key := [2]string{agents[a.Player].Name(), a.Player}
+		if a.Duration > 0 {
+			h, ok := m.latency[key]
+			if !ok {
+				h = &histogram{counts: make([]int, len(latencyBuckets))}
+				m.latency[key] = h
+			}
+			h.observe(a.Duration.Seconds())
+		}
+		switch {
+		case a.Error != "" && a.Response == "":
+			// The backend never answered
+			m.backendErrors[key]++
+		case !a.Legal:
+			m.illegal[key]++
+		}
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	writeHeader(w, "llmtactoe_games_total", "counter", "Games finished, by result (X, O, draw, or error)")
+	for _, result := range sortedKeys(m.games) {
+		fmt.Fprintf(w, "llmtactoe_games_total{result=%s} %d\n", labelValue(result), m.games[result])
+	}
+
+	writeHeader(w, "llmtactoe_model_results_total", "counter", "Games finished by each model and side, by outcome (win, loss, draw, or error)")
+	for _, key := range sortedKeys(m.results) {
+		fmt.Fprintf(w, "llmtactoe_model_results_total{model=%s,player=%s,outcome=%s} %d\n",
+			labelValue(key[0]), labelValue(key[1]), labelValue(key[2]), m.results[key])
+	}
+
+	writeHeader(w, "llmtactoe_illegal_moves_total", "counter", "Answers that couldn't be parsed or named an unavailable move")
+	for _, key := range sortedKeys(m.illegal) {
+		fmt.Fprintf(w, "llmtactoe_illegal_moves_total{%s} %d\n", modelLabels(key), m.illegal[key])
+	}
+
+	writeHeader(w, "llmtactoe_backend_errors_total", "counter", "Move requests the LLM backend failed to answer, including timeouts")
+	for _, key := range sortedKeys(m.backendErrors) {
+		fmt.Fprintf(w, "llmtactoe_backend_errors_total{%s} %d\n", modelLabels(key), m.backendErrors[key])
+	}
+
+	writeHeader(w, "llmtactoe_llm_response_seconds", "histogram", "Time spent waiting on the LLM for each answer")
+	for _, key := range sortedKeys(m.latency) {
+		h, labels := m.latency[key], modelLabels(key)
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "llmtactoe_llm_response_seconds_bucket{%s,le=\"%g\"} %d\n", labels, bound, h.counts[i])
+		}
+		fmt.Fprintf(w, "llmtactoe_llm_response_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(w, "llmtactoe_llm_response_seconds_sum{%s} %g\n", labels, h.sum)
+		fmt.Fprintf(w, "llmtactoe_llm_response_seconds_count{%s} %d\n", labels, h.count)
+	}
+}
+
+// ServeMetrics serves m at /metrics on addr, e.g. ":9090", in the background
+func ServeMetrics(addr string, m *Metrics) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("can't serve metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	go http.Serve(listener, mux)
+	return nil
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// modelLabels writes the model and player labels of a metric
+func modelLabels(key [2]string) string {
+	return "model=" + labelValue(key[0]) + ",player=" + labelValue(key[1])
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue quotes a label value as the Prometheus text format requires
+func labelValue(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+// sortedKeys returns the keys of a metric's map in order, so scrapes are stable
+func sortedKeys[K string | [2]string | [3]string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+	return keys
+}
@@ -0,0 +1,98 @@
+package main
+
+import "math"
+
+// MinimaxAgent plays perfect tic-tac-toe by searching the full game tree. In
+// games too large for that it searches Depth moves ahead (0 for the game's
+// default) and scores the positions it stops at with the game's evaluation.
+type MinimaxAgent struct {
+	Depth int
+}
+
+// Name identifies the minimax agent
+func (m *MinimaxAgent) Name() string {
+	return "minimax"
+}
+
+// ChooseMove returns the first optimal move for player
+func (m *MinimaxAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	position, _ := SearchMove(game, player, m.Depth)
+	return MoveResult{Position: position}, nil
+}
+
+// Evaluator is implemented by games too large to search to the end
+type Evaluator interface {
+	// SearchDepth is the default number of moves to search ahead
+	SearchDepth() int
+	// Evaluate scores an unfinished position from player's point of view,
+	// well within ±winScore
+	Evaluate(player string) int
+}
+
+// winScore is the score of a won position, less one per move it takes
+const winScore = 1000
+
+// BestMove returns the optimal position for player along with its minimax score
+// (positive: forced win, zero: draw, negative: forced loss), searching as deep
+// as the game allows by default
+func BestMove(game Game, player string) (position int, score int) {
+	return SearchMove(game, player, 0)
+}
+
+// SearchMove is BestMove limited to depth moves ahead, 0 for the game's
+// default. Faster wins and slower losses score higher in magnitude so the
+// engine never dawdles.
+func SearchMove(game Game, player string, depth int) (position int, score int) {
+	if depth <= 0 {
+		depth = math.MaxInt
+		if e, ok := game.(Evaluator); ok {
+			depth = e.SearchDepth()
+		}
+	}
+
+	position, score = -1, -winScore-1
+	for _, pos := range game.Legal() {
+		next := game.Clone()
+		next.Play(player, pos)
+		// Only a strictly better move can replace the best so far, so the
+		// window can start at the current best score
+		s := -negamax(next, OtherPlayer(player), 1, depth, -winScore-1, -score)
+		if s > score {
+			position, score = pos, s
+		}
+	}
+	return position, score
+}
+
+// negamax scores the game from the perspective of the player to move, with
+// alpha-beta pruning
+func negamax(game Game, toMove string, ply, depth, alpha, beta int) int {
+	switch winner := game.Winner(); winner {
+	case "":
+	case "draw":
+		return 0
+	case toMove:
+		return winScore - ply
+	default:
+		return ply - winScore
+	}
+	if ply >= depth {
+		if e, ok := game.(Evaluator); ok {
+			return e.Evaluate(toMove)
+		}
+		return 0
+	}
+
+	best := -winScore - 1
+	for _, pos := range game.Legal() {
+		next := game.Clone()
+		next.Play(toMove, pos)
+		s := -negamax(next, OtherPlayer(toMove), ply+1, depth, -beta, -alpha)
+		best = max(best, s)
+		alpha = max(alpha, s)
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
@@ -0,0 +1,125 @@
+package main
+
+import "testing"
+
+// loadTicTacToe sets up a tic-tac-toe position written row by row with . for
+// empty cells, e.g. "XX./OO./..."
+func loadTicTacToe(t *testing.T, rows string) *TicTacToe {
+	t.Helper()
+	game := NewTicTacToe()
+	pos := 0
+	for _, c := range rows {
+		switch c {
+		case '/':
+			continue
+		case 'X', 'O':
+			game.Board[pos/3][pos%3] = string(c)
+		case '.':
+		default:
+			t.Fatalf("loadTicTacToe(%q): unexpected %q", rows, c)
+		}
+		pos++
+	}
+	if pos != 9 {
+		t.Fatalf("loadTicTacToe(%q): %d cells, want 9", rows, pos)
+	}
+	return game
+}
+
+func TestMinimaxNeverLoses(t *testing.T) {
+	for _, minimax := range []string{PlayerX, PlayerO} {
+		for _, first := range []string{PlayerX, PlayerO} {
+			games := 0
+			var play func(game Game, toMove string)
+			play = func(game Game, toMove string) {
+				if winner := game.Winner(); winner != "" {
+					games++
+					if winner == OtherPlayer(minimax) {
+						t.Fatalf("minimax as %s lost with %s moving first: %v", minimax, first, game.(*TicTacToe).Board)
+					}
+					return
+				}
+				if toMove == minimax {
+					next := game.Clone()
+					position, _ := BestMove(game, toMove)
+					next.Play(toMove, position)
+					play(next, OtherPlayer(toMove))
+					return
+				}
+				for _, position := range game.Legal() {
+					next := game.Clone()
+					next.Play(toMove, position)
+					play(next, OtherPlayer(toMove))
+				}
+			}
+			play(NewTicTacToe(), first)
+			if games == 0 {
+				t.Fatalf("minimax as %s with %s moving first played no games", minimax, first)
+			}
+		}
+	}
+}
+
+func TestMinimaxTactics(t *testing.T) {
+	tests := []struct {
+		name   string
+		board  string
+		player string
+		want   int
+	}{
+		{"takes a row", "XX./OO./...", PlayerX, 2},
+		{"takes a column", "X.O/XO./...", PlayerX, 6},
+		{"takes a diagonal over a block", "X.O/.XO/...", PlayerX, 8},
+		{"blocks a row", "XX./O../...", PlayerO, 2},
+		{"blocks a column", "XO./X../..O", PlayerO, 6},
+		{"blocks a diagonal", "..X/.X./..O", PlayerO, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := loadTicTacToe(t, tt.board)
+			if position, _ := BestMove(game, tt.player); position != tt.want {
+				t.Errorf("BestMove = %d, want %d", position, tt.want)
+			}
+		})
+	}
+}
+
+// depthProbe is tic-tac-toe searched to a fixed depth, recording how many
+// moves had been played in each position minimax evaluated
+type depthProbe struct {
+	*TicTacToe
+	depth     int
+	evaluated map[int]int
+}
+
+func (p *depthProbe) Clone() Game {
+	return &depthProbe{TicTacToe: p.TicTacToe.Clone().(*TicTacToe), depth: p.depth, evaluated: p.evaluated}
+}
+
+func (p *depthProbe) SearchDepth() int { return p.depth }
+
+func (p *depthProbe) Evaluate(player string) int {
+	p.evaluated[len(TakenPositions(p.Board))]++
+	return 0
+}
+
+func TestMinimaxSearchDepth(t *testing.T) {
+	tests := []struct {
+		name  string
+		depth int // passed to SearchMove, 0 for the game's SearchDepth
+		want  int // moves played in every evaluated position
+	}{
+		{"game's default", 0, 2},
+		{"explicit depth", 3, 3},
+		{"one move", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := &depthProbe{TicTacToe: NewTicTacToe(), depth: 2, evaluated: map[int]int{}}
+			SearchMove(probe, PlayerX, tt.depth)
+			if len(probe.evaluated) != 1 || probe.evaluated[tt.want] == 0 {
+				t.Errorf("evaluated positions by moves played = %v, want all at %d", probe.evaluated, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterVariant("tictactoe", "misere", func() Game {
+		return &TicTacToe{Board: InitBoard(), Misere: true}
+	})
+}
+
+// BuildMisereContext describes the game state for misère tic-tac-toe, where
+// completing three in a row loses. The threat analysis is inverted: the lines
+// a player could complete are moves to avoid, and the opponent's are left
+// open for the opponent to be forced into.
+func BuildMisereContext(board Board, player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	prompt.WriteString(fmt.Sprintf("You are playing Misère Tic-Tac-Toe as player %s.\n", player))
+	prompt.WriteString("The rules are REVERSED: whoever completes three in a row (row, column, or diagonal) LOSES. Avoid getting three in a row and try to force your opponent into it.\n\n")
+	prompt.WriteString(DescribeBoard(board, moveHistory))
+
+	if promptHints != HintsNone {
+		// Completing a line is now what each player must avoid
+		losingMoves, opponentLosingMoves := DetectThreats(board, player)
+		losingMoves, opponentLosingMoves = distinctPositions(losingMoves), distinctPositions(opponentLosingMoves)
+		var safeMoves []int
+		for _, pos := range AvailablePositions(board) {
+			if !containsPosition(losingMoves, pos) {
+				safeMoves = append(safeMoves, pos)
+			}
+		}
+
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		if len(losingMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("☠️  DANGER! Playing %v would give you three in a row and LOSE the game! NEVER play there!\n", losingMoves))
+			if len(safeMoves) > 0 {
+				prompt.WriteString(fmt.Sprintf("SAFE POSITIONS: %v\n", safeMoves))
+			} else {
+				prompt.WriteString("Every available position completes a line for you, so this is your last move.\n")
+			}
+		}
+		if len(opponentLosingMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("💡 %s would lose by playing %v. Leave those positions open so %s may be forced to take them.\n", opponent, opponentLosingMoves, opponent))
+		}
+		if len(losingMoves) == 0 && len(opponentLosingMoves) == 0 {
+			prompt.WriteString("No immediate dangers detected. Play carefully.\n")
+			if promptHints == HintsFull {
+				prompt.WriteString("Safe strategy: as X, take the center (4), then answer each O move with the position opposite it through the center\n")
+			}
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. AVOID: Never complete three of your own marks in a row\n")
+		prompt.WriteString(fmt.Sprintf("2. LEAVE: Don't fill the positions that would complete %s's lines; let %s be forced into them\n", opponent, opponent))
+		prompt.WriteString("3. STRATEGIC: As X, take the center and mirror O through it; as O, avoid lining up your marks\n")
+	}
+
+	return prompt.String()
+}
+
+// distinctPositions drops repeats, since one position can complete two lines
+func distinctPositions(positions []int) []int {
+	var distinct []int
+	for _, pos := range positions {
+		if !containsPosition(distinct, pos) {
+			distinct = append(distinct, pos)
+		}
+	}
+	return distinct
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ModelLister is implemented by backends that can report which models they serve
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// OllamaTagsResponse is the model list returned by /api/tags
+type OllamaTagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// ListModels returns the models installed on the Ollama server
+func (o *OllamaBackend) ListModels(ctx context.Context) ([]string, error) {
+	var tags OllamaTagsResponse
+	if err := getJSON(ctx, o.URL+"/api/tags", bearerHeaders(o.APIKey, o.Headers), &tags); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, model := range tags.Models {
+		names = append(names, model.Name)
+	}
+	return names, nil
+}
+
+// errCannotListModels is returned by model listers wrapping backends that can't list models
+var errCannotListModels = errors.New("backend cannot list models")
+
+// CheckModel verifies that the backend serves model, suggesting close matches
+// when it doesn't. Backends that can't list their models are assumed to have it.
+func CheckModel(ctx context.Context, backend Backend, model string) error {
+	lister, ok := backend.(ModelLister)
+	if !ok {
+		return nil
+	}
+	available, err := lister.ListModels(ctx)
+	if errors.Is(err, errCannotListModels) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not list models: %w", err)
+	}
+	if hasModel(available, model) {
+		return nil
+	}
+	return &MissingModelError{Model: model, Available: available}
+}
+
+// ProbeModel checks that llm's backend answers and has its model: from the
+// backend's list of models where it has one, and otherwise, or if the
+// server won't list them, with a one-token request
+func ProbeModel(ctx context.Context, llm *LLMAgent) error {
+	if lister, ok := llm.Backend.(ModelLister); ok {
+		available, err := lister.ListModels(ctx)
+		var httpErr *HTTPError
+		switch {
+		case err == nil && hasModel(available, llm.Model):
+			return nil
+		case err == nil:
+			return &MissingModelError{Model: llm.Model, Available: available}
+		case !errors.Is(err, errCannotListModels) && !errors.As(err, &httpErr):
+			return fmt.Errorf("could not list models: %w", err)
+		}
+	}
+	_, err := llm.Warmup()
+	return err
+}
+
+// MissingModelError reports a model the backend doesn't have
+type MissingModelError struct {
+	Model     string
+	Available []string
+}
+
+func (e *MissingModelError) Error() string {
+	msg := fmt.Sprintf("model %q is not installed", e.Model)
+	if suggestions := closestModels(e.Model, e.Available, 3); len(suggestions) > 0 {
+		msg += fmt.Sprintf("; did you mean %s?", strings.Join(suggestions, ", "))
+	} else if len(e.Available) == 0 {
+		msg += "; no models are installed"
+	} else {
+		msg += "; installed models: " + strings.Join(e.Available, ", ")
+	}
+	return msg
+}
+
+// ModelPuller is implemented by backends that can download missing models
+type ModelPuller interface {
+	// PullModel downloads model, reporting progress as it goes; total is 0
+	// for steps without a known size
+	PullModel(ctx context.Context, model string, progress func(status string, completed, total int64)) error
+}
+
+// OllamaPullProgress is one line of a streamed /api/pull response
+type OllamaPullProgress struct {
+	Status    string `json:"status"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PullModel downloads a model with Ollama's /api/pull
+func (o *OllamaBackend) PullModel(ctx context.Context, model string, progress func(status string, completed, total int64)) error {
+	body := map[string]any{"model": model, "stream": true}
+	success := false
+	_, err := streamText(ctx, o.URL+"/api/pull", bearerHeaders(o.APIKey, o.Headers), body, false, func(string) {}, func(data []byte) (string, error) {
+		var update OllamaPullProgress
+		if err := json.Unmarshal(data, &update); err != nil {
+			return "", err
+		}
+		if update.Error != "" {
+			return "", errors.New(update.Error)
+		}
+		success = update.Status == "success"
+		progress(update.Status, update.Completed, update.Total)
+		return "", nil
+	})
+	if err == nil && !success {
+		err = fmt.Errorf("pull of %s ended without success", model)
+	}
+	return err
+}
+
+// PullWithProgress pulls a missing model, showing progress on one console line
+func PullWithProgress(w io.Writer, puller ModelPuller, model string) error {
+	fmt.Fprintf(w, "Pulling %s...\n", model)
+	err := puller.PullModel(context.Background(), model, func(status string, completed, total int64) {
+		if total > 0 {
+			fmt.Fprintf(w, "\r\033[K  %s: %.0f%% (%.1f/%.1f MB)", status, float64(completed)/float64(total)*100,
+				float64(completed)/1e6, float64(total)/1e6)
+		} else {
+			fmt.Fprintf(w, "\r\033[K  %s", status)
+		}
+	})
+	fmt.Fprintln(w)
+	return err
+}
+
+// hasModel reports whether model is in the list; like Ollama, a name without a
+// tag refers to the :latest tag
+func hasModel(available []string, model string) bool {
+	for _, name := range available {
+		if name == model || name == model+":latest" {
+			return true
+		}
+	}
+	return false
+}
+
+// closestModels returns up to n installed models whose names are closest to model
+func closestModels(model string, available []string, n int) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, name := range available {
+		base, _, _ := strings.Cut(name, ":")
+		want, _, _ := strings.Cut(model, ":")
+		distance := levenshtein(strings.ToLower(want), strings.ToLower(base))
+		// Same family with a different tag, e.g. llama3.2:1b for llama3.2:3b
+		if strings.EqualFold(base, want) {
+			distance = 0
+		}
+		// Too different to be a typo
+		if distance > max(len(want)/2, 2) {
+			continue
+		}
+		candidates = append(candidates, candidate{name, distance})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var names []string
+	for i := 0; i < len(candidates) && i < n; i++ {
+		names = append(names, candidates[i].name)
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	RegisterVariant("tictactoe", "notakto", func() Game { return NewNotakto(1) })
+}
+
+// maxNotaktoBoards keeps each board to one digit of the move numbers
+const maxNotaktoBoards = 9
+
+// Notakto is neutral-mark tic-tac-toe on one or more boards: both players
+// place X, a board with three in a row is dead, and whoever kills the last
+// live board loses. On one board moves are positions 0-8; on several, a move
+// is the board number (1-9) followed by the position, so 24 is board 2,
+// position 4.
+type Notakto struct {
+	Boards []Board
+	last   string
+}
+
+// NewNotakto creates a game on the given number of empty boards
+func NewNotakto(boards int) *Notakto {
+	n := &Notakto{Boards: make([]Board, boards)}
+	for i := range n.Boards {
+		n.Boards[i] = InitBoard()
+	}
+	return n
+}
+
+// notaktoMove numbers a move on board b (0-based) at position
+func (n *Notakto) notaktoMove(b, position int) int {
+	if len(n.Boards) == 1 {
+		return position
+	}
+	return (b+1)*10 + position
+}
+
+// notaktoCell decodes a move number, reporting whether it is on one of the boards
+func (n *Notakto) notaktoCell(move int) (b, position int, ok bool) {
+	b, position = 0, move
+	if len(n.Boards) > 1 {
+		b, position = move/10-1, move%10
+		if move < 10 {
+			return b, position, false
+		}
+	}
+	return b, position, b >= 0 && b < len(n.Boards) && position >= 0 && position <= 8
+}
+
+// dead reports whether board b has three in a row
+func (n *Notakto) dead(b int) bool {
+	return CheckWinner(n.Boards[b]) != ""
+}
+
+// liveBoards returns the boards still in play, 0-based
+func (n *Notakto) liveBoards() []int {
+	var live []int
+	for b := range n.Boards {
+		if !n.dead(b) {
+			live = append(live, b)
+		}
+	}
+	return live
+}
+
+func (n *Notakto) Name() string {
+	if len(n.Boards) == 1 {
+		return "Notakto"
+	}
+	return fmt.Sprintf("Notakto (%d boards)", len(n.Boards))
+}
+
+func (n *Notakto) Clone() Game {
+	clone := &Notakto{Boards: make([]Board, len(n.Boards)), last: n.last}
+	copy(clone.Boards, n.Boards)
+	return clone
+}
+
+// Display prints the boards side by side, numbered from 1
+func (n *Notakto) Display(w io.Writer) {
+	if len(n.Boards) == 1 {
+		DisplayBoard(w, n.Boards[0])
+		return
+	}
+	fmt.Fprintln(w)
+	for b := range n.Boards {
+		label := fmt.Sprintf("Board %d", b+1)
+		if n.dead(b) {
+			label += " (dead)"
+		}
+		fmt.Fprintf(w, "  %-16s", label)
+	}
+	fmt.Fprintln(w)
+	for row := 0; row < 3; row++ {
+		for b := range n.Boards {
+			fmt.Fprint(w, "  ")
+			for col := 0; col < 3; col++ {
+				cell := n.Boards[b][row][col]
+				if cell == Empty {
+					cell = "."
+				}
+				fmt.Fprint(w, cell+" ")
+			}
+			fmt.Fprint(w, "          ")
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// CellGrid lays out the boards side by side, as Display prints them
+func (n *Notakto) CellGrid() [][]string {
+	grid := make([][]string, 3)
+	for row := range grid {
+		for b, board := range n.Boards {
+			if b > 0 {
+				grid[row] = append(grid[row], "")
+			}
+			grid[row] = append(grid[row], board[row][:]...)
+		}
+	}
+	return grid
+}
+
+// Legal returns the empty cells of the live boards
+func (n *Notakto) Legal() []int {
+	var moves []int
+	for _, b := range n.liveBoards() {
+		for _, position := range AvailablePositions(n.Boards[b]) {
+			moves = append(moves, n.notaktoMove(b, position))
+		}
+	}
+	return moves
+}
+
+// Play places an X for either player
+func (n *Notakto) Play(player string, position int) bool {
+	b, pos, ok := n.notaktoCell(position)
+	if !ok || n.dead(b) || !MakeMove(&n.Boards[b], PlayerX, pos/3, pos%3) {
+		return false
+	}
+	n.last = player
+	return true
+}
+
+// Winner is the player who didn't kill the last board. Every board dies
+// before it fills up, so there are no draws.
+func (n *Notakto) Winner() string {
+	if n.last == "" || len(n.liveBoards()) > 0 {
+		return ""
+	}
+	return OtherPlayer(n.last)
+}
+
+func (n *Notakto) Describe(position int) string {
+	b, pos, ok := n.notaktoCell(position)
+	if !ok || len(n.Boards) == 1 {
+		return fmt.Sprintf("position %d", position)
+	}
+	return fmt.Sprintf("%d (board %d, position %d)", position, b+1, pos)
+}
+
+func (n *Notakto) Rules(player string) string {
+	if len(n.Boards) == 1 {
+		return fmt.Sprintf("You are playing Notakto as player %s: both players place X, and whoever completes three in a row LOSES. "+
+			"Positions are numbered 0-8, left to right and top to bottom. Each turn, respond with a single position number.", player)
+	}
+	return fmt.Sprintf("You are playing Notakto on %d boards as player %s: both players place X on any live board, a board with three in a row is dead, "+
+		"and whoever kills the LAST live board loses. Moves are the board number followed by the position 0-8, so 24 is board 2, position 4. "+
+		"Each turn, respond with a single two-digit move.", len(n.Boards), player)
+}
+
+func (n *Notakto) Context(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	if len(n.Boards) == 1 {
+		prompt.WriteString(fmt.Sprintf("You are playing Notakto as player %s.\n", player))
+		prompt.WriteString("BOTH players place X; there are no O's. Whoever completes three X's in a row (row, column, or diagonal) LOSES.\n\n")
+		prompt.WriteString(DescribeBoard(n.Boards[0], moveHistory))
+	} else {
+		prompt.WriteString(fmt.Sprintf("You are playing Notakto on %d boards as player %s.\n", len(n.Boards), player))
+		prompt.WriteString("BOTH players place X on any live board; there are no O's. A board with three X's in a row is dead and can't be played on. " +
+			"Whoever kills the LAST live board LOSES.\n")
+		prompt.WriteString("Moves are the board number followed by the position 0-8 (left to right, top to bottom): 24 is board 2, position 4.\n\n")
+
+		if len(moveHistory) > 0 {
+			prompt.WriteString("Move history:\n")
+			for i, move := range moveHistory {
+				prompt.WriteString(fmt.Sprintf("%d. Player %s played %s\n", i+1, move.Player, n.Describe(move.Position)))
+			}
+			prompt.WriteString("\n")
+		}
+
+		prompt.WriteString("Current boards (empty spaces show their move number):\n")
+		for b, board := range n.Boards {
+			if n.dead(b) {
+				prompt.WriteString(fmt.Sprintf("Board %d: DEAD\n", b+1))
+				continue
+			}
+			prompt.WriteString(fmt.Sprintf("Board %d:\n", b+1))
+			for row := 0; row < 3; row++ {
+				for col := 0; col < 3; col++ {
+					if board[row][col] == Empty {
+						prompt.WriteString(fmt.Sprintf(" %d", n.notaktoMove(b, row*3+col)))
+					} else {
+						prompt.WriteString("  X")
+					}
+				}
+				prompt.WriteString("\n")
+			}
+		}
+
+		prompt.WriteString("\n✅ AVAILABLE POSITIONS (CHOOSE ONE OF THESE): ")
+		prompt.WriteString(joinPositions(n.Legal()))
+		prompt.WriteString("\n")
+	}
+
+	if promptHints != HintsNone {
+		// Moves that complete a line kill their board, which loses on the last one
+		var killingMoves, safeMoves []int
+		for _, move := range n.Legal() {
+			b, _, _ := n.notaktoCell(move)
+			next := n.Clone().(*Notakto)
+			next.Play(player, move)
+			if next.dead(b) {
+				killingMoves = append(killingMoves, move)
+			} else {
+				safeMoves = append(safeMoves, move)
+			}
+		}
+		lastBoard := len(n.liveBoards()) == 1
+
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		switch {
+		case len(killingMoves) > 0 && lastBoard:
+			prompt.WriteString(fmt.Sprintf("☠️  DANGER! Playing %s would complete three in a row on the last live board and LOSE the game! NEVER play there!\n", joinPositions(killingMoves)))
+			if len(safeMoves) > 0 {
+				prompt.WriteString(fmt.Sprintf("SAFE POSITIONS: %s\n", joinPositions(safeMoves)))
+			} else {
+				prompt.WriteString("Every available position completes a line, so this is your last move.\n")
+			}
+		case len(killingMoves) > 0:
+			prompt.WriteString(fmt.Sprintf("💡 Playing %s would complete three in a row and kill that board. The game goes on with the other boards, "+
+				"so this only matters for who is forced to kill the last one.\n", joinPositions(killingMoves)))
+		default:
+			prompt.WriteString("No move completes a line yet. Play carefully.\n")
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. AVOID: Never complete three in a row on the last live board\n")
+		prompt.WriteString(fmt.Sprintf("2. FORCE: Leave %s a last board where every empty cell completes a line\n", opponent))
+		if len(n.Boards) == 1 {
+			prompt.WriteString("3. STRATEGIC: The first player wins by taking the center (4) and then playing a knight's move away from the second X\n")
+		} else {
+			prompt.WriteString("3. STRATEGIC: Killing a board on purpose changes who has to make the last move, so count how many safe moves each board has left\n")
+		}
+	}
+
+	return prompt.String()
+}
+
+func (n *Notakto) Prompt(player string, moveHistory []Move) string {
+	if len(n.Boards) == 1 {
+		return n.Context(player, moveHistory) + AnswerInstructions(n.Boards[0])
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(n.Context(player, moveHistory))
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	prompt.WriteString("1. You MUST choose ONLY from the AVAILABLE POSITIONS list above\n")
+	prompt.WriteString("2. ONLY respond with ONE two-digit move: the board number, then the position, e.g. 24\n")
+	prompt.WriteString("3. Do NOT include any other text, explanation, or formatting\n")
+	return prompt.String()
+}
+
+var (
+	notaktoNamedMove = regexp.MustCompile(`(?i)board\D*([1-9])\D*?(?:position|pos|cell|square)\D*([0-8])`)
+	notaktoMove      = regexp.MustCompile(`\b([1-9])[\s,;:/\-]{0,3}([0-8])\b`)
+)
+
+// ParseMove reads a position on one board, or a board and position such as
+// 24, "2-4", or "board 2, position 4" on several
+func (n *Notakto) ParseMove(response string) (int, error) {
+	if len(n.Boards) == 1 {
+		return ParseMove(response)
+	}
+	match := notaktoNamedMove.FindStringSubmatch(response)
+	if match == nil {
+		match = notaktoMove.FindStringSubmatch(response)
+	}
+	if match == nil {
+		return -1, fmt.Errorf("no valid board and position found in response: %s", strings.TrimSpace(response))
+	}
+	return int(match[1][0]-'0')*10 + int(match[2][0]-'0'), nil
+}
+
+func (n *Notakto) HumanHint() string {
+	if len(n.Boards) == 1 {
+		return `position 0-8 or "row col"`
+	}
+	return "board and position, e.g. 2 4"
+}
+
+func (n *Notakto) ParseHumanMove(input string) (int, error) {
+	if len(n.Boards) == 1 {
+		row, col, err := ParseHumanMove(input)
+		if err != nil {
+			return -1, err
+		}
+		return row*3 + col, nil
+	}
+	return n.ParseMove(input)
+}
+
+// SearchDepth searches a single board to the end; several boards have too
+// many move orders for that
+func (n *Notakto) SearchDepth() int {
+	if len(n.Boards) == 1 {
+		return math.MaxInt
+	}
+	return 6
+}
+
+// Evaluate scores unfinished positions as even: which boards are worth
+// killing depends on the whole set, not on any one board's shape
+func (n *Notakto) Evaluate(player string) int {
+	return 0
+}
+
+// LoadPosition reads nine cells per board, board after board, each left to
+// right and top to bottom. Both players' marks are X's, so the position never
+// says whose turn it is.
+func (n *Notakto) LoadPosition(board string) (string, error) {
+	cells, err := parseCells(board, 9*len(n.Boards))
+	if err != nil {
+		return "", err
+	}
+	for i, cell := range cells {
+		if cell == PlayerO {
+			return "", fmt.Errorf("notakto boards only hold X's")
+		}
+		n.Boards[i/9][i%9/3][i%3] = cell
+	}
+	if len(n.liveBoards()) == 0 {
+		return "", fmt.Errorf("every board in the start position already has three in a row")
+	}
+	return "", nil
+}
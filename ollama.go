@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterProvider(&BasicProvider{
+		ID:   "ollama",
+		Info: "Ollama /api/generate (and /api/chat with -chat)",
+		URL:  ollamaHost(os.Getenv("OLLAMA_HOST")),
+		Factory: func(cfg BackendConfig) (Backend, error) {
+			return &OllamaBackend{URL: cfg.URL, APIKey: orDefault(cfg.APIKey, os.Getenv("OLLAMA_API_KEY")), Headers: cfg.Headers, KeepAlive: cfg.KeepAlive}, nil
+		},
+	})
+}
+
+// ollamaHost is the URL of the server OLLAMA_HOST names, as the ollama
+// command reads it: "host", "host:port", or a URL, on port 11434 unless it
+// says otherwise. Without it, the server is on localhost.
+func ollamaHost(host string) string {
+	if host == "" {
+		return "http://localhost:11434"
+	}
+	scheme, rest, ok := strings.Cut(host, "://")
+	if !ok {
+		scheme, rest = "http", host
+	}
+	hostPort, path, _ := strings.Cut(rest, "/")
+	if _, _, err := net.SplitHostPort(hostPort); err != nil {
+		port := "11434"
+		if scheme == "https" {
+			port = "443"
+		}
+		hostPort = net.JoinHostPort(strings.Trim(hostPort, "[]"), port)
+	}
+	return strings.TrimSuffix(scheme+"://"+hostPort+"/"+path, "/")
+}
+
+type OllamaRequest struct {
+	Model     string         `json:"model"`
+	System    string         `json:"system,omitempty"`
+	Prompt    string         `json:"prompt"`
+	Format    map[string]any `json:"format,omitempty"`
+	Stream    bool           `json:"stream"`
+	KeepAlive any            `json:"keep_alive,omitempty"`
+	Options   OllamaOptions  `json:"options"`
+}
+
+// OllamaOptions holds the model parameters Ollama reads from a request
+type OllamaOptions struct {
+	Temperature float64 `json:"temperature"`
+	TopP        float64 `json:"top_p,omitempty"`
+	TopK        int     `json:"top_k,omitempty"`
+	Seed        int     `json:"seed,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+type OllamaResponse struct {
+	Response string `json:"response"`
+	OllamaUsage
+	Error string `json:"error,omitempty"`
+}
+
+// OllamaUsage holds the token counts Ollama reports with the final response
+type OllamaUsage struct {
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+func (u OllamaUsage) usage() Usage {
+	return Usage{PromptTokens: u.PromptEvalCount, CompletionTokens: u.EvalCount}
+}
+
+type OllamaChatRequest struct {
+	Model     string         `json:"model"`
+	Messages  []ChatMessage  `json:"messages"`
+	Tools     []openAITool   `json:"tools,omitempty"`
+	Format    map[string]any `json:"format,omitempty"`
+	Stream    bool           `json:"stream"`
+	KeepAlive any            `json:"keep_alive,omitempty"`
+	Options   OllamaOptions  `json:"options"`
+}
+
+type OllamaChatResponse struct {
+	Message ChatMessage `json:"message"`
+	OllamaUsage
+	Error string `json:"error,omitempty"`
+}
+
+// OllamaBackend talks to Ollama's /api/generate endpoint, or /api/chat for
+// requests that carry conversation history or tools
+type OllamaBackend struct {
+	URL       string
+	APIKey    string // sent as a bearer token, for authenticated gateways in front of Ollama
+	Headers   map[string]string
+	KeepAlive string // empty uses the server's default
+}
+
+// Generate sends a prompt to Ollama
+func (o *OllamaBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	if len(req.Messages) > 0 || len(req.Tools) > 0 {
+		return o.chat(ctx, req)
+	}
+
+	reqBody := OllamaRequest{
+		Model:     req.Model,
+		System:    req.System,
+		Prompt:    req.Prompt,
+		Format:    req.Format,
+		Stream:    req.OnToken != nil,
+		KeepAlive: o.keepAlive(),
+		Options:   newOllamaOptions(req),
+	}
+
+	url := o.URL + "/api/generate"
+	headers := bearerHeaders(o.APIKey, o.Headers)
+	if reqBody.Stream {
+		// Ollama streams one JSON object per line, with token counts on the last
+		var usage Usage
+		text, err := streamText(ctx, url, headers, reqBody, false, req.OnToken, func(data []byte) (string, error) {
+			var chunk OllamaResponse
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return "", err
+			}
+			if chunk.Error != "" {
+				return "", errors.New(chunk.Error)
+			}
+			usage.Add(chunk.usage())
+			return chunk.Response, nil
+		})
+		return LLMResponse{Text: text, Usage: usage}, err
+	}
+
+	var ollamaResp OllamaResponse
+	if err := postJSON(ctx, url, headers, reqBody, &ollamaResp); err != nil {
+		return LLMResponse{}, err
+	}
+	return LLMResponse{Text: ollamaResp.Response, Usage: ollamaResp.usage()}, nil
+}
+
+// keepAlive converts the keep-alive setting for the request. Ollama reads a
+// bare number as seconds (with negative meaning forever) and anything else as
+// a duration string.
+func (o *OllamaBackend) keepAlive() any {
+	if o.KeepAlive == "" {
+		return nil
+	}
+	if seconds, err := strconv.ParseFloat(o.KeepAlive, 64); err == nil {
+		return seconds
+	}
+	return o.KeepAlive
+}
+
+// newOllamaOptions copies the sampling settings and token limit from a request
+func newOllamaOptions(req LLMRequest) OllamaOptions {
+	return OllamaOptions{Temperature: req.Temperature, TopP: req.TopP, TopK: req.TopK, Seed: req.Seed, NumPredict: req.MaxTokens}
+}
+
+// chat sends the conversation to /api/chat
+func (o *OllamaBackend) chat(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	reqBody := OllamaChatRequest{
+		Model:     req.Model,
+		Messages:  chatMessages(req),
+		Tools:     openAITools(req.Tools),
+		Format:    req.Format,
+		Stream:    req.OnToken != nil,
+		KeepAlive: o.keepAlive(),
+		Options:   newOllamaOptions(req),
+	}
+
+	url := o.URL + "/api/chat"
+	headers := bearerHeaders(o.APIKey, o.Headers)
+	if reqBody.Stream {
+		var usage Usage
+		text, err := streamText(ctx, url, headers, reqBody, false, req.OnToken, func(data []byte) (string, error) {
+			var chunk OllamaChatResponse
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return "", err
+			}
+			if chunk.Error != "" {
+				return "", errors.New(chunk.Error)
+			}
+			usage.Add(chunk.usage())
+			return chunk.Message.Content, nil
+		})
+		return LLMResponse{Text: text, Usage: usage}, err
+	}
+
+	var chatResp OllamaChatResponse
+	if err := postJSON(ctx, url, headers, reqBody, &chatResp); err != nil {
+		return LLMResponse{}, err
+	}
+	return LLMResponse{Text: chatResp.Message.Content, ToolCalls: chatResp.Message.ToolCalls, Usage: chatResp.usage()}, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const openAIDefaultURL = "https://api.openai.com"
+
+func init() {
+	RegisterProvider(&BasicProvider{
+		ID:   "openai",
+		Info: "OpenAI-compatible /v1/chat/completions (OpenAI, LM Studio, vLLM, LocalAI)",
+		URL:  openAIDefaultURL,
+		Factory: func(cfg BackendConfig) (Backend, error) {
+			return &OpenAIBackend{URL: cfg.URL, APIKey: orDefault(cfg.APIKey, os.Getenv("OPENAI_API_KEY")), Headers: cfg.Headers}, nil
+		},
+	})
+}
+
+// ChatMessage is one message in a chat-style conversation
+type ChatMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+type OpenAIRequest struct {
+	Model          string                `json:"model,omitempty"`
+	Messages       []ChatMessage         `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	TopP           float64               `json:"top_p,omitempty"`
+	Seed           int                   `json:"seed,omitempty"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	Tools          []openAITool          `json:"tools,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	LogitBias      map[string]float64    `json:"logit_bias,omitempty"` // keyed by token ID
+	Stream         bool                  `json:"stream"`
+	StreamOptions  *openAIStreamOptions  `json:"stream_options,omitempty"`
+}
+
+// openAIStreamOptions asks for token usage in the final streamed chunk
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type OpenAIResponse struct {
+	Choices []struct {
+		Message ChatMessage `json:"message"`
+		Delta   ChatMessage `json:"delta"` // streamed chunks carry text here
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// usage returns the reported token counts, if any
+func (r OpenAIResponse) usage() Usage {
+	if r.Usage == nil {
+		return Usage{}
+	}
+	return Usage{PromptTokens: r.Usage.PromptTokens, CompletionTokens: r.Usage.CompletionTokens}
+}
+
+// OpenAIBackend talks to /v1/chat/completions style APIs (OpenAI, LM Studio, vLLM, LocalAI)
+type OpenAIBackend struct {
+	URL     string
+	APIKey  string
+	Headers map[string]string
+}
+
+// Generate sends the prompt as a single user message
+func (o *OpenAIBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	body := newOpenAIRequest(req)
+	body.StreamOptions = streamUsage(body.Stream)
+	return postChatCompletion(ctx, openAIEndpoint(o.URL, "/chat/completions"), bearerHeaders(o.APIKey, o.Headers), body, req.OnToken)
+}
+
+// streamUsage asks for token counts at the end of a stream. Not every
+// OpenAI-compatible API accepts stream_options, so only backends known to
+// support it send it.
+func streamUsage(stream bool) *openAIStreamOptions {
+	if !stream {
+		return nil
+	}
+	return &openAIStreamOptions{IncludeUsage: true}
+}
+
+// chatCompletion posts an OpenAI-style chat completion request and returns the first choice
+func chatCompletion(ctx context.Context, url string, headers map[string]string, req LLMRequest) (LLMResponse, error) {
+	return postChatCompletion(ctx, url, headers, newOpenAIRequest(req), req.OnToken)
+}
+
+// newOpenAIRequest converts a backend-independent request to the chat completions shape
+func newOpenAIRequest(req LLMRequest) OpenAIRequest {
+	return OpenAIRequest{
+		Model:          req.Model,
+		Messages:       chatMessages(req),
+		Temperature:    req.Temperature,
+		TopP:           req.TopP,
+		Seed:           req.Seed,
+		MaxTokens:      req.MaxTokens,
+		Tools:          openAITools(req.Tools),
+		ResponseFormat: newOpenAIResponseFormat(req.Format),
+		LogitBias:      openAILogitBias(req.LogitBias),
+		Stream:         req.OnToken != nil,
+	}
+}
+
+// postChatCompletion posts a chat completions request body and returns the
+// first choice, streaming it to onToken when set
+func postChatCompletion(ctx context.Context, url string, headers map[string]string, body any, onToken func(string)) (LLMResponse, error) {
+	if onToken != nil {
+		var usage Usage
+		text, err := streamText(ctx, url, headers, body, true, onToken, func(data []byte) (string, error) {
+			var chunk OpenAIResponse
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return "", err
+			}
+			usage.Add(chunk.usage())
+			if len(chunk.Choices) == 0 {
+				return "", nil
+			}
+			return chunk.Choices[0].Delta.Content, nil
+		})
+		return LLMResponse{Text: text, Usage: usage}, err
+	}
+
+	var openaiResp OpenAIResponse
+	if err := postJSON(ctx, url, headers, body, &openaiResp); err != nil {
+		return LLMResponse{}, err
+	}
+	if len(openaiResp.Choices) == 0 {
+		return LLMResponse{}, fmt.Errorf("response contained no choices")
+	}
+	message := openaiResp.Choices[0].Message
+	return LLMResponse{Text: message.Content, ToolCalls: message.ToolCalls, Usage: openaiResp.usage()}, nil
+}
+
+// openAIEndpoint joins a base URL and an API path, accepting base URLs with or without /v1
+func openAIEndpoint(baseURL, path string) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(baseURL, "/v1") {
+		baseURL += "/v1"
+	}
+	return baseURL + path
+}
+
+// chatMessages builds the full conversation for a request: the optional
+// system prompt, any earlier turns, and the prompt as the final user message
+func chatMessages(req LLMRequest) []ChatMessage {
+	var messages []ChatMessage
+	if req.System != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: req.System})
+	}
+	messages = append(messages, req.Messages...)
+	return appendChatMessage(messages, ChatMessage{Role: "user", Content: req.Prompt})
+}
+
+// appendChatMessage adds a message, merging it into the previous one when both
+// have the same role so that user and assistant turns strictly alternate
+func appendChatMessage(messages []ChatMessage, msg ChatMessage) []ChatMessage {
+	if n := len(messages); n > 0 && messages[n-1].Role == msg.Role {
+		messages[n-1].Content += "\n\n" + msg.Content
+		return messages
+	}
+	return append(messages, msg)
+}
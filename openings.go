@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Grid is implemented by games played on a rectangular board, so the
+// positions chosen on it can be drawn as a heatmap
+type Grid interface {
+	// Dimensions returns the board's rows and columns, with positions
+	// numbered left to right and top to bottom from 0
+	Dimensions() (rows, cols int)
+}
+
+// openingTotal returns how many games the model opened from the empty board
+func openingTotal(m *ModelStats) int {
+	total := 0
+	for _, count := range m.Openings {
+		total += count
+	}
+	return total
+}
+
+// openingModels returns the names of the models that opened a game from the empty board, sorted
+func openingModels(stats *GameStats) []string {
+	var names []string
+	for _, name := range sortedKeys(stats.Models) {
+		if len(stats.Models[name].Openings) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// PrintOpenings prints, for each model, a heatmap of the positions it chose
+// when opening a game from the empty board, if game has a grid to draw it on
+func PrintOpenings(w io.Writer, stats *GameStats, game Game) {
+	grid, ok := game.(Grid)
+	names := openingModels(stats)
+	if !ok || len(names) == 0 {
+		return
+	}
+	rows, cols := grid.Dimensions()
+	fmt.Fprintln(w, "Opening moves (share of games each model opened on the empty board):")
+	for _, name := range names {
+		m := stats.Models[name]
+		total := openingTotal(m)
+		fmt.Fprintf(w, "  %s (%d games):\n", name, total)
+		for r := 0; r < rows; r++ {
+			fmt.Fprint(w, "   ")
+			for c := 0; c < cols; c++ {
+				fmt.Fprintf(w, " %5.1f%%", rate(m.Openings[r*cols+c], total)*100)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
+
+// heatSquare shades a share from 0 to 1 for a Markdown heatmap
+func heatSquare(share float64) string {
+	switch {
+	case share >= 0.5:
+		return "🟥"
+	case share >= 0.25:
+		return "🟧"
+	case share >= 0.1:
+		return "🟨"
+	case share > 0:
+		return "🟦"
+	}
+	return "⬜"
+}
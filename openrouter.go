@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	openRouterDefaultURL = "https://openrouter.ai/api/v1"
+	openRouterReferer    = "https://github.com/brianhealey/llm-tac-toe"
+	openRouterTitle      = "LLM Tic-Tac-Toe"
+)
+
+func init() {
+	RegisterProvider(&BasicProvider{
+		ID:   "openrouter",
+		Info: "OpenRouter multi-vendor routing (key from OPENROUTER_API_KEY)",
+		URL:  openRouterDefaultURL,
+		Factory: func(cfg BackendConfig) (Backend, error) {
+			return newOpenRouterBackend(cfg), nil
+		},
+	})
+}
+
+type OpenRouterRequest struct {
+	OpenAIRequest
+	TopK   int      `json:"top_k,omitempty"`
+	Models []string `json:"models,omitempty"`
+}
+
+// OpenRouterBackend talks to OpenRouter, which routes vendor-prefixed model IDs
+// (e.g. anthropic/claude-3.5-sonnet, openai/gpt-4o) to the matching provider
+type OpenRouterBackend struct {
+	URL     string
+	APIKey  string
+	Headers map[string]string
+}
+
+// Generate sends the prompt to the model named in the request. A comma-separated
+// model list uses OpenRouter's fallback routing: the first model is tried first
+// and the rest are used if it is unavailable.
+func (o *OpenRouterBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	if o.APIKey == "" {
+		return LLMResponse{}, fmt.Errorf("openrouter backend requires an API key (set OPENROUTER_API_KEY)")
+	}
+
+	body := OpenRouterRequest{OpenAIRequest: newOpenAIRequest(req), TopK: req.TopK}
+	body.StreamOptions = streamUsage(body.Stream)
+	if models := strings.Split(req.Model, ","); len(models) > 1 {
+		for i := range models {
+			models[i] = strings.TrimSpace(models[i])
+		}
+		body.Model = models[0]
+		body.Models = models
+	}
+	headers := bearerHeaders(o.APIKey, mergeHeaders(map[string]string{
+		"HTTP-Referer": openRouterReferer,
+		"X-Title":      openRouterTitle,
+	}, o.Headers))
+	return postChatCompletion(ctx, openAIEndpoint(o.URL, "/chat/completions"), headers, body, req.OnToken)
+}
+
+// newOpenRouterBackend falls back to the API key from the environment
+func newOpenRouterBackend(cfg BackendConfig) *OpenRouterBackend {
+	return &OpenRouterBackend{URL: cfg.URL, APIKey: orDefault(cfg.APIKey, os.Getenv("OPENROUTER_API_KEY")), Headers: cfg.Headers}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Overlay keeps text files in Dir up to date with the game in progress, for
+// OBS text sources ("Read from file") on live streams:
+//
+//	board.txt     the board as printed on the console
+//	status.txt    e.g. "Game 3: llama3 (X) is thinking…", or the result
+//	players.txt   e.g. "llama3 (X) vs mistral (O)"
+//	score.txt     e.g. "X 3  O 1  Draws 2"
+//	thoughts.txt  the latest answer from an LLM
+//	state.json    all of it, as served at /state
+//
+// Each file is replaced in one step, so a source never reads half of one.
+type Overlay struct {
+	Dir  string
+	live *Live
+
+	mu     sync.Mutex
+	failed bool // whether the last write failed, so failures are warned about once
+}
+
+// NewOverlay writes live's state to dir, creating it if needed, and rewrites
+// it on every change in the background
+func NewOverlay(dir string, live *Live) (*Overlay, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	o := &Overlay{Dir: dir, live: live}
+	state, changed := live.State()
+	if err := o.write(state); err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			<-changed
+			state, changed = live.State()
+			o.Write(state)
+		}
+	}()
+	return o, nil
+}
+
+// Write writes a snapshot, warning if it can't
+func (o *Overlay) Write(state LiveState) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	err := o.write(state)
+	if err != nil && !o.failed {
+		slog.Warn("couldn't update the overlay", "dir", o.Dir, "err", err)
+	}
+	o.failed = err != nil
+}
+
+// Flush writes the latest snapshot, so the files show the end of the run
+// however far behind the background writes are
+func (o *Overlay) Flush() {
+	state, _ := o.live.State()
+	o.Write(state)
+}
+
+func (o *Overlay) write(state LiveState) error {
+	files := map[string]string{
+		"board.txt":    state.Board,
+		"status.txt":   state.Status(),
+		"players.txt":  "",
+		"score.txt":    state.ScoreLine(),
+		"thoughts.txt": state.Response,
+	}
+	if state.Game > 0 {
+		files["players.txt"] = fmt.Sprintf("%s (X) vs %s (O)", state.Players[PlayerX], state.Players[PlayerO])
+	}
+	for name, text := range files {
+		if err := writeFileAtomic(filepath.Join(o.Dir, name), []byte(strings.Trim(text, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	return writeJSONFile(filepath.Join(o.Dir, "state.json"), state)
+}
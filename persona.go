@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Persona is a built-in style of play for -persona-x and -persona-o, put
+// at the start of the player's system prompt
+type Persona struct {
+	Name   string
+	Prompt string
+}
+
+// personas are the built-in personas, in the order they're listed
+var personas = []Persona{
+	{
+		Name: "aggressive",
+		Prompt: "You are an aggressive player. You always go on the attack: you build lines of your own and make threats " +
+			"your opponent has to answer, rather than waiting for them to make a mistake.",
+	},
+	{
+		Name: "defensive",
+		Prompt: "You are a cautious, defensive player. Your first concern is never to lose: you watch every line your " +
+			"opponent could complete and block it early, and only attack when it's safe.",
+	},
+	{
+		Name: "random-loving",
+		Prompt: "You are a playful player who loves surprises. You enjoy unusual, unexpected moves and would rather try " +
+			"something different than play the obvious move.",
+	},
+	{
+		Name: "beginner",
+		Prompt: "You are a beginner who has only just learned the rules. You play quickly and go with your first instinct, " +
+			"without thinking far ahead.",
+	},
+}
+
+// PersonaNames returns the built-in personas' names
+func PersonaNames() []string {
+	names := make([]string, len(personas))
+	for i, persona := range personas {
+		names[i] = persona.Name
+	}
+	return names
+}
+
+// LookupPersona finds a built-in persona by name
+func LookupPersona(name string) (Persona, error) {
+	for _, persona := range personas {
+		if strings.EqualFold(persona.Name, name) {
+			return persona, nil
+		}
+	}
+	return Persona{}, fmt.Errorf("unknown persona %q: must be one of %s", name, strings.Join(PersonaNames(), ", "))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// finishedGame is a game played by a worker, held until the games before it
+// have been reported
+type finishedGame struct {
+	number int
+	result GameResult
+	stats  *GameStats
+	output bytes.Buffer
+}
+
+// PlayConcurrently plays games numbered from 1 on a pool of workers, up to
+// games of them (0 for unlimited). Each game prints to its own buffer and
+// keeps its own statistics; once every earlier game has been reported, its
+// output is written whole to out, its statistics merged into stats, and done called
+// with its result, so the console and ratings read as if the games had been
+// played one after another. No new games start once done returns true, but
+// the games already under way are finished and reported.
+func PlayConcurrently(workers, games int, out io.Writer, play func(out io.Writer, gameNumber int, stats *GameStats) GameResult, stats *GameStats, done func(GameResult) (stop bool)) {
+	jobs := make(chan int)
+	stop := make(chan struct{})
+	go func() {
+		defer close(jobs)
+		for n := 1; games == 0 || n <= games; n++ {
+			select {
+			case jobs <- n:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	finished := make(chan *finishedGame)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				g := &finishedGame{number: n, stats: NewGameStats()}
+				g.result = play(&g.output, n, g.stats)
+				finished <- g
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	// Games finish out of order; report them in order
+	pending := make(map[int]*finishedGame)
+	next := 1
+	stopped := false
+	for g := range finished {
+		pending[g.number] = g
+		for pending[next] != nil {
+			g := pending[next]
+			delete(pending, next)
+			next++
+			out.Write(g.output.Bytes())
+			stats.Merge(g.stats)
+			if done(g.result) && !stopped {
+				stopped = true
+				close(stop)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressBar is the one line a quiet run shows on a terminal, redrawn after
+// every game: games completed out of the total, the running score, and an
+// ETA from the average time per game so far
+type ProgressBar struct {
+	Games int // games the run will play, 0 if unknown or unlimited
+
+	mu    sync.Mutex
+	out   io.Writer
+	start time.Time
+	line  string // as last drawn, "" while cleared
+}
+
+// progressWidth is the number of characters in the bar itself
+const progressWidth = 30
+
+// progress is the bar being shown, if any, which the console logger clears
+// before printing a message and redraws after
+var progress *ProgressBar
+
+// NewProgressBar creates a bar drawn on the console at out and shows it
+func NewProgressBar(out io.Writer, games int) *ProgressBar {
+	p := &ProgressBar{Games: games, out: out, start: time.Now()}
+	p.Update(NewGameStats())
+	progress = p
+	return p
+}
+
+// Update redraws the bar for the run's statistics so far
+func (p *ProgressBar) Update(stats *GameStats) {
+	var line strings.Builder
+	elapsed := time.Since(p.start)
+	if p.Games > 0 {
+		filled := min(stats.Total*progressWidth/p.Games, progressWidth)
+		fmt.Fprintf(&line, "[%s%s] %d/%d", strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), stats.Total, p.Games)
+	} else {
+		fmt.Fprintf(&line, "%d games", stats.Total)
+	}
+	fmt.Fprintf(&line, "  X %d  O %d  draws %d", stats.XWins, stats.OWins, stats.Draws)
+	if stats.Errors > 0 {
+		fmt.Fprintf(&line, "  errors %d", stats.Errors)
+	}
+	fmt.Fprintf(&line, "  %s", elapsed.Round(time.Second))
+	if p.Games > 0 && stats.Total > 0 && stats.Total < p.Games {
+		remaining := elapsed / time.Duration(stats.Total) * time.Duration(p.Games-stats.Total)
+		fmt.Fprintf(&line, "  ETA %s", remaining.Round(time.Second))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.line = line.String()
+	fmt.Fprint(p.out, "\r\033[K"+p.line)
+}
+
+// Clear removes the bar so a message can be printed in its place
+func (p *ProgressBar) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.line != "" {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+}
+
+// Redraw shows the bar again after Clear
+func (p *ProgressBar) Redraw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.line != "" {
+		fmt.Fprint(p.out, "\r\033[K"+p.line)
+	}
+}
+
+// Done leaves the bar as last drawn and moves to the next line
+func (p *ProgressBar) Done() {
+	progress = nil
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.out)
+	p.line = ""
+}
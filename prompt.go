@@ -0,0 +1,208 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ticTacToePrompt is the built-in tic-tac-toe prompt, and the base every
+// -prompt-template is parsed over
+//
+//go:embed prompts/tictactoe.tmpl
+var ticTacToePrompt string
+
+// promptTranslations are the built-in prompt's translations for
+// -prompt-lang, tictactoe.LANG.tmpl
+//
+//go:embed prompts/tictactoe.*.tmpl
+var promptTranslations embed.FS
+
+// PromptLanguages are the languages of the built-in prompt for -prompt-lang
+var PromptLanguages = []string{"en", "de", "es", "fr", "ja", "zh"}
+
+// defaultPrompt holds the built-in prompt's templates
+var defaultPrompt = template.Must(newPromptTemplate("tictactoe.tmpl").Parse(ticTacToePrompt))
+
+// SetPromptLanguage switches the built-in prompt, and every -prompt-template
+// loaded after, to its translation into lang
+func SetPromptLanguage(lang string) error {
+	if !slices.Contains(PromptLanguages, lang) {
+		return fmt.Errorf("the prompt isn't translated into %q: must be one of %s", lang, strings.Join(PromptLanguages, ", "))
+	}
+	if lang == "en" {
+		return nil
+	}
+	text, err := promptTranslations.ReadFile("prompts/tictactoe." + lang + ".tmpl")
+	if err != nil {
+		return err
+	}
+	translated, err := template.Must(defaultPrompt.Clone()).Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("built-in %s prompt: %w", lang, err)
+	}
+	defaultPrompt = translated
+	return nil
+}
+
+// Hint levels for -hints: how much of its analysis of the position the
+// built-in prompts give the model
+const (
+	HintsFull    = "full"    // threats, and strategy advice
+	HintsThreats = "threats" // only the moves that win or must be blocked now
+	HintsNone    = "none"    // the board alone
+)
+
+// promptHints is the built-in prompts' hint level, set by -hints
+var promptHints = HintsFull
+
+// PromptData is what a prompt template is given
+type PromptData struct {
+	Game        string       // the game's name, e.g. "Tic-Tac-Toe"
+	Player      string       // the player to move, X or O
+	Opponent    string       // the other player
+	Board       string       // the board; on tic-tac-toe boards in the grid format, empty cells show their position number
+	History     []PromptMove // the moves so far, unless they're sent as chat turns with -chat
+	Threats     Threats      // moves that win now, for either player
+	Available   []int        // the legal moves
+	Taken       []int        // the taken positions, on tic-tac-toe boards
+	Rules       string       // the game's rules and answer format, in a few sentences
+	Context     string       // the game's own description of the position, threats, and strategy
+	Hints       string       // the -hints level: full, threats, or none
+	BoardFormat string       // how Board shows a tic-tac-toe board, the -board-format: grid, json, coords, or text
+}
+
+// PromptMove is a move played, for prompt templates
+type PromptMove struct {
+	Number   int    // 1 for the first move
+	Player   string // X or O
+	Position int
+	Move     string // the move described, e.g. "position 4"
+}
+
+// Threats are the moves that end the game at once
+type Threats struct {
+	Wins   []int // the player's moves that win now
+	Blocks []int // the opponent's winning moves, to block
+}
+
+// PromptTemplate is a prompt for plain text answers read from a
+// -prompt-template file
+type PromptTemplate struct {
+	Path string
+	tmpl *template.Template
+}
+
+func newPromptTemplate(name string) *template.Template {
+	return template.New(name).Funcs(template.FuncMap{
+		"join": func(positions []int, sep string) string {
+			items := make([]string, len(positions))
+			for i, position := range positions {
+				items[i] = strconv.Itoa(position)
+			}
+			return strings.Join(items, sep)
+		},
+	})
+}
+
+// LoadPromptTemplate reads a prompt template from path. It's parsed over
+// the built-in prompt, so it can use its parts with {{template "board" .}}
+// and the like, or redefine only some of them.
+func LoadPromptTemplate(path string) (*PromptTemplate, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the prompt template: %w", err)
+	}
+	tmpl, err := template.Must(defaultPrompt.Clone()).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+	return &PromptTemplate{Path: path, tmpl: tmpl}, nil
+}
+
+// Render is the prompt for player to move in game
+func (p *PromptTemplate) Render(game Game, player string, moveHistory []Move) (string, error) {
+	var prompt strings.Builder
+	if err := p.tmpl.Execute(&prompt, gamePromptData(game, player, moveHistory)); err != nil {
+		return "", fmt.Errorf("prompt template %s: %w", p.Path, err)
+	}
+	return prompt.String(), nil
+}
+
+// gamePromptData describes any game for a prompt template, as the built-in
+// prompt sees it for plain tic-tac-toe
+func gamePromptData(game Game, player string, moveHistory []Move) PromptData {
+	var data PromptData
+	if t, ok := game.(*TicTacToe); ok && !t.Wild && !t.Misere {
+		data = boardPromptData(t.Board, player, moveHistory)
+	} else {
+		data = PromptData{
+			Player:      player,
+			Opponent:    OtherPlayer(player),
+			Board:       showBoard(game),
+			Threats:     findThreats(game, player),
+			Available:   LegalPositions(game),
+			Hints:       promptHints,
+			BoardFormat: BoardGrid,
+		}
+		for i, move := range moveHistory {
+			data.History = append(data.History, PromptMove{Number: i + 1, Player: move.Player, Position: move.Position})
+		}
+	}
+	data.Game = game.Name()
+	data.Rules = game.Rules(player)
+	data.Context = game.Context(player, moveHistory)
+	for i, move := range moveHistory {
+		data.History[i].Move = game.Describe(move.Position)
+	}
+	return data
+}
+
+// boardPromptData describes a tic-tac-toe board for the built-in prompt
+func boardPromptData(board Board, player string, moveHistory []Move) PromptData {
+	data := PromptData{
+		Game:        "Tic-Tac-Toe",
+		Player:      player,
+		Opponent:    OtherPlayer(player),
+		Available:   AvailablePositions(board),
+		Taken:       TakenPositions(board),
+		Hints:       promptHints,
+		BoardFormat: promptBoardFormat,
+	}
+	data.Threats.Wins, data.Threats.Blocks = DetectThreats(board, player)
+	for i, move := range moveHistory {
+		data.History = append(data.History, PromptMove{Number: i + 1, Player: move.Player, Position: move.Position, Move: fmt.Sprintf("position %d", move.Position)})
+	}
+
+	data.Board = FormatBoard(board, promptBoardFormat)
+	return data
+}
+
+// findThreats finds the moves that win at once in any game, by trying
+// each legal move for either player
+func findThreats(game Game, player string) Threats {
+	var threats Threats
+	for _, position := range game.Legal() {
+		if g := game.Clone(); g.Play(player, position) && g.Winner() == player {
+			threats.Wins = append(threats.Wins, position)
+		}
+		opponent := OtherPlayer(player)
+		if g := game.Clone(); g.Play(opponent, position) && g.Winner() == opponent {
+			threats.Blocks = append(threats.Blocks, position)
+		}
+	}
+	return threats
+}
+
+// renderPrompt runs one of the built-in prompt's templates
+func renderPrompt(name string, data PromptData) string {
+	var prompt strings.Builder
+	if err := defaultPrompt.ExecuteTemplate(&prompt, name, data); err != nil {
+		panic(fmt.Sprintf("built-in prompt template %s: %v", name, err))
+	}
+	return prompt.String()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Puzzle is a tic-tac-toe position with the moves that solve it: those as
+// good as perfect play's, counting how fast it wins or how long it holds out
+type Puzzle struct {
+	Theme     string // what it tests, e.g. "Block"
+	Board     string // the cells as for -start-position
+	ToMove    string
+	Solutions []int
+}
+
+// Puzzles are the built-in puzzles, from winning and blocking to forks and
+// the replies to the openings
+var Puzzles = []Puzzle{
+	{Theme: "Win", Board: "XX./OO./...", ToMove: PlayerX, Solutions: []int{2}},
+	{Theme: "Win", Board: "X.O/.X./O..", ToMove: PlayerX, Solutions: []int{8}},
+	{Theme: "Win", Board: "X.O/.O./X..", ToMove: PlayerX, Solutions: []int{3}},
+	{Theme: "Win", Board: "XX./OO./X..", ToMove: PlayerO, Solutions: []int{5}},
+	{Theme: "Block", Board: "XX./.O./...", ToMove: PlayerO, Solutions: []int{2}},
+	{Theme: "Block", Board: "OX./.X./...", ToMove: PlayerO, Solutions: []int{7}},
+	{Theme: "Block", Board: "X../O../..X", ToMove: PlayerO, Solutions: []int{4}},
+	{Theme: "Fork", Board: "XO./.X./..O", ToMove: PlayerX, Solutions: []int{3, 6}},
+	{Theme: "Fork", Board: "X../OX./..O", ToMove: PlayerX, Solutions: []int{1, 2}},
+	{Theme: "Fork", Board: "O.X/O../.X.", ToMove: PlayerX, Solutions: []int{6}},
+	{Theme: "Defend a fork", Board: "X../.O./..X", ToMove: PlayerO, Solutions: []int{1, 3, 5, 7}},
+	{Theme: "Defend a fork", Board: "O../.X./..X", ToMove: PlayerO, Solutions: []int{2, 6}},
+	{Theme: "Defend a fork", Board: "X../.O./.X.", ToMove: PlayerO, Solutions: []int{3, 5, 6, 8}},
+	{Theme: "Opening", Board: "X../.../...", ToMove: PlayerO, Solutions: []int{4}},
+	{Theme: "Opening", Board: ".../.X./...", ToMove: PlayerO, Solutions: []int{0, 2, 6, 8}},
+}
+
+// Game returns a game at the puzzle's position
+func (p Puzzle) Game() *TicTacToe {
+	game := NewTicTacToe()
+	game.LoadPosition(p.Board)
+	return game
+}
+
+// PuzzleAnswer is an agent's answer to a puzzle
+type PuzzleAnswer struct {
+	Puzzle   Puzzle
+	Position int    // -1 without a move
+	Error    string // why there's no move, or why it isn't legal
+	Solved   bool
+	Duration time.Duration
+	Usage    Usage
+}
+
+// SolvePuzzles poses each puzzle to agent once a pass, printing each answer
+// to out, and stops early if stop says so after an answer. An answer that
+// isn't a legal move fails the puzzle; there are no retries.
+func SolvePuzzles(out io.Writer, agent Agent, puzzles []Puzzle, passes int, debug bool, stop func() bool) []PuzzleAnswer {
+	var answers []PuzzleAnswer
+	for pass := 1; pass <= passes; pass++ {
+		for i, puzzle := range puzzles {
+			game := puzzle.Game()
+			fmt.Fprintf(out, "\n--- Puzzle %d of %d, pass %d: %s, %s to move ---\n", i+1, len(puzzles), pass, puzzle.Theme, puzzle.ToMove)
+			displayGame(out, game, nil)
+			result, err := agent.ChooseMove(game, puzzle.ToMove, nil)
+			if debug && result.Prompt != "" {
+				fmt.Fprintln(out, "\n========== PROMPT DEBUG ==========")
+				fmt.Fprintln(out, result.Prompt)
+				fmt.Fprintln(out, "==================================")
+				fmt.Fprintln(out)
+			}
+			if result.Response != "" {
+				fmt.Fprintf(out, "LLM response: %s (%.2fs)\n", strings.TrimSpace(result.Response), result.Duration.Seconds())
+			}
+			answer := PuzzleAnswer{Puzzle: puzzle, Position: -1, Duration: result.Duration, Usage: result.Usage}
+			switch {
+			case err != nil:
+				answer.Error = capitalize(err.Error())
+			case !slices.Contains(game.Legal(), result.Position):
+				answer.Error = fmt.Sprintf("%s isn't a legal move", capitalize(game.Describe(result.Position)))
+			default:
+				answer.Position = result.Position
+				answer.Solved = slices.Contains(puzzle.Solutions, result.Position)
+			}
+			var solutions []string
+			for _, position := range puzzle.Solutions {
+				solutions = append(solutions, fmt.Sprint(position))
+			}
+			switch {
+			case answer.Error != "":
+				fmt.Fprintf(out, "❌ %s (solution: %s)\n", answer.Error, strings.Join(solutions, " or "))
+			case answer.Solved:
+				fmt.Fprintf(out, "✅ %s plays %s: solved\n", agent.Name(), game.Describe(answer.Position))
+			default:
+				fmt.Fprintf(out, "❌ %s plays %s (solution: %s)\n", agent.Name(), game.Describe(answer.Position), strings.Join(solutions, " or "))
+			}
+			answers = append(answers, answer)
+			if stop() {
+				return answers
+			}
+		}
+	}
+	return answers
+}
+
+// PrintPuzzleResults prints the share of puzzles solved, overall and by
+// theme with 95% confidence intervals, and what the answers cost
+func PrintPuzzleResults(w io.Writer, answers []PuzzleAnswer) {
+	var themes []string
+	solved, posed := map[string]int{}, map[string]int{}
+	failed := 0
+	var usage Usage
+	var total time.Duration
+	for _, answer := range answers {
+		theme := answer.Puzzle.Theme
+		if !slices.Contains(themes, theme) {
+			themes = append(themes, theme)
+		}
+		posed[theme]++
+		if answer.Solved {
+			solved[theme]++
+			solved[""]++
+		}
+		if answer.Error != "" {
+			failed++
+		}
+		usage.Add(answer.Usage)
+		total += answer.Duration
+	}
+	fmt.Fprintf(w, "  %-16s %7s %22s\n", "Theme", "Posed", "Solved % [95% CI]")
+	for _, theme := range themes {
+		fmt.Fprintf(w, "  %-16s %7d %22s\n", theme, posed[theme], describeRate(solved[theme], posed[theme]))
+	}
+	fmt.Fprintf(w, "  %-16s %7d %22s\n", "All", len(answers), describeRate(solved[""], len(answers)))
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+	if failed > 0 {
+		fmt.Fprintf(w, "No legal move:      %d (%.1f%% of answers)\n", failed, rate(failed, len(answers))*100)
+	}
+	if len(answers) > 0 && total > 0 {
+		fmt.Fprintf(w, "Average response:   %.2fs\n", (total / time.Duration(len(answers))).Seconds())
+	}
+	if usage.Total() > 0 {
+		fmt.Fprintf(w, "Token Usage:\n")
+		fmt.Fprintf(w, "  Prompt tokens:     %d\n", usage.PromptTokens)
+		fmt.Fprintf(w, "  Completion tokens: %d\n", usage.CompletionTokens)
+	}
+}
+
+// describeRate shows count out of total as a percentage with its 95%
+// Wilson interval, or "-" with nothing to count
+func describeRate(count, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	low, high := WilsonInterval(rate(count, total), total)
+	return fmt.Sprintf("%5.1f%% [%4.1f, %5.1f]", rate(count, total)*100, low*100, high*100)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestPuzzleSolutions checks every built-in puzzle against perfect play: its
+// solutions are exactly the optimal moves, and some legal move isn't one
+func TestPuzzleSolutions(t *testing.T) {
+	for i, puzzle := range Puzzles {
+		game := NewTicTacToe()
+		toMove, err := game.LoadPosition(puzzle.Board)
+		if err != nil || game.Winner() != "" {
+			t.Errorf("puzzle %d (%s) %s isn't an unfinished position: %v", i+1, puzzle.Theme, puzzle.Board, err)
+			continue
+		}
+		if toMove != "" && toMove != puzzle.ToMove {
+			t.Errorf("puzzle %d (%s) %s has %s to move, not %s", i+1, puzzle.Theme, puzzle.Board, toMove, puzzle.ToMove)
+		}
+		var optimal []int
+		for _, position := range game.Legal() {
+			if GradeMoves(game, []Move{{Player: puzzle.ToMove, Position: position}})[0] == Optimal {
+				optimal = append(optimal, position)
+			}
+		}
+		if !slices.Equal(optimal, puzzle.Solutions) {
+			t.Errorf("puzzle %d (%s) %s has solutions %v, want the optimal moves %v", i+1, puzzle.Theme, puzzle.Board, puzzle.Solutions, optimal)
+		}
+		if len(optimal) == len(game.Legal()) {
+			t.Errorf("puzzle %d (%s) %s is solved by any move", i+1, puzzle.Theme, puzzle.Board)
+		}
+	}
+}
+
+// TestSolvePuzzles scores an agent that plays perfectly and one that
+// answers with an occupied cell
+func TestSolvePuzzles(t *testing.T) {
+	stop := func() bool { return false }
+	answers := SolvePuzzles(discard{}, &MinimaxAgent{}, Puzzles, 2, false, stop)
+	if len(answers) != 2*len(Puzzles) {
+		t.Fatalf("got %d answers to %d puzzles over 2 passes", len(answers), len(Puzzles))
+	}
+	for _, answer := range answers {
+		if !answer.Solved {
+			t.Errorf("minimax didn't solve %s %s with %d", answer.Puzzle.Theme, answer.Puzzle.Board, answer.Position)
+		}
+	}
+
+	occupied := agentFunc(func(game Game) int {
+		for position := 0; position < 9; position++ {
+			if !slices.Contains(game.Legal(), position) {
+				return position
+			}
+		}
+		return -1
+	})
+	answers = SolvePuzzles(discard{}, occupied, Puzzles[:1], 1, false, stop)
+	if answers[0].Solved || answers[0].Position != -1 || answers[0].Error == "" {
+		t.Errorf("an occupied cell scored %+v, want an unsolved answer with an error", answers[0])
+	}
+}
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return len(p), nil }
+
+// agentFunc is an agent that plays the move the function picks
+type agentFunc func(game Game) int
+
+func (a agentFunc) Name() string { return "func" }
+
+func (a agentFunc) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	return MoveResult{Position: a(game)}, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// MoveGrade rates a move against perfect play
+type MoveGrade int
+
+const (
+	Ungraded   MoveGrade = iota // not judged, such as a random opening move
+	Optimal                     // as good as the best move, including how fast it wins or how long it holds out
+	Safe                        // keeps the same outcome as the best move, only slower
+	Inaccuracy                  // lets a won position slip to a draw
+	Blunder                     // turns a drawn or won position into a lost one
+)
+
+func (g MoveGrade) String() string {
+	switch g {
+	case Optimal:
+		return "optimal"
+	case Safe:
+		return "safe"
+	case Inaccuracy:
+		return "inaccuracy"
+	case Blunder:
+		return "blunder"
+	}
+	return ""
+}
+
+// Quality is what a move of this grade is worth, from 1 for an optimal move
+// down to 0 for a blunder
+func (g MoveGrade) Quality() float64 {
+	switch g {
+	case Optimal:
+		return 1
+	case Safe:
+		return 2.0 / 3
+	case Inaccuracy:
+		return 1.0 / 3
+	}
+	return 0
+}
+
+// Solvable reports whether minimax searches game to the end by default, so
+// its moves can be judged against perfect play
+func Solvable(game Game) bool {
+	e, ok := game.(Evaluator)
+	return !ok || e.SearchDepth() == math.MaxInt
+}
+
+// GradeMoves replays moves, which alternate between the players, from start
+// and grades each one against the best move available
+func GradeMoves(start Game, moves []Move) []MoveGrade {
+	grades := make([]MoveGrade, len(moves))
+	game := start.Clone()
+	for i, move := range moves {
+		_, best := BestMove(game, move.Player)
+		game.Play(move.Player, move.Position)
+		played := -negamax(game, OtherPlayer(move.Player), 1, math.MaxInt, -winScore-1, winScore+1)
+		switch {
+		case played >= best:
+			grades[i] = Optimal
+		case sign(played) == sign(best):
+			grades[i] = Safe
+		case played < 0:
+			grades[i] = Blunder
+		default:
+			grades[i] = Inaccuracy
+		}
+	}
+	return grades
+}
+
+func sign(score int) int {
+	switch {
+	case score > 0:
+		return 1
+	case score < 0:
+		return -1
+	}
+	return 0
+}
+
+// PrintMoveQuality prints how each model's moves graded against perfect play,
+// if any moves were graded
+func PrintMoveQuality(w io.Writer, stats *GameStats) {
+	if stats.GradedMoves == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Move quality against perfect play (quality: optimal 100%, safe 67%, inaccuracy 33%, blunder 0%):")
+	fmt.Fprintf(w, "  %-24s %7s %8s %6s %11s %8s %8s\n", "Model", "Moves", "Optimal", "Safe", "Inaccuracy", "Blunder", "Quality")
+	for _, name := range sortedKeys(stats.Models) {
+		m := stats.Models[name]
+		if m.GradedMoves == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  %-24s %7d %8d %6d %11d %8d %7.1f%%\n", shortName(name, 24), m.GradedMoves,
+			m.Grades[Optimal], m.Grades[Safe], m.Grades[Inaccuracy], m.Grades[Blunder], m.Quality()*100)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
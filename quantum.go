@@ -0,0 +1,554 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func init() {
+	RegisterVariant("tictactoe", "quantum", func() Game { return NewQuantumTicTacToe() })
+}
+
+// quantumMark is a spooky mark: a move in superposition across two squares
+type quantumMark struct {
+	Player string
+	Move   int // 1-based move number, shown as the mark's subscript
+	Cells  [2]int
+}
+
+// QuantumTicTacToe is quantum tic-tac-toe. Each move places a spooky mark in
+// two squares at once, entangling them. A move that closes a cycle of
+// entangled squares forces a collapse: the other player chooses which square
+// that move's mark lands in, and every mark entangled with it follows. Only
+// collapsed (classical) marks count for three in a row; if both players get a
+// line in the same collapse, the line finished by the earlier moves wins.
+//
+// Move numbers: a spooky move in squares a < b is a*10+b, the last free
+// square taken classically is 90 plus the square, and a collapse into square
+// c adds (c+1)*100 to the move that follows it (0 for none).
+type QuantumTicTacToe struct {
+	Classical     [9]string // collapsed marks, Empty where there are none
+	ClassicalMove [9]int    // move number of each collapsed mark
+	Spooky        []quantumMark
+	moves         int
+	pending       int // index in Spooky of the mark that closed a cycle, -1 for none
+}
+
+// NewQuantumTicTacToe creates a game on an empty board
+func NewQuantumTicTacToe() *QuantumTicTacToe {
+	q := &QuantumTicTacToe{pending: -1}
+	for i := range q.Classical {
+		q.Classical[i] = Empty
+	}
+	return q
+}
+
+const (
+	quantumFinal    = 90  // last free square taken classically, plus the square
+	quantumCollapse = 100 // collapse into square c adds (c+1) times this
+)
+
+func quantumPair(a, b int) int {
+	return min(a, b)*10 + max(a, b)
+}
+
+func (q *QuantumTicTacToe) Name() string { return "Quantum Tic-Tac-Toe" }
+
+func (q *QuantumTicTacToe) Clone() Game {
+	clone := *q
+	clone.Spooky = append([]quantumMark(nil), q.Spooky...)
+	return &clone
+}
+
+// free returns the squares without a classical mark
+func (q *QuantumTicTacToe) free() []int {
+	var squares []int
+	for i, cell := range q.Classical {
+		if cell == Empty {
+			squares = append(squares, i)
+		}
+	}
+	return squares
+}
+
+// entangled reports whether squares a and b are already joined by a chain of
+// spooky marks, so that a mark in both would close a cycle
+func (q *QuantumTicTacToe) entangled(a, b int) bool {
+	seen := map[int]bool{a: true}
+	queue := []int{a}
+	for len(queue) > 0 {
+		square := queue[0]
+		queue = queue[1:]
+		if square == b {
+			return true
+		}
+		for _, mark := range q.Spooky {
+			for i, cell := range mark.Cells {
+				if other := mark.Cells[1-i]; cell == square && !seen[other] {
+					seen[other] = true
+					queue = append(queue, other)
+				}
+			}
+		}
+	}
+	return false
+}
+
+// groups returns the sets of squares entangled with each other, for the prompt
+func (q *QuantumTicTacToe) groups() [][]int {
+	var groups [][]int
+	grouped := make(map[int]bool)
+	for _, square := range q.free() {
+		if grouped[square] {
+			continue
+		}
+		var group []int
+		for _, other := range q.free() {
+			if !grouped[other] && (other == square || q.entangled(square, other)) {
+				grouped[other] = true
+				group = append(group, other)
+			}
+		}
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// collapse puts spooky mark i in square, then moves every mark that shared a
+// square with a collapsed mark into its other square, until none are left
+func (q *QuantumTicTacToe) collapse(i, square int) {
+	type landing struct {
+		mark   quantumMark
+		square int
+	}
+	queue := []landing{{q.Spooky[i], square}}
+	q.Spooky = append(q.Spooky[:i:i], q.Spooky[i+1:]...)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if q.Classical[next.square] != Empty {
+			continue
+		}
+		q.Classical[next.square] = next.mark.Player
+		q.ClassicalMove[next.square] = next.mark.Move
+		var rest []quantumMark
+		for _, mark := range q.Spooky {
+			switch next.square {
+			case mark.Cells[0]:
+				queue = append(queue, landing{mark, mark.Cells[1]})
+			case mark.Cells[1]:
+				queue = append(queue, landing{mark, mark.Cells[0]})
+			default:
+				rest = append(rest, mark)
+			}
+		}
+		q.Spooky = rest
+	}
+	q.pending = -1
+}
+
+// followUps returns the moves possible without a collapse pending: spooky
+// marks in any two free squares, or the last free square taken classically
+func (q *QuantumTicTacToe) followUps() []int {
+	free := q.free()
+	if len(free) == 1 {
+		return []int{quantumFinal + free[0]}
+	}
+	var moves []int
+	for i, a := range free {
+		for _, b := range free[i+1:] {
+			moves = append(moves, quantumPair(a, b))
+		}
+	}
+	return moves
+}
+
+// lineWinner returns the player with a classical line, breaking a tie by
+// which line's last mark was placed first
+func (q *QuantumTicTacToe) lineWinner() string {
+	winner, earliest := "", 0
+	for _, line := range ticTacToeLines {
+		player := q.Classical[line[0]]
+		if player == Empty || q.Classical[line[1]] != player || q.Classical[line[2]] != player {
+			continue
+		}
+		last := max(q.ClassicalMove[line[0]], q.ClassicalMove[line[1]], q.ClassicalMove[line[2]])
+		if winner == "" || last < earliest {
+			winner, earliest = player, last
+		}
+	}
+	return winner
+}
+
+// Legal lists the spooky or final moves, or while a collapse is pending,
+// each choice of square combined with each move that can follow it
+func (q *QuantumTicTacToe) Legal() []int {
+	if q.lineWinner() != "" {
+		return nil
+	}
+	if q.pending < 0 {
+		return q.followUps()
+	}
+	var moves []int
+	for _, square := range q.Spooky[q.pending].Cells {
+		next := q.Clone().(*QuantumTicTacToe)
+		next.collapse(q.pending, square)
+		base := (square + 1) * quantumCollapse
+		followUps := next.followUps()
+		if next.lineWinner() != "" || len(followUps) == 0 {
+			moves = append(moves, base)
+			continue
+		}
+		for _, move := range followUps {
+			moves = append(moves, base+move)
+		}
+	}
+	sort.Ints(moves)
+	return moves
+}
+
+func (q *QuantumTicTacToe) Play(player string, position int) bool {
+	if !containsPosition(q.Legal(), position) {
+		return false
+	}
+	if position >= quantumCollapse {
+		q.collapse(q.pending, position/quantumCollapse-1)
+		position %= quantumCollapse
+		if position == 0 {
+			return true
+		}
+	}
+	q.moves++
+	if position >= quantumFinal {
+		square := position - quantumFinal
+		q.Classical[square], q.ClassicalMove[square] = player, q.moves
+		return true
+	}
+	a, b := position/10, position%10
+	closesCycle := q.entangled(a, b)
+	q.Spooky = append(q.Spooky, quantumMark{Player: player, Move: q.moves, Cells: [2]int{a, b}})
+	if closesCycle {
+		q.pending = len(q.Spooky) - 1
+	}
+	return true
+}
+
+func (q *QuantumTicTacToe) Winner() string {
+	if winner := q.lineWinner(); winner != "" {
+		return winner
+	}
+	if len(q.Legal()) == 0 {
+		return "draw"
+	}
+	return ""
+}
+
+// describeFollowUp names a move that doesn't involve a collapse
+func describeFollowUp(move int) string {
+	if move >= quantumFinal {
+		return fmt.Sprintf("position %d", move-quantumFinal)
+	}
+	return fmt.Sprintf("%d-%d", move/10, move%10)
+}
+
+func (q *QuantumTicTacToe) Describe(position int) string {
+	if position >= quantumCollapse {
+		collapse := fmt.Sprintf("collapse into %d", position/quantumCollapse-1)
+		if position%quantumCollapse == 0 {
+			return collapse
+		}
+		return collapse + ", then " + describeFollowUp(position%quantumCollapse)
+	}
+	return describeFollowUp(position)
+}
+
+// cellText shows a square's classical mark, or its spooky marks with their
+// move numbers, e.g. "x1 o4"
+func (q *QuantumTicTacToe) cellText(square int) string {
+	if q.Classical[square] != Empty {
+		return fmt.Sprintf("%s%d", q.Classical[square], q.ClassicalMove[square])
+	}
+	var marks []string
+	for _, mark := range q.Spooky {
+		if mark.Cells[0] == square || mark.Cells[1] == square {
+			marks = append(marks, fmt.Sprintf("%s%d", strings.ToLower(mark.Player), mark.Move))
+		}
+	}
+	return strings.Join(marks, " ")
+}
+
+// boardText draws the board with each square's number, classical mark in
+// capitals, and spooky marks in lower case
+func (q *QuantumTicTacToe) boardText() string {
+	width := 9
+	for square := range q.Classical {
+		width = max(width, len(q.cellText(square))+4)
+	}
+	separator := strings.Repeat("-", 3*(width+3)+1)
+	var text strings.Builder
+	text.WriteString(separator + "\n")
+	for row := 0; row < 3; row++ {
+		text.WriteString("|")
+		for col := 0; col < 3; col++ {
+			square := row*3 + col
+			text.WriteString(fmt.Sprintf(" %-*s |", width, fmt.Sprintf("%d: %s", square, q.cellText(square))))
+		}
+		text.WriteString("\n" + separator + "\n")
+	}
+	return text.String()
+}
+
+func (q *QuantumTicTacToe) Display(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprint(w, q.boardText())
+	fmt.Fprintln(w)
+}
+
+func (q *QuantumTicTacToe) Rules(player string) string {
+	return fmt.Sprintf("You are playing Quantum Tic-Tac-Toe as player %s. Squares are numbered 0-8, left to right and top to bottom. "+
+		"Each move places a spooky mark in TWO free squares at once, answered as a position pair such as 2-6. "+
+		"A move that closes a cycle of entangled squares makes the other player collapse it: they answer e.g. \"collapse 2, then 4-8\", "+
+		"choosing the square that move's mark lands in before making their own move. Only collapsed marks count for three in a row.", player)
+}
+
+func (q *QuantumTicTacToe) Context(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	prompt.WriteString(fmt.Sprintf("You are playing Quantum Tic-Tac-Toe as player %s.\n", player))
+	prompt.WriteString("RULES: Each move places a SPOOKY mark in TWO free squares at once, e.g. 2-6. Spooky marks entangle their two squares. " +
+		"When a move closes a CYCLE (its two squares were already linked by a chain of spooky marks), the OTHER player chooses which of " +
+		"the move's two squares it collapses into; every mark entangled with it then collapses too. Only COLLAPSED (classical) marks " +
+		"count for three in a row. If a collapse gives both players a line, the line completed by earlier moves wins.\n\n")
+
+	if len(moveHistory) > 0 {
+		prompt.WriteString("Move history:\n")
+		for i, move := range moveHistory {
+			prompt.WriteString(fmt.Sprintf("%d. Player %s played %s\n", i+1, move.Player, q.Describe(move.Position)))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("Current board (square number: collapsed marks in CAPITALS, spooky marks in lower case, with their move numbers):\n")
+	prompt.WriteString(q.boardText())
+
+	free := q.free()
+	prompt.WriteString(fmt.Sprintf("\n✅ FREE SQUARES (no collapsed mark): %s\n", joinPositions(free)))
+	if groups := q.groups(); len(groups) > 0 && q.pending < 0 {
+		var linked []string
+		for _, group := range groups {
+			linked = append(linked, "{"+joinPositions(group)+"}")
+		}
+		prompt.WriteString(fmt.Sprintf("🔗 ENTANGLED GROUPS: %s. A spooky mark in two squares of the same group closes a cycle, "+
+			"and then %s chooses how it collapses.\n", strings.Join(linked, " "), opponent))
+	}
+
+	if q.pending >= 0 {
+		mark := q.Spooky[q.pending]
+		prompt.WriteString(fmt.Sprintf("\n🌀 COLLAPSE REQUIRED: %s's move %d (in squares %d and %d) closed a cycle. You must first choose the square it collapses into:\n",
+			mark.Player, mark.Move, mark.Cells[0], mark.Cells[1]))
+		for _, square := range mark.Cells {
+			next := q.Clone().(*QuantumTicTacToe)
+			next.collapse(q.pending, square)
+			outcome := "no line yet"
+			if winner := next.lineWinner(); winner != "" {
+				outcome = fmt.Sprintf("%s WINS", winner)
+			}
+			var collapsed []string
+			for s, cell := range next.Classical {
+				if cell != Empty && q.Classical[s] == Empty {
+					collapsed = append(collapsed, fmt.Sprintf("%s%d at %d", cell, next.ClassicalMove[s], s))
+				}
+			}
+			prompt.WriteString(fmt.Sprintf("  - collapse %d: %s (%s)\n", square, strings.Join(collapsed, ", "), outcome))
+		}
+	}
+
+	if promptHints != HintsNone {
+		winningMoves := ImmediateWins(q, player)
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		if len(winningMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("🎯 YOU CAN WIN NOW! Play %s!\n", q.Describe(winningMoves[0])))
+		} else {
+			prompt.WriteString("No immediate wins. Lines only count once they collapse, so plan where cycles will put the marks.\n")
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. WIN: Choose collapses that complete your lines, or avoid ones that complete your opponent's\n")
+		prompt.WriteString(fmt.Sprintf("2. CAUTION: Closing a cycle hands %s the collapse choice, so only do it when both outcomes suit you\n", opponent))
+		prompt.WriteString("3. STRATEGIC: Spread spooky marks over squares that share lines, such as the center and the corners\n")
+	}
+
+	return prompt.String()
+}
+
+func (q *QuantumTicTacToe) Prompt(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	prompt.WriteString(q.Context(player, moveHistory))
+
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	switch {
+	case q.pending >= 0:
+		mark := q.Spooky[q.pending]
+		prompt.WriteString(fmt.Sprintf("1. First choose the collapse, %d or %d, then your own move in two FREE squares that are still free afterwards\n", mark.Cells[0], mark.Cells[1]))
+		prompt.WriteString(fmt.Sprintf("2. ONLY respond in the form: collapse %d, then 3-5\n", mark.Cells[0]))
+		prompt.WriteString("3. If the collapse ends the game or leaves no move, respond with the collapse alone\n")
+		prompt.WriteString("4. Do NOT include any other text, explanation, or formatting\n")
+	case len(q.free()) == 1:
+		prompt.WriteString(fmt.Sprintf("1. Only square %d is free, so you take it with a collapsed mark\n", q.free()[0]))
+		prompt.WriteString(fmt.Sprintf("2. ONLY respond with the number %d\n", q.free()[0]))
+		prompt.WriteString("3. Do NOT include any other text, explanation, or formatting\n")
+	default:
+		prompt.WriteString("1. Choose TWO different FREE squares for your spooky mark\n")
+		prompt.WriteString("2. ONLY respond with the position pair, e.g. 0-4\n")
+		prompt.WriteString("3. Do NOT include any other text, explanation, or formatting\n")
+	}
+
+	return prompt.String()
+}
+
+var (
+	quantumCollapseChoice = regexp.MustCompile(`(?i)collapse\D*?([0-8])`)
+	quantumPairAnswer     = regexp.MustCompile(`(?i)\b([0-8])\s*(?:-|,|/|&|\band\b|\s)\s*([0-8])\b|\b([0-8])([0-8])\b`)
+	quantumSquare         = regexp.MustCompile(`\b([0-8])\b`)
+)
+
+// ParseMove reads a position pair such as 2-6 or "2 and 6", the last free
+// square alone, and when a collapse is pending, "collapse 2" before either
+func (q *QuantumTicTacToe) ParseMove(response string) (int, error) {
+	game, move, rest := q, 0, response
+	if q.pending >= 0 {
+		match := quantumCollapseChoice.FindStringSubmatchIndex(response)
+		if match == nil {
+			return -1, fmt.Errorf("no collapse choice found in response: %s", strings.TrimSpace(response))
+		}
+		square := int(response[match[2]] - '0')
+		if square != q.Spooky[q.pending].Cells[0] && square != q.Spooky[q.pending].Cells[1] {
+			return -1, fmt.Errorf("move %d can't collapse into square %d", q.Spooky[q.pending].Move, square)
+		}
+		game = q.Clone().(*QuantumTicTacToe)
+		game.collapse(q.pending, square)
+		move, rest = (square+1)*quantumCollapse, response[match[1]:]
+		if game.lineWinner() != "" || len(game.followUps()) == 0 {
+			return move, nil
+		}
+	}
+
+	if free := game.free(); len(free) == 1 {
+		match := quantumSquare.FindStringSubmatch(rest)
+		if match == nil {
+			return -1, fmt.Errorf("no square found in response: %s", strings.TrimSpace(response))
+		}
+		return move + quantumFinal + int(match[1][0]-'0'), nil
+	}
+	match := quantumPairAnswer.FindStringSubmatch(rest)
+	if match == nil {
+		return -1, fmt.Errorf("no position pair found in response: %s", strings.TrimSpace(response))
+	}
+	a, b := match[1], match[2]
+	if a == "" {
+		a, b = match[3], match[4]
+	}
+	if a == b {
+		return -1, fmt.Errorf("a spooky mark needs two different squares, got %s-%s", a, b)
+	}
+	return move + quantumPair(int(a[0]-'0'), int(b[0]-'0')), nil
+}
+
+func (q *QuantumTicTacToe) HumanHint() string {
+	if q.pending >= 0 {
+		return `collapse and pair, e.g. "collapse 2, then 4-8"`
+	}
+	return "two squares, e.g. 0-4"
+}
+
+func (q *QuantumTicTacToe) ParseHumanMove(input string) (int, error) {
+	return q.ParseMove(input)
+}
+
+// MoveFormat explains why the structured answer modes don't apply
+func (q *QuantumTicTacToe) MoveFormat() string {
+	return `position pairs and collapse choices such as "collapse 2, then 4-8"`
+}
+
+// SearchDepth keeps minimax to four moves ahead, since each move has up to
+// 36 replies; any less and it misses collapses that lose a move or two later
+func (q *QuantumTicTacToe) SearchDepth() int {
+	return 4
+}
+
+// Evaluate scores the collapsed marks by the lines they could still complete.
+// A pending collapse is the player to move's to choose, so the position is
+// scored as it stands after the better choice, since a collapse can decide
+// the game at once.
+func (q *QuantumTicTacToe) Evaluate(player string) int {
+	if q.pending < 0 {
+		return q.lineScore(player)
+	}
+	best := -winScore
+	for _, square := range q.Spooky[q.pending].Cells {
+		next := q.Clone().(*QuantumTicTacToe)
+		next.collapse(q.pending, square)
+		score := next.lineScore(player)
+		switch next.lineWinner() {
+		case "":
+		case player:
+			score = winScore / 2
+		default:
+			score = -winScore / 2
+		}
+		best = max(best, score)
+	}
+	return best
+}
+
+// lineScore scores the marks by the lines they could still complete, a
+// collapsed mark counting twice a spooky one that might land there
+func (q *QuantumTicTacToe) lineScore(player string) int {
+	var spooky [9]map[string]bool
+	for _, mark := range q.Spooky {
+		for _, square := range mark.Cells {
+			if spooky[square] == nil {
+				spooky[square] = map[string]bool{}
+			}
+			spooky[square][mark.Player] = true
+		}
+	}
+	score := 0
+	for _, line := range ticTacToeLines {
+		mine, theirs := 0, 0
+		blocked := map[string]bool{}
+		for _, square := range line {
+			switch q.Classical[square] {
+			case player:
+				mine += 2
+				blocked[OtherPlayer(player)] = true
+			case Empty:
+				if spooky[square][player] {
+					mine++
+				}
+				if spooky[square][OtherPlayer(player)] {
+					theirs++
+				}
+			default:
+				theirs += 2
+				blocked[player] = true
+			}
+		}
+		if !blocked[player] {
+			score += mine * mine
+		}
+		if !blocked[OtherPlayer(player)] {
+			score -= theirs * theirs
+		}
+	}
+	return score
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// seededRandomAgent plays uniformly random legal moves from a fixed seed, so
+// tests against it are repeatable
+type seededRandomAgent struct {
+	rng *rand.Rand
+}
+
+func (r *seededRandomAgent) Name() string { return "random" }
+
+func (r *seededRandomAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	available := game.Legal()
+	return MoveResult{Position: available[r.rng.IntN(len(available))]}, nil
+}
+
+func TestQuantumCollapse(t *testing.T) {
+	game := NewQuantumTicTacToe()
+	for _, move := range []struct {
+		player   string
+		position int
+	}{{PlayerX, 1}, {PlayerO, 12}, {PlayerX, 2}} {
+		if !game.Play(move.player, move.position) {
+			t.Fatalf("%s can't play %s", move.player, game.Describe(move.position))
+		}
+	}
+	// X's 0-2 closes the cycle 0-1-2, so O collapses it into 0 or 2 first
+	for _, position := range game.Legal() {
+		if square := position/quantumCollapse - 1; square != 0 && square != 2 {
+			t.Fatalf("legal move %d collapses into %d, want 0 or 2", position, square)
+		}
+	}
+	if game.Play(PlayerO, 34) {
+		t.Fatal("O played 3-4 without collapsing the cycle")
+	}
+	if !game.Play(PlayerO, 1*quantumCollapse+34) {
+		t.Fatal("O can't collapse into 0 and play 3-4")
+	}
+	// X's 0-2 lands in 0, pushing X's 0-1 into 1 and O's 1-2 into 2
+	if want := [3]string{PlayerX, PlayerX, PlayerO}; [3]string(game.Classical[:3]) != want {
+		t.Errorf("top row after the collapse = %v, want %v", game.Classical[:3], want)
+	}
+	if len(game.Spooky) != 1 || game.Spooky[0].Cells != [2]int{3, 4} {
+		t.Errorf("spooky marks after the collapse = %+v, want O's 3-4", game.Spooky)
+	}
+}
+
+func TestQuantumSimultaneousLines(t *testing.T) {
+	// A collapse can finish a line for each player; the line whose last mark
+	// came first wins
+	game := NewQuantumTicTacToe()
+	copy(game.Classical[:], []string{PlayerX, PlayerX, PlayerX, PlayerO, PlayerO, PlayerO})
+	copy(game.ClassicalMove[:], []int{1, 3, 5, 2, 4, 6})
+	if winner := game.Winner(); winner != PlayerX {
+		t.Errorf("Winner() = %q, want X, whose line finished on move 5 before O's on move 6", winner)
+	}
+}
+
+func TestQuantumMinimaxBeatsRandom(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays whole games of quantum tic-tac-toe with minimax")
+	}
+	const games = 10
+	for _, minimax := range []string{PlayerX, PlayerO} {
+		random := &seededRandomAgent{rng: rand.New(rand.NewPCG(1, 2))}
+		agents := map[string]Agent{minimax: &MinimaxAgent{}, OtherPlayer(minimax): random}
+		wins := 0
+		for i := 0; i < games; i++ {
+			game := Game(NewQuantumTicTacToe())
+			toMove := PlayerX
+			for game.Winner() == "" {
+				result, err := agents[toMove].ChooseMove(game, toMove, nil)
+				if err != nil || !game.Play(toMove, result.Position) {
+					t.Fatalf("%s can't play %d: %v", toMove, result.Position, err)
+				}
+				toMove = OtherPlayer(toMove)
+			}
+			switch game.Winner() {
+			case minimax:
+				wins++
+			case OtherPlayer(minimax):
+				t.Errorf("minimax as %s lost game %d to random:\n%s", minimax, i+1, showBoard(game))
+			}
+		}
+		if wins < games*3/4 {
+			t.Errorf("minimax as %s won %d of %d games against random, want at least %d", minimax, wins, games, games*3/4)
+		}
+	}
+}
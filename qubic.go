@@ -0,0 +1,385 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	RegisterGame("qubic", func() Game { return NewQubic() })
+}
+
+const qubicSize = 4
+
+// qubicLines lists all 76 winning lines of the 4x4x4 cube as (layer, row, col) cells
+var qubicLines = func() [][qubicSize][3]int {
+	var lines [][qubicSize][3]int
+	for dl := -1; dl <= 1; dl++ {
+		for dr := -1; dr <= 1; dr++ {
+			for dc := -1; dc <= 1; dc++ {
+				// Keep one of each pair of opposite directions
+				if first := firstNonZero(dl, dr, dc); first <= 0 {
+					continue
+				}
+				for l := 0; l < qubicSize; l++ {
+					for r := 0; r < qubicSize; r++ {
+						for c := 0; c < qubicSize; c++ {
+							last := [3]int{l + 3*dl, r + 3*dr, c + 3*dc}
+							if !inCube(last) {
+								continue
+							}
+							var line [qubicSize][3]int
+							for i := range line {
+								line[i] = [3]int{l + i*dl, r + i*dr, c + i*dc}
+							}
+							lines = append(lines, line)
+						}
+					}
+				}
+			}
+		}
+	}
+	return lines
+}()
+
+func firstNonZero(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+func inCube(cell [3]int) bool {
+	for _, v := range cell {
+		if v < 0 || v >= qubicSize {
+			return false
+		}
+	}
+	return true
+}
+
+// Qubic is 3D tic-tac-toe on a 4x4x4 cube, won by four in a row along any of
+// its 76 lines. Moves are three-digit coordinates LRC (layer, row, column,
+// each 1-4), so 231 is layer 2, row 3, column 1.
+type Qubic struct {
+	Cells [qubicSize][qubicSize][qubicSize]string
+}
+
+// NewQubic creates a game on an empty cube
+func NewQubic() *Qubic {
+	q := &Qubic{}
+	for l := range q.Cells {
+		for r := range q.Cells[l] {
+			for c := range q.Cells[l][r] {
+				q.Cells[l][r][c] = Empty
+			}
+		}
+	}
+	return q
+}
+
+// qubicPosition encodes 0-based cell indexes as an LRC coordinate
+func qubicPosition(l, r, c int) int {
+	return (l+1)*100 + (r+1)*10 + c + 1
+}
+
+// qubicCell decodes an LRC coordinate, reporting whether it is on the cube
+func qubicCell(position int) (l, r, c int, ok bool) {
+	l, r, c = position/100-1, position/10%10-1, position%10-1
+	return l, r, c, position >= 100 && position < 1000 && inCube([3]int{l, r, c})
+}
+
+func (q *Qubic) Name() string { return "3D Tic-Tac-Toe (Qubic)" }
+
+func (q *Qubic) Clone() Game {
+	clone := *q
+	return &clone
+}
+
+// Display prints the four layers side by side
+func (q *Qubic) Display(w io.Writer) {
+	fmt.Fprintln(w)
+	for l := 0; l < qubicSize; l++ {
+		fmt.Fprintf(w, "  Layer %d     ", l+1)
+	}
+	fmt.Fprintln(w)
+	for r := 0; r < qubicSize; r++ {
+		for l := 0; l < qubicSize; l++ {
+			fmt.Fprint(w, "  ")
+			for c := 0; c < qubicSize; c++ {
+				cell := q.Cells[l][r][c]
+				if cell == Empty {
+					cell = "."
+				}
+				fmt.Fprint(w, cell+" ")
+			}
+			fmt.Fprint(w, "    ")
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// CellGrid lays out the layers side by side, as Display prints them
+func (q *Qubic) CellGrid() [][]string {
+	grid := make([][]string, qubicSize)
+	for r := range grid {
+		for l := 0; l < qubicSize; l++ {
+			if l > 0 {
+				grid[r] = append(grid[r], "")
+			}
+			grid[r] = append(grid[r], q.Cells[l][r][:]...)
+		}
+	}
+	return grid
+}
+
+// DisplayColor prints the layers with the last move and any four in a row
+// highlighted
+func (q *Qubic) DisplayColor(w io.Writer, last int) {
+	winning := map[[3]int]bool{}
+	if line, ok := q.line(); ok {
+		for _, cell := range line {
+			winning[cell] = true
+		}
+	}
+	lastL, lastR, lastC, played := qubicCell(last)
+	fmt.Fprintln(w)
+	for l := 0; l < qubicSize; l++ {
+		fmt.Fprintf(w, "  Layer %d     ", l+1)
+	}
+	fmt.Fprintln(w)
+	for r := 0; r < qubicSize; r++ {
+		for l := 0; l < qubicSize; l++ {
+			fmt.Fprint(w, "  ")
+			for c := 0; c < qubicSize; c++ {
+				mark := q.Cells[l][r][c]
+				cell := mark
+				if cell == Empty {
+					cell = "."
+				}
+				isLast := played && l == lastL && r == lastR && c == lastC
+				fmt.Fprint(w, colorCell(cell, mark, isLast, winning[[3]int{l, r, c}])+" ")
+			}
+			fmt.Fprint(w, "    ")
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+func (q *Qubic) Legal() []int {
+	var positions []int
+	for l := range q.Cells {
+		for r := range q.Cells[l] {
+			for c := range q.Cells[l][r] {
+				if q.Cells[l][r][c] == Empty {
+					positions = append(positions, qubicPosition(l, r, c))
+				}
+			}
+		}
+	}
+	return positions
+}
+
+func (q *Qubic) Play(player string, position int) bool {
+	l, r, c, ok := qubicCell(position)
+	if !ok || q.Cells[l][r][c] != Empty {
+		return false
+	}
+	q.Cells[l][r][c] = player
+	return true
+}
+
+func (q *Qubic) Winner() string {
+	if line, ok := q.line(); ok {
+		return q.Cells[line[0][0]][line[0][1]][line[0][2]]
+	}
+	if len(q.Legal()) == 0 {
+		return "draw"
+	}
+	return ""
+}
+
+// line returns a four in a row in the cube, if there is one
+func (q *Qubic) line() ([qubicSize][3]int, bool) {
+	for _, line := range qubicLines {
+		first := q.Cells[line[0][0]][line[0][1]][line[0][2]]
+		if first == Empty {
+			continue
+		}
+		won := true
+		for _, cell := range line[1:] {
+			if q.Cells[cell[0]][cell[1]][cell[2]] != first {
+				won = false
+				break
+			}
+		}
+		if won {
+			return line, true
+		}
+	}
+	return [qubicSize][3]int{}, false
+}
+
+func (q *Qubic) Describe(position int) string {
+	l, r, c, ok := qubicCell(position)
+	if !ok {
+		return fmt.Sprintf("position %d", position)
+	}
+	return fmt.Sprintf("%d (layer %d, row %d, column %d)", position, l+1, r+1, c+1)
+}
+
+func (q *Qubic) Rules(player string) string {
+	return fmt.Sprintf("You are playing 3D Tic-Tac-Toe (Qubic) as player %s on a 4x4x4 cube of four stacked 4x4 layers. "+
+		"Four in a row along any straight line wins, including lines through the layers and the cube's diagonals. "+
+		"Cells are three-digit coordinates LRC: layer, row, and column, each 1-4. Each turn, respond with a single coordinate such as 231.", player)
+}
+
+func (q *Qubic) Context(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	prompt.WriteString(fmt.Sprintf("You are playing 3D Tic-Tac-Toe (Qubic) as player %s.\n", player))
+	prompt.WriteString("The board is a 4x4x4 cube made of four stacked 4x4 layers. Get four in a row along any straight line to win: " +
+		"within a layer, straight down through the layers, or diagonally across layers and through the cube.\n")
+	prompt.WriteString("Cells are three-digit coordinates LRC (layer, row, column, each 1-4): 231 is layer 2, row 3, column 1.\n\n")
+
+	if len(moveHistory) > 0 {
+		prompt.WriteString("Move history:\n")
+		for i, move := range moveHistory {
+			prompt.WriteString(fmt.Sprintf("%d. Player %s played %s\n", i+1, move.Player, q.Describe(move.Position)))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("Current board, one layer at a time (empty cells show their coordinate):\n")
+	for l := range q.Cells {
+		prompt.WriteString(fmt.Sprintf("Layer %d:\n", l+1))
+		for r := range q.Cells[l] {
+			for c, cell := range q.Cells[l][r] {
+				if cell == Empty {
+					prompt.WriteString(fmt.Sprintf(" %d", qubicPosition(l, r, c)))
+				} else {
+					prompt.WriteString(fmt.Sprintf("  %s ", cell))
+				}
+			}
+			prompt.WriteString("\n")
+		}
+	}
+
+	prompt.WriteString("\n✅ AVAILABLE POSITIONS (CHOOSE ONE OF THESE): ")
+	prompt.WriteString(joinPositions(q.Legal()))
+	prompt.WriteString("\n")
+
+	if promptHints != HintsNone {
+		winningMoves := ImmediateWins(q, player)
+		blockingMoves := ImmediateWins(q, opponent)
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		if len(winningMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("🎯 YOU CAN WIN NOW! Play %s to get four in a row!\n", q.Describe(winningMoves[0])))
+		} else if len(blockingMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("⚠️  DANGER! %s can win with %s! You MUST BLOCK IT!\n", opponent, q.Describe(blockingMoves[0])))
+			if len(blockingMoves) > 1 {
+				prompt.WriteString(fmt.Sprintf("%s threatens several wins at once: %v\n", opponent, blockingMoves))
+			}
+		} else {
+			prompt.WriteString("No immediate wins or threats detected. Play strategically.\n")
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. WIN: Complete four in a row immediately\n")
+		prompt.WriteString(fmt.Sprintf("2. BLOCK: Stop %s from completing four in a row\n", opponent))
+		prompt.WriteString("3. STRATEGIC: Otherwise, prefer the 8 corners (111, 114, 141, 144, 411, 414, 441, 444) and the 8 center cells " +
+			"(222, 223, 232, 233, 322, 323, 332, 333), which each lie on 7 lines, and build two threats at once\n")
+	}
+
+	return prompt.String()
+}
+
+func (q *Qubic) Prompt(player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	prompt.WriteString(q.Context(player, moveHistory))
+
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	prompt.WriteString("1. You MUST choose ONLY from the AVAILABLE POSITIONS list above\n")
+	prompt.WriteString("2. ONLY respond with ONE three-digit coordinate LRC (layer, row, column), e.g. 231\n")
+	prompt.WriteString("3. Do NOT include any other text, explanation, or formatting\n")
+
+	return prompt.String()
+}
+
+var (
+	qubicNamedCoordinates = regexp.MustCompile(`(?i)layer\D*([1-4])\D*?row\D*([1-4])\D*?col\w*\D*([1-4])`)
+	qubicCoordinates      = regexp.MustCompile(`([1-4])[\s,;/()\-]{0,3}([1-4])[\s,;/()\-]{0,3}([1-4])`)
+)
+
+// ParseMove accepts a coordinate written as 231, "2,3,1", "(2, 3, 1)", or
+// "layer 2, row 3, column 1"
+func (q *Qubic) ParseMove(response string) (int, error) {
+	match := qubicNamedCoordinates.FindStringSubmatch(response)
+	if match == nil {
+		match = qubicCoordinates.FindStringSubmatch(response)
+	}
+	if match == nil {
+		return -1, fmt.Errorf("no valid coordinate found in response: %s", strings.TrimSpace(response))
+	}
+	return int(match[1][0]-'0')*100 + int(match[2][0]-'0')*10 + int(match[3][0]-'0'), nil
+}
+
+func (q *Qubic) HumanHint() string {
+	return "layer row column, e.g. 2 3 1"
+}
+
+func (q *Qubic) ParseHumanMove(input string) (int, error) {
+	return q.ParseMove(input)
+}
+
+// SearchDepth keeps minimax to two moves ahead, since each move has up to 64 replies
+func (q *Qubic) SearchDepth() int {
+	return 2
+}
+
+// Evaluate scores an unfinished position for player by its open lines, each
+// worth more the more of player's pieces it holds
+func (q *Qubic) Evaluate(player string) int {
+	weights := [qubicSize]int{0, 1, 4, 16}
+	score := 0
+	for _, line := range qubicLines {
+		mine, theirs := 0, 0
+		for _, cell := range line {
+			switch q.Cells[cell[0]][cell[1]][cell[2]] {
+			case player:
+				mine++
+			case Empty:
+			default:
+				theirs++
+			}
+		}
+		if theirs == 0 {
+			score += weights[mine]
+		} else if mine == 0 {
+			score -= weights[theirs]
+		}
+	}
+	return score
+}
+
+// LoadPosition reads the 64 cells layer by layer, each row by row
+func (q *Qubic) LoadPosition(board string) (string, error) {
+	cells, err := parseCells(board, qubicSize*qubicSize*qubicSize)
+	if err != nil {
+		return "", err
+	}
+	for i, cell := range cells {
+		q.Cells[i/(qubicSize*qubicSize)][i/qubicSize%qubicSize][i%qubicSize] = cell
+	}
+	return playerToMove(cells)
+}
@@ -0,0 +1,17 @@
+package main
+
+import "math/rand/v2"
+
+// RandomAgent picks a uniformly random legal move, as a chance-level baseline
+type RandomAgent struct{}
+
+// Name identifies the random agent
+func (r *RandomAgent) Name() string {
+	return "random"
+}
+
+// ChooseMove returns a random legal move
+func (r *RandomAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	available := game.Legal()
+	return MoveResult{Position: available[rand.IntN(len(available))]}, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReflectAgent asks the LLM to propose a move with reasoning, then asks it
+// again to check that proposal and revise it if needed
+type ReflectAgent struct {
+	LLM *LLMAgent
+}
+
+// Name identifies the model and the reflection mode
+func (r *ReflectAgent) Name() string {
+	return r.LLM.Name() + " (reflect)"
+}
+
+// ChooseMove runs the propose and critique passes and returns the final move
+func (r *ReflectAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	gameContext := game.Context(player, moveHistory) + r.LLM.clockNote(player)
+	result := MoveResult{Position: -1}
+
+	proposalPrompt := gameContext + "\nPROPOSE A MOVE:\n" +
+		"Explain your reasoning in a few sentences, then state your move on the last line as: MOVE: <position>\n"
+	result.Prompt = proposalPrompt
+	proposalResp, duration, err := r.LLM.Complete(proposalPrompt)
+	proposal := proposalResp.Text
+	result.Duration += duration
+	result.Usage.Add(proposalResp.Usage)
+	if err != nil {
+		return result, fmt.Errorf("error calling LLM: %w", err)
+	}
+	var transcript strings.Builder
+	transcript.WriteString("--- Proposal ---\n")
+	transcript.WriteString(strings.TrimSpace(proposal) + "\n")
+
+	proposed, err := ParseFinalMove(game, proposal)
+	proposedText := "no parseable move"
+	if err == nil {
+		proposedText = game.Describe(proposed)
+	}
+
+	critiquePrompt := gameContext + fmt.Sprintf("\nREVIEW THIS PROPOSAL:\nA player suggested %s with this reasoning:\n%s\n\n", proposedText, strings.TrimSpace(proposal)) +
+		"Check the proposal:\n" +
+		fmt.Sprintf("1. Is the move one of the available moves: %v?\n", game.Legal()) +
+		"2. Does it miss an immediate win?\n" +
+		"3. Does it fail to block an opponent's immediate win?\n" +
+		"If the proposal is sound, keep it. Otherwise choose a better position.\n" +
+		"Explain briefly, then state your final move on the last line as: MOVE: <position>\n"
+	critiqueResp, duration, err := r.LLM.Complete(critiquePrompt)
+	critique := critiqueResp.Text
+	result.Duration += duration
+	result.Usage.Add(critiqueResp.Usage)
+	transcript.WriteString("--- Critique prompt ---\n")
+	transcript.WriteString(critiquePrompt)
+	if err != nil {
+		result.Transcript = transcript.String()
+		return result, fmt.Errorf("error calling LLM: %w", err)
+	}
+	transcript.WriteString("--- Critique ---\n")
+	transcript.WriteString(strings.TrimSpace(critique) + "\n")
+	result.Transcript = transcript.String()
+
+	position, err := ParseFinalMove(game, critique)
+	if err != nil {
+		return result, fmt.Errorf("error parsing move: %w", err)
+	}
+	result.Position = position
+	if position == proposed {
+		result.Response = fmt.Sprintf("proposed %d, confirmed", proposed)
+	} else {
+		result.Response = fmt.Sprintf("proposed %s, revised to %d", proposedText, position)
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Replay is a game saved by -replays for the replay subcommand: the game's
+// -jsonl record, plus the board as printed before the first move and after
+// every move, so it can be stepped through without the game's rules
+type Replay struct {
+	GameRecord
+	Start string       `json:"start"`
+	Steps []ReplayStep `json:"steps"` // one per move played
+}
+
+// ReplayStep is one move of a replay
+type ReplayStep struct {
+	Player string `json:"player"`
+	Move   string `json:"move"`  // described, e.g. "position 4"
+	Board  string `json:"board"` // after the move
+	// Answers are the player's answers for this move, the last of which was
+	// played; random opening moves have none
+	Answers []RecordAttempt `json:"answers,omitempty"`
+}
+
+// ReplayWriter saves every finished game to its own replay file in Dir,
+// game-0001.json for the run's first game and so on
+type ReplayWriter struct {
+	Dir     string
+	NewGame func() Game // creates the game the moves were played in
+	games   int
+}
+
+// NewReplayWriter saves replays in dir, creating it if needed
+func NewReplayWriter(dir string, newGame func() Game) (*ReplayWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &ReplayWriter{Dir: dir, NewGame: newGame}, nil
+}
+
+// Record saves a finished game; sides gives each player's backend
+func (r *ReplayWriter) Record(result GameResult, agents map[string]Agent, sides map[string]string) error {
+	r.games++
+	game := r.NewGame()
+	replay := Replay{GameRecord: NewGameRecord(r.games, game.Name(), result, agents, sides), Start: showBoard(game)}
+
+	// Answers that were played match the moves after any random opening
+	played := 0
+	for _, a := range replay.Attempts {
+		if a.Legal {
+			played++
+		}
+	}
+	opened := len(result.Moves) - played
+	answers := replay.Attempts
+	for i, move := range result.Moves {
+		step := ReplayStep{Player: move.Player, Move: game.Describe(move.Position)}
+		if i >= opened {
+			for len(answers) > 0 {
+				step.Answers = append(step.Answers, answers[0])
+				answers = answers[1:]
+				if step.Answers[len(step.Answers)-1].Legal {
+					break
+				}
+			}
+		}
+		game.Play(move.Player, move.Position)
+		step.Board = showBoard(game)
+		replay.Steps = append(replay.Steps, step)
+	}
+	return writeJSONFile(filepath.Join(r.Dir, fmt.Sprintf("game-%04d.json", r.games)), replay)
+}
+
+// LoadReplay reads a replay file
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, fmt.Errorf("can't read replay %s: %w", path, err)
+	}
+	return &replay, nil
+}
+
+// Play prints the replay a move at a time, waiting delay between moves, or
+// for Enter from in if delay is 0; q and Enter stops early
+func (r *Replay) Play(out io.Writer, in io.Reader, delay time.Duration) {
+	keys := bufio.NewReader(in)
+	next := func() bool {
+		if delay > 0 {
+			time.Sleep(delay)
+			return true
+		}
+		fmt.Fprint(out, "Press Enter for the next move, or q to quit: ")
+		line, err := keys.ReadString('\n')
+		return err == nil && strings.TrimSpace(line) != "q"
+	}
+
+	fmt.Fprintf(out, "=== Replay of %s game %d: %s (X) vs %s (O) ===\n", r.Mode, r.Game, r.Players[PlayerX].Model, r.Players[PlayerO].Model)
+	fmt.Fprintf(out, "Played %s, starting player %s\n", r.Finished.Local().Format("2006-01-02 15:04"), r.FirstPlayer)
+	fmt.Fprint(out, r.colored(r.Start))
+	answered := 0
+	for i, step := range r.Steps {
+		if !next() {
+			return
+		}
+		fmt.Fprintf(out, "\n--- Move %d: Player %s (%s) ---\n", i+1, step.Player, r.Players[step.Player].Model)
+		if len(step.Answers) == 0 {
+			fmt.Fprintln(out, "Random opening move")
+		}
+		printAnswers(out, step.Answers)
+		answered += len(step.Answers)
+		fmt.Fprintf(out, "Player %s plays %s\n", step.Player, step.Move)
+		if i < len(r.Moves) && r.Moves[i].Grade != "" && r.Moves[i].Grade != Ungraded.String() {
+			fmt.Fprintf(out, "Grade: %s\n", r.Moves[i].Grade)
+		}
+		fmt.Fprint(out, r.colored(step.Board))
+	}
+	// Answers after the last move are those of a player who couldn't finish
+	if answered < len(r.Attempts) {
+		if !next() {
+			return
+		}
+		player := r.Attempts[answered].Player
+		fmt.Fprintf(out, "\n--- Player %s (%s) ---\n", player, r.Players[player].Model)
+		printAnswers(out, r.Attempts[answered:])
+	}
+	fmt.Fprintf(out, "\nResult: %s after %d moves (%.1fs)\n", describeResult(GameResult{Winner: r.Winner, FailedPlayer: r.FailedPlayer, LostOnTime: r.LostOnTime}), len(r.Steps), r.Seconds)
+}
+
+// colored colors the marks of a board printed by Display when boards are in color
+func (r *Replay) colored(board string) string {
+	if !colorBoards {
+		return board
+	}
+	return colorMarks(board)
+}
+
+// printAnswers prints a turn's answers with their raw responses
+func printAnswers(out io.Writer, answers []RecordAttempt) {
+	for n, a := range answers {
+		fmt.Fprintf(out, "Answer %d/%d (%.2fs)", n+1, len(answers), a.Seconds)
+		switch {
+		case a.Error != "":
+			fmt.Fprintf(out, ": %s", a.Error)
+		case !a.Legal:
+			fmt.Fprint(out, ": illegal move")
+		}
+		fmt.Fprintln(out)
+		if response := strings.TrimSpace(a.Response); response != "" {
+			fmt.Fprintf(out, "  %s\n", strings.ReplaceAll(response, "\n", "\n  "))
+		}
+		if a.Override != "" {
+			fmt.Fprintf(out, "  Engine override: %s\n", a.Override)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// MarkdownReport collects a run's games and writes a Markdown summary of
+// them, for pasting into GitHub issues or blog posts
+type MarkdownReport struct {
+	Path     string
+	Title    string      // e.g. "Tic-Tac-Toe: llama3.2 vs qwen2.5"
+	Settings [][2]string // run settings listed at the top, as name and value
+	Game     Game        // a new game, for the board opening heatmaps are drawn on
+	games    []reportGame
+}
+
+// reportGame is a finished game kept for the notable games section
+type reportGame struct {
+	number int
+	x, o   string
+	result GameResult
+}
+
+// Record keeps a finished game
+func (r *MarkdownReport) Record(result GameResult, agents map[string]Agent) {
+	r.games = append(r.games, reportGame{number: len(r.games) + 1, x: agents[PlayerX].Name(), o: agents[PlayerO].Name(), result: result})
+}
+
+// Write writes the report to its file
+func (r *MarkdownReport) Write(stats *GameStats) error {
+	var md strings.Builder
+	fmt.Fprintf(&md, "# %s\n\n", r.Title)
+	fmt.Fprintf(&md, "_Generated by llama-tac-toe on %s_\n\n", time.Now().Format("2006-01-02 15:04"))
+	for _, setting := range r.Settings {
+		fmt.Fprintf(&md, "- **%s:** %s\n", setting[0], setting[1])
+	}
+
+	md.WriteString("\n## Results\n\n")
+	md.WriteString("| Games | X wins | O wins | Draws | Errors | First mover won |\n")
+	md.WriteString("|---:|---:|---:|---:|---:|---:|\n")
+	fmt.Fprintf(&md, "| %d | %s | %s | %s | %s | %s |\n", stats.Total, percentOf(stats.XWins, stats.Total), percentOf(stats.OWins, stats.Total),
+		percentOf(stats.Draws, stats.Total), percentOf(stats.Errors, stats.Total), percentOf(stats.FirstMoverWins, stats.Total))
+
+	names := playedModels(stats)
+	if len(names) >= 2 {
+		md.WriteString("\n## Models\n\n")
+		md.WriteString("| Model | Games | W-L-D | Score (95% CI) | Moving first | Moving second | Illegal | Unparseable | Avg response | Tokens |\n")
+		md.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+		for _, name := range names {
+			m := stats.Models[name]
+			r := m.MovingFirst
+			r.add(m.MovingSecond)
+			low, high := WilsonInterval(r.Score(), r.Games())
+			fmt.Fprintf(&md, "| %s | %d | %s | %.1f%% (%.1f–%.1f%%) | %s | %s | %.1f%% | %.1f%% | %s | %d |\n",
+				markdownEscape(name), r.Games(), r, r.Score()*100, low*100, high*100, m.MovingFirst, m.MovingSecond,
+				m.IllegalRate()*100, m.ParseFailureRate()*100, formatResponse(m), m.Usage.Total())
+		}
+
+		if len(stats.HeadToHead) > 0 {
+			md.WriteString("\n## Head to head\n\n")
+			md.WriteString("Wins-losses-draws of the row model against the column model, as X / as O.\n\n")
+			md.WriteString("| |")
+			for _, name := range names {
+				fmt.Fprintf(&md, " %s |", markdownEscape(name))
+			}
+			md.WriteString("\n|---|" + strings.Repeat(":---:|", len(names)) + "\n")
+			for _, name := range names {
+				fmt.Fprintf(&md, "| **%s** |", markdownEscape(name))
+				for _, opponent := range names {
+					h, met := stats.HeadToHead[[2]string{name, opponent}]
+					switch {
+					case name == opponent:
+						md.WriteString(" – |")
+					case !met:
+						md.WriteString(" · |")
+					default:
+						fmt.Fprintf(&md, " %s / %s |", h.AsX, h.AsO)
+					}
+				}
+				md.WriteString("\n")
+			}
+		}
+	}
+
+	if grid, ok := r.Game.(Grid); ok && len(openingModels(stats)) > 0 {
+		rows, cols := grid.Dimensions()
+		md.WriteString("\n## Opening moves\n\n")
+		md.WriteString("Where each model played when it opened a game on the empty board, as a share of those games.\n")
+		for _, name := range openingModels(stats) {
+			m := stats.Models[name]
+			total := openingTotal(m)
+			fmt.Fprintf(&md, "\n**%s** (%d games)\n\n", markdownEscape(name), total)
+			md.WriteString("|" + strings.Repeat(" |", cols) + "\n|" + strings.Repeat(":---:|", cols) + "\n")
+			for row := 0; row < rows; row++ {
+				md.WriteString("|")
+				for col := 0; col < cols; col++ {
+					share := rate(m.Openings[row*cols+col], total)
+					fmt.Fprintf(&md, " %s %.0f%% |", heatSquare(share), share*100)
+				}
+				md.WriteString("\n")
+			}
+		}
+	}
+
+	if notable := r.notableGames(); len(notable) > 0 {
+		md.WriteString("\n## Notable games\n\n")
+		md.WriteString("| | Game | X | O | Result | Moves | Rejected answers |\n")
+		md.WriteString("|---|---:|---|---|---|---|---:|\n")
+		for _, n := range notable {
+			g := n.game
+			moves := make([]string, len(g.result.Moves))
+			for i, move := range g.result.Moves {
+				moves[i] = fmt.Sprint(move.Position)
+			}
+			fmt.Fprintf(&md, "| %s | %d | %s | %s | %s | %s | %d |\n", n.label, g.number, markdownEscape(g.x), markdownEscape(g.o),
+				describeResult(g.result), strings.Join(moves, " "), g.result.IllegalMoves+g.result.ParseFailures)
+		}
+	}
+
+	return os.WriteFile(r.Path, []byte(md.String()), 0o644)
+}
+
+// notableGame is a game picked out for the report, with why
+type notableGame struct {
+	label string
+	game  reportGame
+}
+
+// notableGames picks the quickest win, the longest game, and the game with
+// the most rejected answers
+func (r *MarkdownReport) notableGames() []notableGame {
+	var notable []notableGame
+	var quickest, longest, sloppiest *reportGame
+	for i := range r.games {
+		g := &r.games[i]
+		if (g.result.Winner == PlayerX || g.result.Winner == PlayerO) && (quickest == nil || len(g.result.Moves) < len(quickest.result.Moves)) {
+			quickest = g
+		}
+		if longest == nil || len(g.result.Moves) > len(longest.result.Moves) {
+			longest = g
+		}
+		rejected := g.result.IllegalMoves + g.result.ParseFailures
+		if rejected > 0 && (sloppiest == nil || rejected > sloppiest.result.IllegalMoves+sloppiest.result.ParseFailures) {
+			sloppiest = g
+		}
+	}
+	if quickest != nil {
+		notable = append(notable, notableGame{"Quickest win", *quickest})
+	}
+	if longest != nil && longest != quickest {
+		notable = append(notable, notableGame{"Longest game", *longest})
+	}
+	if sloppiest != nil {
+		notable = append(notable, notableGame{"Most rejected answers", *sloppiest})
+	}
+	return notable
+}
+
+// describeResult names how a game ended, e.g. "X wins" or "O forfeits"
+func describeResult(result GameResult) string {
+	switch result.Winner {
+	case "draw":
+		return "Draw"
+	case "error":
+		return result.FailedPlayer + " forfeits"
+	}
+	if result.LostOnTime != "" {
+		return result.Winner + " wins on time"
+	}
+	return result.Winner + " wins"
+}
+
+func percentOf(count, total int) string {
+	return fmt.Sprintf("%d (%.1f%%)", count, rate(count, total)*100)
+}
+
+func formatResponse(m *ModelStats) string {
+	if m.ResponseCount == 0 {
+		return "–"
+	}
+	return fmt.Sprintf("%.2fs", m.AverageResponse().Seconds())
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`)
+
+// markdownEscape keeps model names from breaking tables or turning into emphasis
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
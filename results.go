@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// resultsHeader names the columns of the -out CSV file
+var resultsHeader = []string{
+	"game", "mode", "x_model", "o_model", "first_player", "winner", "winner_model",
+	"moves", "move_count", "duration_s", "retries", "illegal_moves", "parse_failures", "prompt_tokens", "completion_tokens",
+}
+
+// ResultsCSV writes one row per finished game to a CSV file, flushing after
+// every game so an interrupted run keeps the rows it has
+type ResultsCSV struct {
+	Path  string
+	Mode  string // name of the game being played, e.g. "Connect Four"
+	file  *os.File
+	w     *csv.Writer
+	games int
+}
+
+// CreateResultsCSV creates or truncates the CSV file at path and writes its header
+func CreateResultsCSV(path, mode string) (*ResultsCSV, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &ResultsCSV{Path: path, Mode: mode, file: file, w: csv.NewWriter(file)}
+	if err := r.write(resultsHeader); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Record writes a finished game
+func (r *ResultsCSV) Record(result GameResult, agents map[string]Agent) error {
+	r.games++
+	moves := make([]string, len(result.Moves))
+	for i, move := range result.Moves {
+		moves[i] = strconv.Itoa(move.Position)
+	}
+	winnerModel := ""
+	if agent, ok := agents[result.Winner]; ok {
+		winnerModel = agent.Name()
+	}
+	return r.write([]string{
+		strconv.Itoa(r.games), r.Mode, agents[PlayerX].Name(), agents[PlayerO].Name(), result.FirstPlayer, result.Winner, winnerModel,
+		strings.Join(moves, " "), strconv.Itoa(len(result.Moves)), fmt.Sprintf("%.2f", result.Duration.Seconds()),
+		strconv.Itoa(result.Retries), strconv.Itoa(result.IllegalMoves), strconv.Itoa(result.ParseFailures),
+		strconv.Itoa(result.Usage.PromptTokens), strconv.Itoa(result.Usage.CompletionTokens),
+	})
+}
+
+func (r *ResultsCSV) write(row []string) error {
+	r.w.Write(row)
+	r.w.Flush()
+	return r.w.Error()
+}
+
+// Close closes the file
+func (r *ResultsCSV) Close() error {
+	return r.file.Close()
+}
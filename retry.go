@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const maxBackoff = 30 * time.Second
+
+// RetryPolicy controls how transport and HTTP-level failures are retried.
+// These are retried separately from bad answers, which use the -retries budget.
+type RetryPolicy struct {
+	MaxRetries int           // retries after the first attempt; 0 disables retrying
+	BaseDelay  time.Duration // delay before the first retry, doubled for each one after
+}
+
+// IsRetryable reports whether err is a failure worth retrying: the server was
+// unreachable, rate limited the request, or returned a server error
+func IsRetryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests ||
+			httpErr.StatusCode == http.StatusRequestTimeout ||
+			httpErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// Delay returns the jittered exponential backoff before the given retry
+// (starting at 1), honoring any Retry-After the server sent with err
+func (p RetryPolicy) Delay(retry int, err error) time.Duration {
+	delay := p.BaseDelay << (retry - 1)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	// Equal jitter: keep half the delay and randomize the rest so that
+	// concurrent clients don't retry in lockstep
+	delay = delay/2 + rand.N(delay/2+1)
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > delay {
+		delay = httpErr.RetryAfter
+	}
+	return delay
+}
@@ -0,0 +1,92 @@
+package main
+
+import "fmt"
+
+// Series is a best-of-N match between the X and O agents. A win is worth a
+// point and a draw half a point to each side; a game a player couldn't finish
+// is forfeited to the other. The match ends as soon as one side can't be
+// caught.
+type Series struct {
+	Length int
+	Games  int
+	Points map[string]float64 // by player, X or O
+}
+
+// NewSeries starts a best-of-length match
+func NewSeries(length int) *Series {
+	return &Series{Length: length, Points: map[string]float64{PlayerX: 0, PlayerO: 0}}
+}
+
+// Record adds a finished game to the match
+func (s *Series) Record(result GameResult) {
+	s.Games++
+	switch result.Winner {
+	case PlayerX, PlayerO:
+		s.Points[result.Winner]++
+	case "draw":
+		s.Points[PlayerX] += 0.5
+		s.Points[PlayerO] += 0.5
+	case "error":
+		s.Points[OtherPlayer(result.FailedPlayer)]++
+	}
+}
+
+// Decided reports whether the match is over
+func (s *Series) Decided() bool {
+	left := float64(s.Length - s.Games)
+	gap := s.Points[PlayerX] - s.Points[PlayerO]
+	return left <= 0 || gap > left || -gap > left
+}
+
+// Winner returns "X" or "O" for the side ahead on points, or "draw"
+func (s *Series) Winner() string {
+	switch {
+	case s.Points[PlayerX] > s.Points[PlayerO]:
+		return PlayerX
+	case s.Points[PlayerO] > s.Points[PlayerX]:
+		return PlayerO
+	}
+	return "draw"
+}
+
+// Score shows the points, e.g. "4-2" or "3½-2½"
+func (s *Series) Score() string {
+	return formatPoints(s.Points[PlayerX]) + "-" + formatPoints(s.Points[PlayerO])
+}
+
+func formatPoints(points float64) string {
+	whole := int(points)
+	if points == float64(whole) {
+		return fmt.Sprint(whole)
+	}
+	if whole == 0 {
+		return "½"
+	}
+	return fmt.Sprintf("%d½", whole)
+}
+
+// MatchStats counts the results of finished matches
+type MatchStats struct {
+	XWins  int
+	OWins  int
+	Draws  int
+	Scores []string // final score of each match, in order
+}
+
+// Record adds a decided match
+func (m *MatchStats) Record(s *Series) {
+	switch s.Winner() {
+	case PlayerX:
+		m.XWins++
+	case PlayerO:
+		m.OWins++
+	default:
+		m.Draws++
+	}
+	m.Scores = append(m.Scores, s.Score())
+}
+
+// Total is the number of matches played
+func (m *MatchStats) Total() int {
+	return m.XWins + m.OWins + m.Draws
+}
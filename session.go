@@ -0,0 +1,803 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// session is what the subcommands that play share: the game, player X's and
+// player O's backends and LLMs, and the recorders every finished game goes
+// to. Each subcommand's run function validates its own flags, then builds a
+// session and picks the players from it.
+type session struct {
+	f    *cliFlags
+	name string // the subcommand
+
+	newGame func() Game
+	opening Opening
+	events  *JSONEvents // with -output json
+
+	backendForX, backendForO string
+	urlForX, urlForO         string
+	throttle                 *Throttle
+	breakers                 []*CircuitBreaker
+	llmX, llmO               *LLMAgent
+	playerX, playerO         Agent // the LLMs as -agent plays them
+	agentOpts                AgentOptions
+	clock                    *Clock
+
+	stats     *GameStats
+	backends  map[string]string // each side's backend, for the recorders
+	out       *os.File          // where everything for people goes
+	gameOut   io.Writer         // where games print their moves
+	bar       *ProgressBar
+	dashboard *Dashboard
+
+	ratings     *Ratings
+	glicko      *GlickoRatings
+	leaderboard *Leaderboard
+	resultsCSV  *ResultsCSV
+	gameLog     *GameLog
+	webhooks    *Webhooks
+	slack       *Slack
+	transcripts *TranscriptWriter
+	images      *BoardImages
+	gifs        *GameGIFs
+	replays     *ReplayWriter
+	metrics     *Metrics
+	report      *MarkdownReport
+}
+
+// newSession sets up the game and both players' LLMs from the flags, exiting
+// with the problem if any of them are invalid
+func newSession(f *cliFlags, name string) *session {
+	s := &session{f: f, name: name, stats: NewGameStats(), out: os.Stdout}
+	// With -output json, stdout carries only the events and everything
+	// written for people goes to stderr
+	switch *f.outputFormat {
+	case "text":
+	case "json":
+		s.events = NewJSONEvents(os.Stdout, "")
+		s.out = os.Stderr
+		LogTo(s.out)
+	default:
+		fmt.Fprintf(s.out, "Invalid -output value %q: must be text or json\n", *f.outputFormat)
+		os.Exit(2)
+	}
+	colorBoards = !*f.noColor && os.Getenv("NO_COLOR") == "" && isTerminal(s.out)
+	s.setupGame()
+	s.setupPlayers()
+
+	// Games print their moves to gameOut, unless -q leaves them out
+	s.gameOut = io.Writer(s.out)
+	if *f.quiet {
+		s.gameOut = io.Discard
+	}
+	return s
+}
+
+// setupGame builds newGame from -game and the flags that change its start
+func (s *session) setupGame() {
+	f := s.f
+	newGame, err := NewGame(*f.gameName, *f.variant)
+	if err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(2)
+	}
+	if *f.boards != 1 {
+		if _, ok := newGame().(*Notakto); !ok {
+			fmt.Fprintln(s.out, "-boards only applies to -variant notakto")
+			os.Exit(2)
+		}
+		if *f.boards < 1 || *f.boards > maxNotaktoBoards {
+			fmt.Fprintf(s.out, "Invalid -boards value %d: must be 1-%d\n", *f.boards, maxNotaktoBoards)
+			os.Exit(2)
+		}
+		count := *f.boards
+		newGame = func() Game { return NewNotakto(count) }
+	}
+	if *f.startPosition != "" {
+		loader, ok := newGame().(PositionLoader)
+		if !ok {
+			fmt.Fprintf(s.out, "%s doesn't support -start-position\n", newGame().Name())
+			os.Exit(2)
+		}
+		s.opening.ToMove, err = loader.LoadPosition(*f.startPosition)
+		if err == nil && loader.(Game).Winner() != "" {
+			err = fmt.Errorf("start position is already finished")
+		}
+		if err != nil {
+			fmt.Fprintf(s.out, "Invalid -start-position: %v\n", err)
+			os.Exit(2)
+		}
+		startGame, board := newGame, *f.startPosition
+		newGame = func() Game {
+			game := startGame()
+			game.(PositionLoader).LoadPosition(board)
+			return game
+		}
+	}
+	if *f.randomStart < 0 {
+		fmt.Fprintf(s.out, "Invalid -random-start value %d: must be 0 or more\n", *f.randomStart)
+		os.Exit(2)
+	}
+	s.opening.RandomMoves = *f.randomStart
+
+	if countTrue(*f.tools, *f.jsonMoves, *f.grammar, *f.logitBias, *f.cot) > 1 {
+		fmt.Fprintln(s.out, "Only one of -tools, -json, -grammar, -logit-bias, and -cot can be used at a time")
+		os.Exit(2)
+	}
+	if _, ok := ChoosesMark(newGame()); ok && (*f.grammar || *f.logitBias) {
+		fmt.Fprintln(s.out, "-grammar and -logit-bias can't express a choice of mark; use -tools, -json, or plain text answers")
+		os.Exit(2)
+	}
+	if text, ok := newGame().(TextMoves); ok && countTrue(*f.tools, *f.jsonMoves, *f.grammar, *f.logitBias) > 0 {
+		fmt.Fprintf(s.out, "%s moves are %s, which -tools, -json, -grammar, and -logit-bias can't express; use plain text answers\n", newGame().Name(), text.MoveFormat())
+		os.Exit(2)
+	}
+	if *f.swap {
+		if countTrue(*f.tools, *f.jsonMoves, *f.grammar, *f.logitBias) > 0 {
+			fmt.Fprintln(s.out, "-swap needs plain text answers and can't be used with -tools, -json, -grammar, or -logit-bias")
+			os.Exit(2)
+		}
+		plainGame := newGame
+		newGame = func() Game { return NewSwapGame(plainGame()) }
+	}
+	s.newGame = newGame
+}
+
+// setupPlayers connects both players' backends and sets up their LLMs with
+// the prompt and answer settings
+func (s *session) setupPlayers() {
+	f := s.f
+	httpConfig := HTTPClientConfig{Proxy: *f.proxy, CACert: *f.caCert, InsecureTLS: *f.insecureTLS, MaxIdlePerHost: *f.maxIdleConns}
+	if err := ConfigureHTTPClient(httpConfig); err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(2)
+	}
+
+	s.backendForX = orDefault(*f.backendNameX, *f.backendName)
+	s.backendForO = orDefault(*f.backendNameO, *f.backendName)
+	if *f.grammar && s.backendForX != "llamacpp" && s.backendForO != "llamacpp" {
+		fmt.Fprintln(s.out, "-grammar is a llama.cpp feature and needs the llamacpp backend for player X or O")
+		os.Exit(2)
+	}
+	s.urlForX = orDefault(*f.urlX, *f.ollamaURL)
+	s.urlForO = orDefault(*f.urlO, *f.ollamaURL)
+	if !flagWasSet("url") {
+		// Each backend has its own well-known endpoint
+		s.urlForX = orDefault(*f.urlX, DefaultBackendURL(s.backendForX))
+		s.urlForO = orDefault(*f.urlO, DefaultBackendURL(s.backendForO))
+	}
+	s.backends = map[string]string{PlayerX: s.backendForX, PlayerO: s.backendForO}
+	backendConfig := BackendConfig{
+		AzureDeployment: *f.azureDeployment,
+		AzureAPIVersion: *f.azureAPIVersion,
+		AWSRegion:       *f.awsRegion,
+		Headers:         f.headers,
+		KeepAlive:       *f.keepAlive,
+		Balance:         *f.balance,
+	}
+	backendConfig.URL = s.urlForX
+	backendConfig.APIKey = orDefault(*f.apiKeyX, *f.apiKey)
+	backendX, err := NewBackend(s.backendForX, backendConfig)
+	if err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(2)
+	}
+	backendConfig.URL = s.urlForO
+	backendConfig.APIKey = orDefault(*f.apiKeyO, *f.apiKey)
+	backendO, err := NewBackend(s.backendForO, backendConfig)
+	if err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(2)
+	}
+	s.throttle = &Throttle{
+		RequestsPerMinute: *f.rpm,
+		TokensPerMinute:   *f.tpm,
+		InputPrice:        *f.priceIn,
+		OutputPrice:       *f.priceOut,
+		MaxCost:           *f.maxCost,
+	}
+	throttled := s.throttle.Describe() != "" || *f.priceIn > 0 || *f.priceOut > 0
+	if throttled {
+		backendX = &ThrottledBackend{Backend: backendX, Throttle: s.throttle}
+		backendO = &ThrottledBackend{Backend: backendO, Throttle: s.throttle}
+	}
+	if *f.maxCost > 0 && *f.priceIn == 0 && *f.priceOut == 0 {
+		fmt.Fprintln(s.out, "-max-cost needs -price-in and/or -price-out to estimate spend")
+		os.Exit(2)
+	}
+
+	if *f.breakerThreshold > 0 {
+		var fallback Backend
+		if *f.fallbackModel != "" || *f.fallbackURL != "" || *f.fallbackBackend != "" {
+			fallbackName := orDefault(*f.fallbackBackend, s.backendForX)
+			// Player X's URL and key are only good for the fallback on the same
+			// provider; another has its own endpoint and environment variable
+			backendConfig.URL = orDefault(*f.fallbackURL, DefaultBackendURL(fallbackName))
+			backendConfig.APIKey = *f.fallbackAPIKey
+			if fallbackName == s.backendForX {
+				backendConfig.URL = orDefault(*f.fallbackURL, s.urlForX)
+				backendConfig.APIKey = orDefault(*f.fallbackAPIKey, orDefault(*f.apiKeyX, *f.apiKey))
+			}
+			if fallback, err = NewBackend(fallbackName, backendConfig); err != nil {
+				fmt.Fprintln(s.out, err)
+				os.Exit(2)
+			}
+			// The fallback counts toward the same limits and budget
+			if throttled {
+				fallback = &ThrottledBackend{Backend: fallback, Throttle: s.throttle}
+			}
+		}
+		breakerX := &CircuitBreaker{Backend: backendX, Threshold: *f.breakerThreshold, Fallback: fallback, FallbackModel: *f.fallbackModel}
+		breakerO := &CircuitBreaker{Backend: backendO, Threshold: *f.breakerThreshold, Fallback: fallback, FallbackModel: *f.fallbackModel}
+		s.breakers = append(s.breakers, breakerX, breakerO)
+		backendX, backendO = breakerX, breakerO
+	}
+	llmX := &LLMAgent{Backend: backendX, Model: orDefault(*f.modelX, *f.model), Temperature: *f.temperature, MaxTokens: *f.maxTokens, Chat: *f.chat}
+	llmO := &LLMAgent{Backend: backendO, Model: orDefault(*f.modelO, *f.model), Temperature: *f.temperature, MaxTokens: *f.maxTokens, Chat: *f.chat}
+	for _, llm := range []*LLMAgent{llmX, llmO} {
+		llm.TopP, llm.TopK, llm.Seed = *f.topP, *f.topK, *f.seed
+		llm.Retry = RetryPolicy{MaxRetries: *f.httpRetries, BaseDelay: *f.httpBackoff}
+		llm.Timeout, llm.MoveTimeout = *f.timeout, *f.moveTimeout
+	}
+	if err := f.optionsX.apply(llmX); err != nil {
+		fmt.Fprintf(s.out, "Invalid -options-x: %v\n", err)
+		os.Exit(2)
+	}
+	if err := f.optionsO.apply(llmO); err != nil {
+		fmt.Fprintf(s.out, "Invalid -options-o: %v\n", err)
+		os.Exit(2)
+	}
+	if llmX.System, err = readSystemPrompt(*f.systemX); err != nil {
+		fmt.Fprintf(s.out, "Invalid -system-x: %v\n", err)
+		os.Exit(2)
+	}
+	if llmO.System, err = readSystemPrompt(*f.systemO); err != nil {
+		fmt.Fprintf(s.out, "Invalid -system-o: %v\n", err)
+		os.Exit(2)
+	}
+	if err := applyPersona(llmX, *f.personaX); err != nil {
+		fmt.Fprintf(s.out, "Invalid -persona-x: %v\n", err)
+		os.Exit(2)
+	}
+	if err := applyPersona(llmO, *f.personaO); err != nil {
+		fmt.Fprintf(s.out, "Invalid -persona-o: %v\n", err)
+		os.Exit(2)
+	}
+	if *f.timeControl > 0 {
+		s.clock = NewClock(*f.timeControl, *f.increment)
+		llmX.Clock, llmO.Clock = s.clock, s.clock
+	} else if *f.increment > 0 {
+		fmt.Fprintln(s.out, "-increment needs a -time control")
+		os.Exit(2)
+	}
+	llmX.Tools, llmO.Tools = *f.tools, *f.tools
+	llmX.JSON, llmO.JSON = *f.jsonMoves, *f.jsonMoves
+	llmX.Grammar, llmO.Grammar = *f.grammar, *f.grammar
+	llmX.LogitBias, llmO.LogitBias = *f.logitBias, *f.logitBias
+	llmX.Reason, llmO.Reason = *f.cot, *f.cot
+	if *f.stream {
+		llmX.Stream = s.out
+		llmO.Stream = s.out
+	}
+	switch *f.hints {
+	case HintsFull, HintsThreats, HintsNone:
+		promptHints = *f.hints
+	default:
+		fmt.Fprintf(s.out, "Invalid -hints value %q: must be full, threats, or none\n", *f.hints)
+		os.Exit(2)
+	}
+	switch *f.boardFormat {
+	case BoardGrid, BoardJSON, BoardCoordinates, BoardText:
+		promptBoardFormat = *f.boardFormat
+	default:
+		fmt.Fprintf(s.out, "Invalid -board-format value %q: must be grid, json, coords, or text\n", *f.boardFormat)
+		os.Exit(2)
+	}
+	if promptBoardFormat != BoardGrid {
+		if !hasTicTacToeBoard(s.newGame()) {
+			fmt.Fprintln(s.out, "-board-format only changes how prompts show a tic-tac-toe board, and can't be used with this game")
+			os.Exit(2)
+		}
+		if *f.promptLang != "en" {
+			fmt.Fprintln(s.out, "-board-format's other formats are in English, and can't be used with -prompt-lang")
+			os.Exit(2)
+		}
+	}
+	if *f.promptLang != "en" {
+		if ttt, ok := s.newGame().(*TicTacToe); !ok || !ttt.Standard() {
+			fmt.Fprintln(s.out, "-prompt-lang only translates the standard tic-tac-toe prompt")
+			os.Exit(2)
+		}
+		if *f.chat || *f.conversation != "" || *f.examplesPath != "" || countTrue(*f.tools, *f.jsonMoves, *f.cot) > 0 || *f.agentMode == "reflect" {
+			fmt.Fprintln(s.out, "-prompt-lang translates the prompt for plain text answers, and can't be used with -chat, -conversation, -examples, -tools, -json, -cot, or the reflect agent, which add English of their own")
+			os.Exit(2)
+		}
+	}
+	if err := SetPromptLanguage(*f.promptLang); err != nil {
+		fmt.Fprintf(s.out, "Invalid -prompt-lang: %v\n", err)
+		os.Exit(2)
+	}
+	if *f.promptTemplatePath != "" {
+		if *f.tools || *f.jsonMoves || *f.cot {
+			fmt.Fprintln(s.out, "-prompt-template is the prompt for plain text answers and can't be used with -tools, -json, or -cot")
+			os.Exit(2)
+		}
+		promptTemplate, err := LoadPromptTemplate(*f.promptTemplatePath)
+		if err == nil {
+			// Catch mistakes such as unknown variables before the first move
+			_, err = promptTemplate.Render(s.newGame(), PlayerX, nil)
+		}
+		if err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+		llmX.Template, llmO.Template = promptTemplate, promptTemplate
+	}
+	if *f.examplesPath != "" {
+		examples, err := LoadFewShot(*f.examplesPath, s.newGame)
+		if err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+		llmX.Examples, llmO.Examples = examples, examples
+	}
+	switch side := strings.ToLower(*f.conversation); side {
+	case "":
+	case "x", "o", "both":
+		if *f.chat || *f.promptTemplatePath != "" || *f.tools || *f.jsonMoves || *f.cot || (*f.agentMode != "llm" && *f.agentMode != "hybrid") {
+			fmt.Fprintln(s.out, "-conversation builds its own prompts and can't be used with -chat, -prompt-template, -tools, -json, -cot, or the ensemble and reflect agents")
+			os.Exit(2)
+		}
+		if side != "o" {
+			llmX.Conversations = NewConversations()
+		}
+		if side != "x" {
+			llmO.Conversations = NewConversations()
+		}
+	default:
+		fmt.Fprintf(s.out, "Invalid -conversation value %q: must be x, o, or both\n", *f.conversation)
+		os.Exit(2)
+	}
+
+	s.llmX, s.llmO = llmX, llmO
+	s.agentOpts = AgentOptions{Votes: *f.votes, MCTSSimulations: *f.mctsSimulations, MinimaxDepth: *f.minimaxDepth}
+	if s.playerX, err = NewLLMPlayer(*f.agentMode, llmX, s.agentOpts); err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(2)
+	}
+	s.playerO, _ = NewLLMPlayer(*f.agentMode, llmO, s.agentOpts)
+}
+
+// newEngine returns the engine named, or the LLM given for "llm", exiting if
+// it can't play the game
+func (s *session) newEngine(name string, llm Agent) Agent {
+	if name == "heuristic" {
+		if *s.f.swap {
+			fmt.Fprintln(s.out, "The heuristic opponent can't answer -swap")
+			os.Exit(2)
+		}
+		if ttt, ok := s.newGame().(*TicTacToe); !ok || !ttt.Standard() {
+			fmt.Fprintln(s.out, "The heuristic opponent only plays standard tic-tac-toe")
+			os.Exit(2)
+		}
+	}
+	engine, err := NewOpponent(name, llm, s.agentOpts)
+	if err != nil {
+		fmt.Fprintln(s.out, err)
+		os.Exit(2)
+	}
+	return engine
+}
+
+// entrant is another model on player X's backend with player X's settings,
+// as tournament entrants and the grpc subcommand's players are
+func (s *session) entrant(model string) (*LLMAgent, Agent) {
+	llm := *s.llmX
+	llm.Model = model
+	player, _ := NewLLMPlayer(*s.f.agentMode, &llm, s.agentOpts)
+	return &llm, player
+}
+
+// printBackends prints the backend and URL in use, or each side's if
+// bothSides and they differ
+func (s *session) printBackends(bothSides bool) {
+	if s.backendForX == s.backendForO || !bothSides {
+		fmt.Fprintf(s.out, "Backend: %s\n", s.backendForX)
+	} else {
+		fmt.Fprintf(s.out, "Backends: %s (X), %s (O)\n", s.backendForX, s.backendForO)
+	}
+	if s.urlForX == s.urlForO || !bothSides {
+		fmt.Fprintf(s.out, "API URL: %s\n", orDefault(s.urlForX, "(backend default)"))
+	} else {
+		fmt.Fprintf(s.out, "API URLs: %s (X), %s (O)\n", orDefault(s.urlForX, "(backend default)"), orDefault(s.urlForO, "(backend default)"))
+	}
+}
+
+// printSettings prints the banner lines every subcommand that plays shares,
+// after its own
+func (s *session) printSettings() {
+	f := s.f
+	if *f.agentMode == "ensemble" {
+		fmt.Fprintf(s.out, "Ensemble votes: %d\n", *f.votes)
+	}
+	if s.llmX.Persona != "" || s.llmO.Persona != "" {
+		fmt.Fprintf(s.out, "Personas: %s (X), %s (O)\n", orDefault(s.llmX.Persona, "(none)"), orDefault(s.llmO.Persona, "(none)"))
+	}
+	if *f.systemX != "" || *f.systemO != "" {
+		fmt.Fprintf(s.out, "System prompts: %s (X), %s (O)\n", describeSystemPrompt(*f.systemX), describeSystemPrompt(*f.systemO))
+	}
+	if promptHints != HintsFull {
+		fmt.Fprintf(s.out, "Hints: %s\n", promptHints)
+	}
+	if promptBoardFormat != BoardGrid {
+		fmt.Fprintf(s.out, "Board format: %s\n", promptBoardFormat)
+	}
+	if *f.promptLang != "en" {
+		fmt.Fprintf(s.out, "Prompt language: %s\n", *f.promptLang)
+	}
+	// Puzzles are answered once, without retries
+	if s.name != "puzzle" {
+		fmt.Fprintf(s.out, "Max retries: %d\n", *f.maxRetries)
+	}
+	if limits := s.throttle.Describe(); limits != "" {
+		fmt.Fprintf(s.out, "Limits: %s\n", limits)
+	}
+	fmt.Fprintf(s.out, "Temperature: %.2f\n", *f.temperature)
+	if s.clock != nil {
+		fmt.Fprintf(s.out, "Time control: %s per player + %s per move\n", *f.timeControl, *f.increment)
+	}
+}
+
+// prepare checks the LLMs that will be asked for moves and warms them up, or
+// with -dry-run checks them, prints the settings, and exits. checkedLater
+// says the subcommand's models are only known as they're asked for.
+func (s *session) prepare(active []*LLMAgent, checkedLater bool) {
+	f := s.f
+	if *f.dryRun {
+		fmt.Fprintln(s.out, "\nSettings in effect (given or differing from the defaults):")
+		PrintSettings(s.out)
+		fmt.Fprintln(s.out, "\nChecking backends and models:")
+		if checkedLater {
+			fmt.Fprintf(s.out, "  None: the %s subcommand's models are checked as they're asked for\n", s.name)
+		}
+		problems := 0
+		for _, llm := range active {
+			backendName, url := s.backendForX, s.urlForX
+			if llm == s.llmO {
+				backendName, url = s.backendForO, s.urlForO
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			err := ProbeModel(ctx, llm)
+			cancel()
+			if err != nil {
+				problems++
+				fmt.Fprintf(s.out, "  %s on %s at %s: %v\n", llm.Model, backendName, orDefault(url, "(backend default)"), err)
+			} else {
+				fmt.Fprintf(s.out, "  %s on %s at %s: OK\n", llm.Model, backendName, orDefault(url, "(backend default)"))
+			}
+		}
+		if problems > 0 {
+			fmt.Fprintf(s.out, "\nDry run: %d problem(s) found; nothing was played\n", problems)
+			os.Exit(1)
+		}
+		fmt.Fprintln(s.out, "\nDry run: everything checks out; nothing was played")
+		os.Exit(0)
+	}
+
+	if *f.checkModels || *f.autoPull {
+		for _, llm := range active {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err := CheckModel(ctx, llm.Backend, llm.Model)
+			cancel()
+			var httpErr *HTTPError
+			var missing *MissingModelError
+			puller, canPull := llm.Backend.(ModelPuller)
+			switch {
+			case errors.As(err, &missing) && *f.autoPull && canPull:
+				if err := PullWithProgress(s.out, puller, llm.Model); err != nil {
+					fmt.Fprintf(s.out, "Error pulling %s: %v\n", llm.Model, err)
+					os.Exit(1)
+				}
+			case errors.As(err, &httpErr) || IsRetryable(err):
+				// The server may not support listing models, or may come up
+				// later; the moves themselves will report any real problem
+				slog.Warn(err.Error())
+			case err != nil:
+				fmt.Fprintln(s.out, capitalize(err.Error()))
+				os.Exit(1)
+			}
+		}
+	}
+
+	if *f.warmup {
+		for _, llm := range active {
+			fmt.Fprintf(s.out, "Warming up %s...", llm.Model)
+			duration, err := llm.Warmup()
+			if err != nil {
+				fmt.Fprintf(s.out, " failed: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(s.out, " ready (%.2fs)\n", duration.Seconds())
+		}
+	}
+}
+
+// openRecorders opens everything finished games are recorded to: ratings,
+// the leaderboard, result files, notifications, and metrics
+func (s *session) openRecorders() {
+	f := s.f
+	var err error
+	if *f.eloFile != "" {
+		if *f.eloK <= 0 {
+			fmt.Fprintf(s.out, "Invalid -elo-k value %g: must be positive\n", *f.eloK)
+			os.Exit(2)
+		}
+		if s.ratings, err = LoadRatings(*f.eloFile, s.newGame().Name(), *f.eloK); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.glickoFile != "" {
+		if *f.glickoTau <= 0 {
+			fmt.Fprintf(s.out, "Invalid -glicko-tau value %g: must be positive\n", *f.glickoTau)
+			os.Exit(2)
+		}
+		if s.glicko, err = LoadGlickoRatings(*f.glickoFile, s.newGame().Name(), *f.glickoTau); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.leaderboardFile != "" {
+		if s.leaderboard, err = LoadLeaderboard(*f.leaderboardFile); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+
+	if *f.outFile != "" {
+		if s.resultsCSV, err = CreateResultsCSV(*f.outFile, s.newGame().Name()); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.jsonlFile != "" {
+		if s.gameLog, err = OpenGameLog(*f.jsonlFile, s.newGame().Name()); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.webhookURLs != "" {
+		if s.webhooks, err = NewWebhooks(*f.webhookURLs, s.newGame().Name()); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.slackWebhook != "" || *f.slackChannel != "" {
+		if s.slack, err = NewSlack(*f.slackWebhook, *f.slackChannel, orDefault(*f.slackToken, os.Getenv("SLACK_BOT_TOKEN")), s.newGame); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.transcriptDir != "" {
+		if s.transcripts, err = NewTranscriptWriter(*f.transcriptDir, s.newGame); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.imageDir != "" {
+		if s.images, err = NewBoardImages(*f.imageDir, *f.imageFormat, *f.imageEveryMove, s.newGame); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.gifDir != "" {
+		if s.gifs, err = NewGameGIFs(*f.gifDir, s.newGame); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+	if *f.replayDir != "" {
+		if s.replays, err = NewReplayWriter(*f.replayDir, s.newGame); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+	}
+
+	if *f.metricsAddr != "" {
+		s.metrics = NewMetrics()
+		if err := ServeMetrics(*f.metricsAddr, s.metrics); err != nil {
+			fmt.Fprintln(s.out, err)
+			os.Exit(2)
+		}
+		fmt.Fprintf(s.out, "Serving metrics at http://%s/metrics\n", *f.metricsAddr)
+	}
+}
+
+// close closes the result files
+func (s *session) close() {
+	if s.resultsCSV != nil {
+		s.resultsCSV.Close()
+	}
+	if s.gameLog != nil {
+		s.gameLog.Close()
+	}
+}
+
+// startReport starts the -report, if one was asked for, with the
+// subcommand's title and settings before the ones every run shares
+func (s *session) startReport(title string, settings ...[2]string) {
+	f := s.f
+	if *f.reportFile == "" {
+		return
+	}
+	s.report = &MarkdownReport{Path: *f.reportFile, Game: s.newGame(), Title: title, Settings: settings}
+	backendList := s.backends[PlayerX]
+	if s.backends[PlayerO] != backendList {
+		backendList += ", " + s.backends[PlayerO]
+	}
+	s.report.Settings = append(s.report.Settings,
+		[2]string{"Backend", backendList},
+		[2]string{"Agent", *f.agentMode},
+		[2]string{"Temperature", fmt.Sprintf("%.2f", *f.temperature)},
+		[2]string{"Max retries", fmt.Sprint(*f.maxRetries)},
+	)
+}
+
+// showProgress shows a progress bar toward total games, 0 if unknown, when
+// a quiet run leaves the games out
+func (s *session) showProgress(total int) {
+	if *s.f.quiet && isTerminal(s.out) {
+		s.bar = NewProgressBar(s.out, total)
+	}
+}
+
+// sides returns each side's backend as the recorders show it
+func (s *session) sides(agents map[string]Agent) map[string]string {
+	sides := map[string]string{}
+	for player, agent := range agents {
+		sides[player] = sideBackend(agent, s.backends[player])
+	}
+	return sides
+}
+
+// startEvents sends the -output json start event, with the agents of a run
+// between two players or the names of the entrants of one between several
+func (s *session) startEvents(agents map[string]Agent, names []string) {
+	if s.events == nil {
+		return
+	}
+	s.events.Mode = s.newGame().Name()
+	var sides map[string]string
+	if agents != nil {
+		sides = s.sides(agents)
+	}
+	s.events.Start(*s.f.games, agents, sides, names)
+}
+
+// beforeGame is called as every game starts
+func (s *session) beforeGame(agents map[string]Agent) {
+	if s.slack != nil {
+		s.slack.StartGame(agents)
+	}
+}
+
+// afterGame records every finished game
+func (s *session) afterGame(result GameResult, agents map[string]Agent) {
+	s.stats.RecordGame(result, agents)
+	sides := s.sides(agents)
+	if result.Usage.Total() > 0 {
+		slog.Info(fmt.Sprintf("Tokens this game: %d prompt, %d completion", result.Usage.PromptTokens, result.Usage.CompletionTokens))
+	}
+	if s.ratings != nil {
+		s.ratings.Report(result, agents)
+	}
+	if s.glicko != nil {
+		s.glicko.Report(result, agents)
+	}
+	if s.metrics != nil {
+		s.metrics.Record(result, agents)
+	}
+	if s.report != nil {
+		s.report.Record(result, agents)
+	}
+	if s.dashboard != nil {
+		s.dashboard.Render(result, s.stats)
+	}
+	if s.bar != nil {
+		s.bar.Update(s.stats)
+	}
+	if s.resultsCSV != nil {
+		if err := s.resultsCSV.Record(result, agents); err != nil {
+			slog.Warn("couldn't write the results", "path", s.resultsCSV.Path, "err", err)
+		}
+	}
+	if s.gameLog != nil {
+		if err := s.gameLog.Record(result, agents, sides); err != nil {
+			slog.Warn("couldn't write the game log", "path", s.gameLog.Path, "err", err)
+		}
+	}
+	if s.transcripts != nil {
+		if err := s.transcripts.Record(result, agents, sides); err != nil {
+			slog.Warn("couldn't write the transcript", "dir", s.transcripts.Dir, "err", err)
+		}
+	}
+	if s.images != nil {
+		if err := s.images.Record(result); err != nil {
+			slog.Warn("couldn't save the board images", "dir", s.images.Dir, "err", err)
+		}
+	}
+	if s.gifs != nil {
+		if err := s.gifs.Record(result); err != nil {
+			slog.Warn("couldn't save the GIF", "dir", s.gifs.Dir, "err", err)
+		}
+	}
+	if s.replays != nil {
+		if err := s.replays.Record(result, agents, sides); err != nil {
+			slog.Warn("couldn't save the replay", "dir", s.replays.Dir, "err", err)
+		}
+	}
+	if s.leaderboard != nil {
+		if err := s.leaderboard.Record(s.newGame().Name(), result, agents, sides); err != nil {
+			slog.Warn("couldn't save the leaderboard", "path", s.leaderboard.Path, "err", err)
+		}
+	}
+	if s.events != nil {
+		s.events.Game(result, agents, sides)
+	}
+	if s.webhooks != nil {
+		s.webhooks.Game(result, agents, sides)
+	}
+	if s.slack != nil {
+		s.slack.EndGame(result, agents)
+	}
+}
+
+// stop reports whether the run has to stop: the budget is spent or a backend
+// keeps failing
+func (s *session) stop() bool {
+	if s.throttle.OverBudget() {
+		fmt.Fprintf(s.out, "\nStopping the run: estimated spend $%.4f has reached the -max-cost budget of $%.2f\n", s.throttle.Spent(), *s.f.maxCost)
+		return true
+	}
+	if tripped(s.breakers) {
+		fmt.Fprintln(s.out, "\nStopping the run: a backend keeps failing and no -fallback-model or -fallback-url is configured")
+		return true
+	}
+	return false
+}
+
+// printRatings prints the -elo and -glicko ladders
+func (s *session) printRatings() {
+	if s.ratings != nil {
+		s.ratings.PrintLadder(s.out)
+		fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	}
+	if s.glicko != nil {
+		s.glicko.PrintLadder(s.out)
+		fmt.Fprintln(s.out, strings.Repeat("-", 50))
+	}
+}
+
+// printCost prints the estimated spend, if prices were given
+func (s *session) printCost() {
+	if *s.f.priceIn > 0 || *s.f.priceOut > 0 {
+		fmt.Fprintf(s.out, "Estimated cost:     $%.4f\n", s.throttle.Spent())
+	}
+}
+
+// finish writes the report and sends the summary event once the games are over
+func (s *session) finish() {
+	writeReport(s.out, s.report, s.stats)
+	if s.events != nil {
+		s.events.Summary(s.stats)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// z95 is the standard normal quantile for a two-sided 95% confidence level
+const z95 = 1.959964
+
+// WilsonInterval returns the 95% Wilson score interval for a rate of score
+// over n trials. Draws counted as half a win make the interval a little
+// conservative, which is fine for telling noise from a real difference.
+func WilsonInterval(score float64, n int) (low, high float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	nf := float64(n)
+	center := (score + z95*z95/(2*nf)) / (1 + z95*z95/nf)
+	margin := z95 / (1 + z95*z95/nf) * math.Sqrt(score*(1-score)/nf+z95*z95/(4*nf*nf))
+	return max(center-margin, 0), min(center+margin, 1)
+}
+
+// EvenMatchTest tests a head-to-head record against the two sides being
+// equally strong, returning the two-sided p-value of a z-test of its score
+// against 50%
+func EvenMatchTest(r WLD) (p float64) {
+	n := float64(r.Games())
+	if n == 0 {
+		return 1
+	}
+	z := (r.Score() - 0.5) / math.Sqrt(0.25/n)
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// CompareScores tests whether two records from separate games, such as two
+// models' results against the same field, have different scores, returning
+// the two-sided p-value of a two-proportion z-test
+func CompareScores(a, b WLD) (p float64) {
+	return CompareRates(a.Score(), a.Games(), b.Score(), b.Games())
+}
+
+// CompareRates tests whether rate a over na trials differs from rate b over
+// nb separate trials, returning the two-sided p-value of a two-proportion
+// z-test
+func CompareRates(a float64, na int, b float64, nb int) (p float64) {
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	fa, fb := float64(na), float64(nb)
+	pooled := (a*fa + b*fb) / (fa + fb)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/fa + 1/fb))
+	if se == 0 {
+		return 1
+	}
+	z := (a - b) / se
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// describeP explains a p-value for the console
+func describeP(p float64) string {
+	if p < 0.05 {
+		return fmt.Sprintf("p = %.3f, significant at 5%%", p)
+	}
+	return fmt.Sprintf("p = %.3f, could be chance", p)
+}
+
+// PrintSignificance prints each model's score with a 95% confidence interval
+// and, for each pair of models, whether the difference between them is
+// significant: by their head-to-head record if they met, otherwise by their
+// overall scores
+func PrintSignificance(w io.Writer, stats *GameStats) {
+	names := playedModels(stats)
+	if len(names) < 2 {
+		return
+	}
+
+	records := map[string]WLD{}
+	fmt.Fprintln(w, "Significance (scores count a draw as half a win; 95% confidence intervals):")
+	for _, name := range names {
+		m := stats.Models[name]
+		// A game a player couldn't finish is forfeited to the other
+		r := m.MovingFirst
+		r.add(m.MovingSecond)
+		records[name] = r
+		low, high := WilsonInterval(r.Score(), r.Games())
+		fmt.Fprintf(w, "  %-24s %5.1f%% [%5.1f%%, %5.1f%%] over %d games\n", shortName(name, 24), r.Score()*100, low*100, high*100, r.Games())
+	}
+	for i, a := range names {
+		for _, b := range names[i+1:] {
+			if h, met := stats.HeadToHead[[2]string{a, b}]; met {
+				r := h.AsX
+				r.add(h.AsO)
+				fmt.Fprintf(w, "  %s vs %s: %s head to head, %.1f%% for %s (%s)\n", a, b, r, r.Score()*100, a, describeP(EvenMatchTest(r)))
+				continue
+			}
+			diff := (records[a].Score() - records[b].Score()) * 100
+			fmt.Fprintf(w, "  %s vs %s: %+.1f points overall (%s)\n", a, b, diff, describeP(CompareScores(records[a], records[b])))
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
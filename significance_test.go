@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		score     float64
+		n         int
+		low, high float64
+	}{
+		{"half of 10", 0.5, 10, 0.2366, 0.7634},
+		{"none of 10", 0, 10, 0, 0.2775},
+		{"all of 10", 1, 10, 0.7225, 1},
+		{"8 of 100", 0.08, 100, 0.0411, 0.1500},
+		{"no trials", 0, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high := WilsonInterval(tt.score, tt.n)
+			if math.Abs(low-tt.low) > 0.0005 || math.Abs(high-tt.high) > 0.0005 {
+				t.Errorf("WilsonInterval(%g, %d) = [%.4f, %.4f], want [%.4f, %.4f]", tt.score, tt.n, low, high, tt.low, tt.high)
+			}
+		})
+	}
+}
+
+func TestEvenMatchTest(t *testing.T) {
+	tests := []struct {
+		name   string
+		record WLD
+		p      float64
+	}{
+		{"60 of 100", WLD{Wins: 60, Losses: 40}, 0.0455},
+		{"even", WLD{Wins: 30, Losses: 30, Draws: 40}, 1},
+		{"draws count half", WLD{Wins: 10, Draws: 20}, 0.0679},
+		{"no games", WLD{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p := EvenMatchTest(tt.record); math.Abs(p-tt.p) > 0.0005 {
+				t.Errorf("EvenMatchTest(%v) = %.4f, want %.4f", tt.record, p, tt.p)
+			}
+		})
+	}
+}
+
+func TestCompareScores(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b WLD
+		p    float64
+	}{
+		{"50 vs 60 of 100", WLD{Wins: 50, Losses: 50}, WLD{Wins: 60, Losses: 40}, 0.1552},
+		{"draws count half", WLD{Wins: 10, Losses: 30, Draws: 60}, WLD{Wins: 40, Losses: 60}, 1},
+		{"no games", WLD{}, WLD{Wins: 3}, 1},
+		{"both always win", WLD{Wins: 20}, WLD{Wins: 30}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p := CompareScores(tt.a, tt.b); math.Abs(p-tt.p) > 0.0005 {
+				t.Errorf("CompareScores(%v, %v) = %.4f, want %.4f", tt.a, tt.b, p, tt.p)
+			}
+		})
+	}
+}
+
+func TestCompareRates(t *testing.T) {
+	tests := []struct {
+		name string
+		a    float64
+		na   int
+		b    float64
+		nb   int
+		p    float64
+	}{
+		{"50% vs 60% of 100", 0.5, 100, 0.6, 100, 0.1552},
+		{"20% vs 40% of 100", 0.2, 100, 0.4, 100, 0.0022},
+		{"symmetric", 0.6, 100, 0.5, 100, 0.1552},
+		{"equal", 0.3, 50, 0.3, 80, 1},
+		{"no trials", 0.5, 0, 0.6, 100, 1},
+		{"both always", 1, 20, 1, 30, 1},
+		{"both never", 0, 20, 0, 30, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p := CompareRates(tt.a, tt.na, tt.b, tt.nb); math.Abs(p-tt.p) > 0.0005 {
+				t.Errorf("CompareRates(%g, %d, %g, %d) = %.4f, want %.4f", tt.a, tt.na, tt.b, tt.nb, p, tt.p)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// slackAPI is the Slack Web API method that posts a message as a bot
+const slackAPI = "https://slack.com/api/chat.postMessage"
+
+// Slack announces games as they start and finish, and a tournament's final
+// standings, in a Slack channel, through an incoming webhook or as a bot
+type Slack struct {
+	Webhook string      // incoming webhook URL; messages are posted here when set
+	Token   string      // bot token, for Channel
+	Channel string      // channel ID or name
+	NewGame func() Game // creates the game being played, for final boards
+}
+
+// NewSlack posts through webhook if it's set, and otherwise as the bot with
+// token in channel
+func NewSlack(webhook, channel, token string, newGame func() Game) (*Slack, error) {
+	if webhook != "" {
+		if u, err := url.Parse(webhook); err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("invalid Slack webhook URL %q: must be an https URL", webhook)
+		}
+	} else if token == "" {
+		return nil, fmt.Errorf("-slack-channel needs a bot token: set -slack-token or SLACK_BOT_TOKEN")
+	}
+	return &Slack{Webhook: webhook, Token: token, Channel: channel, NewGame: newGame}, nil
+}
+
+// StartGame announces a game about to be played
+func (s *Slack) StartGame(agents map[string]Agent) {
+	s.post(fmt.Sprintf(":arrow_forward: *%s*: %s", slackEscape(s.NewGame().Name()), slackPlayers(agents)))
+}
+
+// EndGame reports a finished game with its final board
+func (s *Slack) EndGame(result GameResult, agents map[string]Agent) {
+	game := s.NewGame()
+	for _, move := range result.Moves {
+		game.Play(move.Player, move.Position)
+	}
+	s.post(fmt.Sprintf(":checkered_flag: *%s*: %s: *%s* after %d moves in %.1fs\n```\n%s\n```",
+		slackEscape(game.Name()), slackPlayers(agents), describeResult(result), len(result.Moves), result.Duration.Seconds(), compactBoard(game)))
+}
+
+// Tournament reports a finished tournament's standings
+func (s *Slack) Tournament(format string, standings []Standing) {
+	var table strings.Builder
+	fmt.Fprintf(&table, "%-4s %-24s %5s %7s\n", "Rank", "Player", "W-D-L", "Points")
+	for rank, st := range standings {
+		fmt.Fprintf(&table, "%-4d %-24s %5s %7s\n", rank+1, shortName(st.Name, 24),
+			fmt.Sprintf("%d-%d-%d", st.Wins, st.Draws, st.Losses), formatPoints(st.Points))
+	}
+	s.post(fmt.Sprintf(":trophy: *%s %s tournament*: final standings\n```\n%s```", slackEscape(s.NewGame().Name()), format, slackEscape(table.String())))
+}
+
+// slackResponse is the part of a Web API response used here
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// post sends a message, warning if it can't
+func (s *Slack) post(text string) {
+	var err error
+	if s.Webhook != "" {
+		err = callWebhook(s.Webhook, map[string]string{"text": text})
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+		var resp slackResponse
+		err = postJSON(ctx, slackAPI, map[string]string{"Authorization": "Bearer " + s.Token},
+			map[string]string{"channel": s.Channel, "text": text}, &resp)
+		cancel()
+		if err == nil && !resp.OK {
+			err = fmt.Errorf("Slack error %s", resp.Error)
+		}
+	}
+	if err != nil {
+		slog.Warn("couldn't post to Slack", "err", err)
+	}
+}
+
+// slackPlayers names both players, e.g. "llama3.2 (X) vs qwen2.5 (O)"
+func slackPlayers(agents map[string]Agent) string {
+	return slackEscape(fmt.Sprintf("%s (X) vs %s (O)", agents[PlayerX].Name(), agents[PlayerO].Name()))
+}
+
+var slackReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackEscape escapes the characters Slack gives a meaning to in messages
+func slackEscape(text string) string {
+	return slackReplacer.Replace(text)
+}
+
+// compactBoard draws the board a row to a line with a character a cell, "·"
+// for empty ones, for games that can be drawn, and as on the console otherwise
+func compactBoard(game Game) string {
+	board, ok := game.(Drawable)
+	if !ok || board.CellGrid() == nil {
+		return strings.Trim(showBoard(game), "\n")
+	}
+	var lines []string
+	for _, row := range board.CellGrid() {
+		cells := make([]string, len(row))
+		for i, mark := range row {
+			switch mark {
+			case "":
+				cells[i] = " "
+			case Empty:
+				cells[i] = "·"
+			default:
+				cells[i] = mark
+			}
+		}
+		lines = append(lines, strings.Join(cells, " "))
+	}
+	return strings.Join(lines, "\n")
+}
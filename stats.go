@@ -0,0 +1,471 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GameResult describes how a single game ended
+type GameResult struct {
+	Winner        string // "X", "O", "draw", or "error"
+	FailedPlayer  string // player who could not produce a valid move, for "error" results
+	LostOnTime    string // player whose clock ran out, if any
+	FirstPlayer   string // player who moved first, X or O
+	Moves         []Move
+	Attempts      []Attempt     // every answer the agents gave, including rejected ones
+	Usage         Usage         // tokens used by both players
+	Duration      time.Duration // wall-clock time the game took
+	Retries       int           // move attempts after the first, for any reason
+	IllegalMoves  int           // answers naming a move that isn't available
+	ParseFailures int           // answers no move could be read from
+	// Grades rates each move in Moves against perfect play, and is nil if the
+	// game is too large to solve
+	Grades []MoveGrade
+}
+
+// Attempt is one answer an agent gave during a game, whether or not it was
+// played
+type Attempt struct {
+	Player    string
+	Position  int           // move chosen, -1 if none could be read from the answer
+	Response  string        // raw LLM response, if any
+	Duration  time.Duration // time spent waiting on the LLM, if any
+	Error     string        // why no move could be chosen, if any
+	Legal     bool          // whether the move was played
+	Reasoning string        // the model's reasoning behind the move, with -cot or -json
+	Override  string        // why an engine replaced the LLM's choice, if it did
+
+	// For -transcripts: the prompt sent, any further exchanges behind the
+	// answer, and the tokens spent on it
+	Prompt     string
+	Transcript string
+	Usage      Usage
+}
+
+type GameStats struct {
+	XWins             int
+	OWins             int
+	Draws             int
+	Errors            int
+	Total             int
+	TotalResponseTime time.Duration
+	MinResponseTime   time.Duration
+	MaxResponseTime   time.Duration
+	ResponseCount     int
+	Overrides         int // moves where the hybrid agent's engine replaced the LLM's choice
+	Timeouts          int // move attempts abandoned because the LLM took too long
+	Answers           int // answers the agents gave, legal or not, excluding backend failures
+	IllegalMoves      int // answers naming a move that isn't available
+	ParseFailures     int // answers no move could be read from
+	LostOnTime        int // games lost when a player's clock ran out
+	FirstMoverWins    int // games won by the player who moved first, including forfeits
+	SecondMoverWins   int
+	GradedMoves       int              // moves judged against perfect play
+	Grades            [Blunder + 1]int // graded moves by grade
+	Usage             Usage
+	Models            map[string]*ModelStats
+	HeadToHead        map[[2]string]*HeadToHead // by model and opponent
+}
+
+// WLD is a win-loss-draw record
+type WLD struct {
+	Wins, Losses, Draws int
+}
+
+// Games is the number of games in the record
+func (r WLD) Games() int {
+	return r.Wins + r.Losses + r.Draws
+}
+
+// Score is the share of points won, counting draws as half
+func (r WLD) Score() float64 {
+	if r.Games() == 0 {
+		return 0
+	}
+	return (float64(r.Wins) + float64(r.Draws)/2) / float64(r.Games())
+}
+
+// String shows the record as wins-losses-draws, e.g. "3-1-2"
+func (r WLD) String() string {
+	return fmt.Sprintf("%d-%d-%d", r.Wins, r.Losses, r.Draws)
+}
+
+func (r *WLD) add(other WLD) {
+	r.Wins += other.Wins
+	r.Losses += other.Losses
+	r.Draws += other.Draws
+}
+
+// HeadToHead is one model's record against one opponent, split by the side
+// it played. A game a player can't finish counts as a loss for them and a win
+// for their opponent.
+type HeadToHead struct {
+	AsX, AsO WLD
+}
+
+// ModelStats holds results for one agent regardless of which symbol it played
+type ModelStats struct {
+	Games     int
+	Wins      int
+	Losses    int
+	Draws     int
+	Errors    int
+	Overrides int
+	Timeouts  int
+	// Answers counts every answer the agent gave, and IllegalMoves and
+	// ParseFailures those rejected, including any corrected on a retry
+	Answers       int
+	IllegalMoves  int
+	ParseFailures int
+	ResponseTime  time.Duration // total time spent waiting on the agent's LLM
+	ResponseCount int
+	// MovingFirst and MovingSecond split the agent's games by whether it
+	// made the first move, whichever symbol it played
+	MovingFirst  WLD
+	MovingSecond WLD
+	GradedMoves  int              // moves judged against perfect play
+	Grades       [Blunder + 1]int // graded moves by grade
+	// Openings counts the positions the agent chose when it opened a game
+	// from the empty board
+	Openings map[int]int
+	Usage    Usage
+}
+
+// IllegalRate returns the share of the agent's answers that named an unavailable move
+func (m *ModelStats) IllegalRate() float64 {
+	return rate(m.IllegalMoves, m.Answers)
+}
+
+// ParseFailureRate returns the share of the agent's answers no move could be read from
+func (m *ModelStats) ParseFailureRate() float64 {
+	return rate(m.ParseFailures, m.Answers)
+}
+
+// Quality returns the average quality of the agent's graded moves, from 1
+// if every move was optimal down to 0 if every move was a blunder
+func (m *ModelStats) Quality() float64 {
+	if m.GradedMoves == 0 {
+		return 0
+	}
+	total := 0.0
+	for grade, count := range m.Grades {
+		total += MoveGrade(grade).Quality() * float64(count)
+	}
+	return total / float64(m.GradedMoves)
+}
+
+func (m *ModelStats) recordOpening(position, count int) {
+	if m.Openings == nil {
+		m.Openings = make(map[int]int)
+	}
+	m.Openings[position] += count
+}
+
+// rate returns count as a share of total, 0 when total is
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+// AverageResponse returns the agent's mean LLM response time, 0 if it never called one
+func (m *ModelStats) AverageResponse() time.Duration {
+	if m.ResponseCount == 0 {
+		return 0
+	}
+	return m.ResponseTime / time.Duration(m.ResponseCount)
+}
+
+// NewGameStats creates empty statistics
+func NewGameStats() *GameStats {
+	return &GameStats{Models: make(map[string]*ModelStats), HeadToHead: make(map[[2]string]*HeadToHead)}
+}
+
+// Model returns the statistics for the named agent, creating them if needed
+func (s *GameStats) Model(name string) *ModelStats {
+	m, ok := s.Models[name]
+	if !ok {
+		m = &ModelStats{}
+		s.Models[name] = m
+	}
+	return m
+}
+
+// Against returns the named agent's record against opponent, creating it if needed
+func (s *GameStats) Against(name, opponent string) *HeadToHead {
+	h, ok := s.HeadToHead[[2]string{name, opponent}]
+	if !ok {
+		h = &HeadToHead{}
+		s.HeadToHead[[2]string{name, opponent}] = h
+	}
+	return h
+}
+
+// RecordResponse adds one LLM response time of the named agent to the statistics
+func (s *GameStats) RecordResponse(name string, duration time.Duration) {
+	m := s.Model(name)
+	m.ResponseTime += duration
+	m.ResponseCount++
+
+	s.TotalResponseTime += duration
+	s.ResponseCount++
+	if s.MinResponseTime == 0 || duration < s.MinResponseTime {
+		s.MinResponseTime = duration
+	}
+	if duration > s.MaxResponseTime {
+		s.MaxResponseTime = duration
+	}
+}
+
+// Merge adds the response times, usage, and move counts of other, such as
+// the statistics of one game played on its own, to s
+func (s *GameStats) Merge(other *GameStats) {
+	s.XWins += other.XWins
+	s.OWins += other.OWins
+	s.Draws += other.Draws
+	s.Errors += other.Errors
+	s.Total += other.Total
+	s.TotalResponseTime += other.TotalResponseTime
+	if s.MinResponseTime == 0 || (other.MinResponseTime > 0 && other.MinResponseTime < s.MinResponseTime) {
+		s.MinResponseTime = other.MinResponseTime
+	}
+	s.MaxResponseTime = max(s.MaxResponseTime, other.MaxResponseTime)
+	s.ResponseCount += other.ResponseCount
+	s.Overrides += other.Overrides
+	s.Timeouts += other.Timeouts
+	s.Answers += other.Answers
+	s.IllegalMoves += other.IllegalMoves
+	s.ParseFailures += other.ParseFailures
+	s.LostOnTime += other.LostOnTime
+	s.FirstMoverWins += other.FirstMoverWins
+	s.SecondMoverWins += other.SecondMoverWins
+	s.GradedMoves += other.GradedMoves
+	for grade, count := range other.Grades {
+		s.Grades[grade] += count
+	}
+	s.Usage.Add(other.Usage)
+	for name, o := range other.Models {
+		m := s.Model(name)
+		m.Games += o.Games
+		m.Wins += o.Wins
+		m.Losses += o.Losses
+		m.Draws += o.Draws
+		m.Errors += o.Errors
+		m.Overrides += o.Overrides
+		m.Timeouts += o.Timeouts
+		m.Answers += o.Answers
+		m.IllegalMoves += o.IllegalMoves
+		m.ParseFailures += o.ParseFailures
+		m.ResponseTime += o.ResponseTime
+		m.ResponseCount += o.ResponseCount
+		m.MovingFirst.add(o.MovingFirst)
+		m.MovingSecond.add(o.MovingSecond)
+		m.GradedMoves += o.GradedMoves
+		for grade, count := range o.Grades {
+			m.Grades[grade] += count
+		}
+		for position, count := range o.Openings {
+			m.recordOpening(position, count)
+		}
+		m.Usage.Add(o.Usage)
+	}
+	for key, o := range other.HeadToHead {
+		h := s.Against(key[0], key[1])
+		h.AsX.add(o.AsX)
+		h.AsO.add(o.AsO)
+	}
+}
+
+// RecordGame updates the per-symbol and per-model statistics with a finished game
+func (s *GameStats) RecordGame(result GameResult, agents map[string]Agent) {
+	s.Total++
+	switch result.Winner {
+	case PlayerX:
+		s.XWins++
+	case PlayerO:
+		s.OWins++
+	case "draw":
+		s.Draws++
+	case "error":
+		s.Errors++
+	}
+	if result.LostOnTime != "" {
+		s.LostOnTime++
+	}
+	switch winner := gameWinner(result); winner {
+	case "":
+	case result.FirstPlayer:
+		s.FirstMoverWins++
+	default:
+		s.SecondMoverWins++
+	}
+
+	// Move grades are the mover's own, so they count even in self-play
+	for i, grade := range result.Grades {
+		if grade == Ungraded {
+			continue
+		}
+		m := s.Model(agents[result.Moves[i].Player].Name())
+		s.GradedMoves++
+		s.Grades[grade]++
+		m.GradedMoves++
+		m.Grades[grade]++
+	}
+
+	// Self-play would credit the same model with both sides, so skip the rest
+	if agents[PlayerX].Name() == agents[PlayerO].Name() {
+		return
+	}
+	for _, player := range []string{PlayerX, PlayerO} {
+		h := s.Against(agents[player].Name(), agents[OtherPlayer(player)].Name())
+		bySide, byOrder := &h.AsX, &s.Model(agents[player].Name()).MovingFirst
+		if player == PlayerO {
+			bySide = &h.AsO
+		}
+		if player != result.FirstPlayer {
+			byOrder = &s.Model(agents[player].Name()).MovingSecond
+		}
+		for _, record := range []*WLD{bySide, byOrder} {
+			switch gameWinner(result) {
+			case player:
+				record.Wins++
+			case "":
+				record.Draws++
+			default:
+				record.Losses++
+			}
+		}
+
+		m := s.Model(agents[player].Name())
+		m.Games++
+		switch result.Winner {
+		case player:
+			m.Wins++
+		case OtherPlayer(player):
+			m.Losses++
+		case "draw":
+			m.Draws++
+		case "error":
+			if result.FailedPlayer == player {
+				m.Errors++
+			}
+		}
+	}
+}
+
+// gameWinner returns the player who won a game, counting a game a player
+// couldn't finish as forfeited to the other, or "" for a draw
+func gameWinner(result GameResult) string {
+	switch result.Winner {
+	case PlayerX, PlayerO:
+		return result.Winner
+	case "error":
+		return OtherPlayer(result.FailedPlayer)
+	}
+	return ""
+}
+
+// playedModels returns the names of the models that played a game, sorted
+func playedModels(stats *GameStats) []string {
+	var names []string
+	for name, m := range stats.Models {
+		if m.Games > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PrintModelStats prints a per-model results table, if more than one model played
+func PrintModelStats(w io.Writer, stats *GameStats) {
+	if len(stats.Models) < 2 {
+		return
+	}
+	var names []string
+	for name := range stats.Models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "Results by model:\n")
+	fmt.Fprintf(w, "  %-24s %6s %6s %6s %6s %8s %9s %8s %9s %10s\n", "Model", "Wins", "Losses", "Draws", "Errors", "Win %", "Illegal %", "Parse %", "Avg time", "Tokens")
+	for _, name := range names {
+		m := stats.Models[name]
+		winRate := 0.0
+		if m.Games > 0 {
+			winRate = float64(m.Wins) / float64(m.Games) * 100
+		}
+		avgTime := "-"
+		if m.ResponseCount > 0 {
+			avgTime = fmt.Sprintf("%.2fs", m.AverageResponse().Seconds())
+		}
+		fmt.Fprintf(w, "  %-24s %6d %6d %6d %6d %7.1f%% %8.1f%% %7.1f%% %9s %10d\n", name, m.Wins, m.Losses, m.Draws, m.Errors, winRate,
+			m.IllegalRate()*100, m.ParseFailureRate()*100, avgTime, m.Usage.Total())
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
+
+// PrintHeadToHead prints each model's record against each other model it met,
+// as X and as O, if more than one model played
+func PrintHeadToHead(w io.Writer, stats *GameStats) {
+	if len(stats.HeadToHead) == 0 {
+		return
+	}
+	const width, cell = 14, 17
+	var names []string
+	for name := range stats.Models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Head to head (row model's wins-losses-draws against the column model, as X / as O):")
+	fmt.Fprintf(w, "  %-*s", width+4, "")
+	for i := range names {
+		fmt.Fprintf(w, " %*d", cell, i+1)
+	}
+	fmt.Fprintln(w)
+	for i, name := range names {
+		fmt.Fprintf(w, "  %2d. %-*s", i+1, width, shortName(name, width))
+		for j, opponent := range names {
+			h, met := stats.HeadToHead[[2]string{name, opponent}]
+			switch {
+			case i == j:
+				fmt.Fprintf(w, " %*s", cell, "-")
+			case !met:
+				fmt.Fprintf(w, " %*s", cell, "·")
+			default:
+				fmt.Fprintf(w, " %*s", cell, h.AsX.String()+" / "+h.AsO.String())
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
+
+// PrintFirstMove prints how often the player moving first won, and for each
+// model how it scored moving first and second, whichever symbol it played
+func PrintFirstMove(w io.Writer, stats *GameStats) {
+	if stats.Total == 0 {
+		return
+	}
+	fmt.Fprintln(w, "First-move advantage:")
+	fmt.Fprintf(w, "  First mover won:  %d (%.1f%%)\n", stats.FirstMoverWins, float64(stats.FirstMoverWins)/float64(stats.Total)*100)
+	fmt.Fprintf(w, "  Second mover won: %d (%.1f%%)\n", stats.SecondMoverWins, float64(stats.SecondMoverWins)/float64(stats.Total)*100)
+	if names := playedModels(stats); len(names) >= 2 {
+		fmt.Fprintf(w, "  %-24s %16s %16s %10s\n", "Model (W-L-D)", "Moving first", "Moving second", "Advantage")
+		for _, name := range names {
+			m := stats.Models[name]
+			advantage := "-"
+			if m.MovingFirst.Games() > 0 && m.MovingSecond.Games() > 0 {
+				advantage = fmt.Sprintf("%+.1f%%", (m.MovingFirst.Score()-m.MovingSecond.Score())*100)
+			}
+			fmt.Fprintf(w, "  %-24s %16s %16s %10s\n", shortName(name, 24), m.MovingFirst.String(), m.MovingSecond.String(), advantage)
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+}
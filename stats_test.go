@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeMatchesSequentialRun(t *testing.T) {
+	a, b, c := namedAgent("llama3.2"), namedAgent("qwen2.5"), namedAgent("minimax")
+	games := []struct {
+		x, o   Agent
+		result GameResult
+	}{
+		{a, b, GameResult{Winner: PlayerX, FirstPlayer: PlayerX, Grades: []MoveGrade{Optimal, Blunder}, Moves: []Move{{Player: PlayerX}, {Player: PlayerO}}}},
+		{b, a, GameResult{Winner: "draw", FirstPlayer: PlayerO}},
+		{a, c, GameResult{Winner: PlayerO, FirstPlayer: PlayerX, LostOnTime: PlayerX}},
+		{c, a, GameResult{Winner: "error", FailedPlayer: PlayerO, FirstPlayer: PlayerO}},
+		{a, b, GameResult{Winner: PlayerO, FirstPlayer: PlayerO, Usage: Usage{PromptTokens: 120, CompletionTokens: 8}}},
+		{a, a, GameResult{Winner: PlayerX, FirstPlayer: PlayerX}},
+		{b, c, GameResult{Winner: "draw", FirstPlayer: PlayerX}},
+	}
+
+	sequential := NewGameStats()
+	workers := []*GameStats{NewGameStats(), NewGameStats(), NewGameStats()}
+	for i, game := range games {
+		agents := map[string]Agent{PlayerX: game.x, PlayerO: game.o}
+		response := time.Duration(i+1) * 100 * time.Millisecond
+		for _, stats := range []*GameStats{sequential, workers[i%len(workers)]} {
+			stats.RecordGame(game.result, agents)
+			stats.RecordResponse(game.x.Name(), response)
+		}
+	}
+	merged := NewGameStats()
+	for _, stats := range workers {
+		merged.Merge(stats)
+	}
+
+	if !reflect.DeepEqual(merged, sequential) {
+		t.Errorf("merged statistics differ from a sequential run:\nmerged:     %+v\nsequential: %+v", merged, sequential)
+	}
+	if h := merged.HeadToHead[[2]string{"llama3.2", "qwen2.5"}]; h == nil || h.AsX != (WLD{Wins: 1, Losses: 1}) || h.AsO != (WLD{Draws: 1}) {
+		t.Errorf("llama3.2 vs qwen2.5 = %+v, want 1-1-0 as X and 0-0-1 as O", h)
+	}
+	llama := merged.Model("llama3.2")
+	if llama.MovingFirst != (WLD{Wins: 1, Losses: 2, Draws: 1}) || llama.MovingSecond != (WLD{Losses: 1}) {
+		t.Errorf("llama3.2 moving first = %v and second = %v, want 1-2-1 and 0-1-0", llama.MovingFirst, llama.MovingSecond)
+	}
+}
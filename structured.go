@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MoveSchema is the JSON schema for a structured move, limited to the currently available positions
+func MoveSchema(available []int) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"position": map[string]any{
+				"type": "integer",
+				"enum": available,
+			},
+			"reasoning": map[string]any{
+				"type": "string",
+			},
+		},
+		"required":             []string{"position", "reasoning"},
+		"additionalProperties": false,
+	}
+}
+
+// AddMarkProperty adds a required "mark" property to a move schema or tool
+// parameters, for games where players choose which mark to place
+func AddMarkProperty(schema map[string]any, marks []string) {
+	schema["properties"].(map[string]any)["mark"] = map[string]any{
+		"type": "string",
+		"enum": marks,
+	}
+	schema["required"] = append(schema["required"].([]string), "mark")
+}
+
+// openAIResponseFormat asks an OpenAI-style API for output matching a JSON schema
+type openAIResponseFormat struct {
+	Type       string `json:"type"`
+	JSONSchema struct {
+		Name   string         `json:"name"`
+		Strict bool           `json:"strict"`
+		Schema map[string]any `json:"schema"`
+	} `json:"json_schema"`
+}
+
+// newOpenAIResponseFormat wraps a schema for response_format, or returns nil when there is none
+func newOpenAIResponseFormat(schema map[string]any) *openAIResponseFormat {
+	if schema == nil {
+		return nil
+	}
+	format := &openAIResponseFormat{Type: "json_schema"}
+	format.JSONSchema.Name = "move"
+	format.JSONSchema.Strict = true
+	format.JSONSchema.Schema = schema
+	return format
+}
+
+// MoveGrammar is a GBNF grammar that only accepts one of the available positions,
+// so a grammar-constrained decoder cannot produce an illegal or unparseable move
+func MoveGrammar(available []int) string {
+	alternatives := make([]string, len(available))
+	for i, position := range available {
+		alternatives[i] = fmt.Sprintf("%q", strconv.Itoa(position))
+	}
+	return "root ::= " + strings.Join(alternatives, " | ")
+}
+
+const (
+	legalDigitBias   = 5    // nudges generation toward the available positions
+	illegalDigitBias = -100 // all but rules out taken positions
+)
+
+// MoveLogitBias biases the digit tokens so that only available positions are
+// likely to be generated. Unlike a grammar, other text is still possible.
+// Positions of several digits favour every digit they use.
+func MoveLogitBias(available []int) map[string]float64 {
+	bias := make(map[string]float64)
+	for digit := 0; digit <= 9; digit++ {
+		bias[strconv.Itoa(digit)] = illegalDigitBias
+	}
+	for _, position := range available {
+		for _, digit := range strconv.Itoa(position) {
+			bias[string(digit)] = legalDigitBias
+		}
+	}
+	return bias
+}
+
+// openAILogitBias converts a text-keyed bias to OpenAI token IDs. OpenAI's
+// tokenizers (GPT-2 through o200k_base) all give the digits 0-9 the IDs 15-24;
+// other text has no fixed ID and is dropped.
+func openAILogitBias(bias map[string]float64) map[string]float64 {
+	if len(bias) == 0 {
+		return nil
+	}
+	ids := make(map[string]float64)
+	for text, value := range bias {
+		if len(text) == 1 && text[0] >= '0' && text[0] <= '9' {
+			ids[strconv.Itoa(15+int(text[0]-'0'))] = value
+		}
+	}
+	return ids
+}
+
+// ParseJSONMark reads the "mark" field of a JSON move, if it has one
+func ParseJSONMark(response string) string {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+	var move struct {
+		Mark string `json:"mark"`
+	}
+	json.Unmarshal([]byte(response[start:end+1]), &move)
+	return strings.ToUpper(strings.TrimSpace(move.Mark))
+}
+
+// ParseJSONMove reads a {"position": n, "reasoning": "..."} object from the response.
+// Text around the object, such as a markdown code fence, is ignored.
+func ParseJSONMove(response string) (int, string, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return -1, "", fmt.Errorf("no JSON object found in response: %s", strings.TrimSpace(response))
+	}
+
+	var move struct {
+		Position  *int   `json:"position"`
+		Reasoning string `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &move); err != nil {
+		return -1, "", fmt.Errorf("invalid JSON move: %w", err)
+	}
+	if move.Position == nil {
+		return -1, move.Reasoning, fmt.Errorf("JSON move has no position")
+	}
+	return *move.Position, move.Reasoning, nil
+}
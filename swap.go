@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// SwapMove is the move number of a swap under the pie rule. It is negative so
+// it can't clash with any game's own move numbers.
+const SwapMove = -2
+
+// SwapGame adds the pie rule to a game: after the opening move, the second
+// player may swap instead of moving, taking that move over as their own so
+// the first player moves again. Whoever opens then has a reason not to pick
+// too strong a move.
+type SwapGame struct {
+	Game
+	start   Game // the position before the opening move, for replaying it
+	opening Move
+	moves   int
+	swapped bool
+}
+
+// NewSwapGame wraps game, in its starting position, with the pie rule
+func NewSwapGame(game Game) *SwapGame {
+	return &SwapGame{Game: game, start: game.Clone()}
+}
+
+func (s *SwapGame) Name() string {
+	return s.Game.Name() + " with the swap rule"
+}
+
+func (s *SwapGame) Clone() Game {
+	clone := *s
+	clone.Game = s.Game.Clone()
+	return &clone
+}
+
+// canSwap reports whether the next move may be a swap
+func (s *SwapGame) canSwap() bool {
+	return s.moves == 1 && s.Game.Winner() == ""
+}
+
+func (s *SwapGame) Legal() []int {
+	legal := s.Game.Legal()
+	if !s.canSwap() {
+		return legal
+	}
+	return append([]int{SwapMove}, legal...)
+}
+
+// Play replays the opening move for player on a swap
+func (s *SwapGame) Play(player string, position int) bool {
+	if position == SwapMove {
+		if !s.canSwap() || player == s.opening.Player {
+			return false
+		}
+		s.Game = s.start.Clone()
+		s.Game.Play(player, s.opening.Position)
+		s.swapped = true
+		s.moves++
+		return true
+	}
+	if !s.Game.Play(player, position) {
+		return false
+	}
+	if s.moves == 0 {
+		s.opening = Move{Player: player, Position: position}
+	}
+	s.moves++
+	return true
+}
+
+// DisplayColor highlights the opening move again once a swap takes it over
+func (s *SwapGame) DisplayColor(w io.Writer, last int) {
+	if last == SwapMove {
+		last = s.opening.Position
+	}
+	displayColor(w, s.Game, last)
+}
+
+// CellGrid draws the game underneath, or returns nil if it can't be drawn
+func (s *SwapGame) CellGrid() [][]string {
+	if board, ok := s.Game.(Drawable); ok {
+		return board.CellGrid()
+	}
+	return nil
+}
+
+func (s *SwapGame) Describe(position int) string {
+	if position == SwapMove {
+		return "swap (taking over the opening move)"
+	}
+	return s.Game.Describe(position)
+}
+
+func (s *SwapGame) Rules(player string) string {
+	return s.Game.Rules(player) + ` Under the swap rule, the second player may answer "swap" instead of their first move, ` +
+		"to take over the opening move as their own; the first player then moves again."
+}
+
+// playedHistory rewrites a swap as the swapping player having made the
+// opening move, which is how the wrapped game sees it
+func (s *SwapGame) playedHistory(moveHistory []Move) []Move {
+	if len(moveHistory) < 2 || moveHistory[1].Position != SwapMove {
+		return moveHistory
+	}
+	history := []Move{{Player: moveHistory[1].Player, Position: moveHistory[0].Position, Mark: moveHistory[0].Mark}}
+	return append(history, moveHistory[2:]...)
+}
+
+// notes explains a swap that was made, or the option to make one
+func (s *SwapGame) notes() string {
+	var notes strings.Builder
+	if s.swapped {
+		notes.WriteString(fmt.Sprintf("\nNote: player %s used the swap rule, taking over %s's opening move as their own.\n",
+			OtherPlayer(s.opening.Player), s.opening.Player))
+	}
+	if s.canSwap() {
+		notes.WriteString(fmt.Sprintf("\n🔄 SWAP RULE: %s opened with %s. Instead of a normal move you may SWAP: the opening move becomes YOURS "+
+			"and %s moves next. Swap if the opening move is better than any reply you have.\n",
+			s.opening.Player, s.Game.Describe(s.opening.Position), s.opening.Player))
+	}
+	return notes.String()
+}
+
+func (s *SwapGame) Context(player string, moveHistory []Move) string {
+	return s.Game.Context(player, s.playedHistory(moveHistory)) + s.notes()
+}
+
+func (s *SwapGame) Prompt(player string, moveHistory []Move) string {
+	prompt := s.Game.Prompt(player, s.playedHistory(moveHistory)) + s.notes()
+	if s.canSwap() {
+		prompt += `Instead of a move, you may respond with the single word "swap".` + "\n"
+	}
+	return prompt
+}
+
+var swapAnswer = regexp.MustCompile(`(?i)\bswap\b`)
+
+// ParseMove reads "swap" while a swap is allowed, otherwise the game's own move
+func (s *SwapGame) ParseMove(response string) (int, error) {
+	if s.canSwap() && swapAnswer.MatchString(response) {
+		return SwapMove, nil
+	}
+	return s.Game.ParseMove(response)
+}
+
+func (s *SwapGame) HumanHint() string {
+	hint := "the move"
+	if input, ok := s.Game.(humanInput); ok {
+		hint = input.HumanHint()
+	}
+	if s.canSwap() {
+		hint += ` or "swap"`
+	}
+	return hint
+}
+
+func (s *SwapGame) ParseHumanMove(input string) (int, error) {
+	if s.canSwap() && swapAnswer.MatchString(input) {
+		return SwapMove, nil
+	}
+	if human, ok := s.Game.(humanInput); ok {
+		return human.ParseHumanMove(input)
+	}
+	return s.Game.ParseMove(input)
+}
+
+// SearchDepth is the wrapped game's, so minimax weighs swapping like any move
+func (s *SwapGame) SearchDepth() int {
+	if e, ok := s.Game.(Evaluator); ok {
+		return e.SearchDepth()
+	}
+	return math.MaxInt
+}
+
+func (s *SwapGame) Evaluate(player string) int {
+	if e, ok := s.Game.(Evaluator); ok {
+		return e.Evaluate(player)
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// NewSwissTournament creates a Swiss tournament of rounds rounds. Each round
+// pairs players on similar scores who haven't met yet, and each pair plays
+// Games games with each as X.
+func NewSwissTournament(players []Agent, games, rounds int) *Tournament {
+	t := NewTournament(players, games)
+	t.Format, t.Rounds = FormatSwiss, rounds
+	return t
+}
+
+// DefaultSwissRounds is the usual rule of thumb for a field of players:
+// log2 of its size rounded up, enough rounds to leave one unbeaten player
+func DefaultSwissRounds(players int) int {
+	return max(bits.Len(uint(players-1)), 1)
+}
+
+// runSwiss plays the Swiss rounds. With an odd number of players, the
+// lowest-ranked player who hasn't had one gets a bye, scored as winning
+// every game of the round.
+func (t *Tournament) runSwiss(play playFunc) {
+	for round := 1; round <= t.Rounds; round++ {
+		pairs, bye := t.swissPairings()
+		fmt.Fprintf(t.out(), "\n========== Swiss round %d of %d ==========\n", round, t.Rounds)
+		for _, pair := range pairs {
+			fmt.Fprintf(t.out(), "  %s (%s) vs %s (%s)\n", t.Players[pair.X].Name(), formatPoints(t.records[pair.X].Points),
+				t.Players[pair.O].Name(), formatPoints(t.records[pair.O].Points))
+		}
+		if bye >= 0 {
+			t.byes[bye] = true
+			t.records[bye].Points += float64(2 * t.Games)
+			fmt.Fprintf(t.out(), "  %s has a bye, worth %d points\n", t.Players[bye].Name(), 2*t.Games)
+		}
+		for n, pair := range pairs {
+			heading := fmt.Sprintf("Round %d, pairing %d of %d", round, n+1, len(pairs))
+			if play(pair, heading, t.Games) || play(Pairing{X: pair.O, O: pair.X}, heading+", return", t.Games) {
+				return
+			}
+		}
+	}
+}
+
+// swissPairings pairs the players in standings order, each with the
+// highest-placed player below them they haven't met, returning the bye or
+// -1. Rematches are only allowed once no pairing avoids them.
+func (t *Tournament) swissPairings() (pairs []Pairing, bye int) {
+	var order []int
+	for _, s := range t.Standings() {
+		order = append(order, s.seed)
+	}
+	bye = -1
+	if len(order)%2 == 1 {
+		bye = order[len(order)-1]
+		for k := len(order) - 1; k >= 0; k-- {
+			if !t.byes[order[k]] {
+				bye = order[k]
+				break
+			}
+		}
+		for k, player := range order {
+			if player == bye {
+				order = append(order[:k], order[k+1:]...)
+				break
+			}
+		}
+	}
+
+	pairs, ok := pairUp(order, func(a, b int) bool { return !t.met[a][b] })
+	if !ok {
+		pairs, _ = pairUp(order, func(a, b int) bool { return true })
+	}
+	return pairs, bye
+}
+
+// pairUp pairs players in order, backtracking when the top player's only
+// allowed opponents are needed further down
+func pairUp(players []int, allowed func(a, b int) bool) ([]Pairing, bool) {
+	if len(players) == 0 {
+		return nil, true
+	}
+	first := players[0]
+	for k := 1; k < len(players); k++ {
+		if !allowed(first, players[k]) {
+			continue
+		}
+		rest := append(append([]int(nil), players[1:k]...), players[k+1:]...)
+		if pairs, ok := pairUp(rest, allowed); ok {
+			return append([]Pairing{{X: first, O: players[k]}}, pairs...), true
+		}
+	}
+	return nil, false
+}
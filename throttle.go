@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Throttle limits requests and tokens per minute and tracks estimated spend.
+// One Throttle is shared by every player so that limits and budgets apply to
+// the run as a whole, as they do on a cloud account.
+type Throttle struct {
+	RequestsPerMinute int     // 0 for no limit
+	TokensPerMinute   int     // 0 for no limit
+	InputPrice        float64 // dollars per million prompt tokens
+	OutputPrice       float64 // dollars per million completion tokens
+	MaxCost           float64 // dollars; 0 for no budget
+
+	mu     sync.Mutex
+	window []*throttleEntry // requests sent in the last minute
+	spent  float64
+}
+
+type throttleEntry struct {
+	at     time.Time
+	tokens int
+}
+
+// ThrottledBackend sends requests through a shared Throttle
+type ThrottledBackend struct {
+	Backend  Backend
+	Throttle *Throttle
+}
+
+// Generate waits until the rate limits allow the request, then sends it
+func (t *ThrottledBackend) Generate(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	promptTokens := estimateTokens(req.System + req.Prompt)
+	for _, msg := range req.Messages {
+		promptTokens += estimateTokens(msg.Content)
+	}
+	entry, err := t.Throttle.wait(ctx, promptTokens)
+	if err != nil {
+		return LLMResponse{}, err
+	}
+	resp, err := t.Backend.Generate(ctx, req)
+	if err == nil {
+		completionTokens := estimateTokens(resp.Text)
+		// Prefer the backend's own counts when it reports them
+		if resp.Usage.Total() > 0 {
+			promptTokens, completionTokens = resp.Usage.PromptTokens, resp.Usage.CompletionTokens
+		}
+		t.Throttle.record(entry, promptTokens, completionTokens)
+	}
+	return resp, err
+}
+
+// Endpoints returns the number of endpoints behind the throttled backend
+func (t *ThrottledBackend) Endpoints() int {
+	return endpointCount(t.Backend)
+}
+
+// ListModels lists the wrapped backend's models, when it can
+func (t *ThrottledBackend) ListModels(ctx context.Context) ([]string, error) {
+	lister, ok := t.Backend.(ModelLister)
+	if !ok {
+		return nil, errCannotListModels
+	}
+	return lister.ListModels(ctx)
+}
+
+// PullModel pulls on the wrapped backend, when it can
+func (t *ThrottledBackend) PullModel(ctx context.Context, model string, progress func(status string, completed, total int64)) error {
+	puller, ok := t.Backend.(ModelPuller)
+	if !ok {
+		return fmt.Errorf("backend cannot pull models")
+	}
+	return puller.PullModel(ctx, model, progress)
+}
+
+// wait blocks until a request of the given size fits within the per-minute
+// limits, returning its entry in the window
+func (t *Throttle) wait(ctx context.Context, tokens int) (*throttleEntry, error) {
+	for {
+		entry, delay := t.reserve(tokens)
+		if entry != nil {
+			return entry, nil
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// reserve records the request and returns its entry if it fits in the
+// current window, or how long to wait before trying again
+func (t *Throttle) reserve(tokens int) (*throttleEntry, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for len(t.window) > 0 && now.Sub(t.window[0].at) >= time.Minute {
+		t.window = t.window[1:]
+	}
+	used := 0
+	for _, e := range t.window {
+		used += e.tokens
+	}
+
+	overRequests := t.RequestsPerMinute > 0 && len(t.window) >= t.RequestsPerMinute
+	// A single request larger than the whole limit is let through on an
+	// empty window rather than blocking forever
+	overTokens := t.TokensPerMinute > 0 && len(t.window) > 0 && used+tokens > t.TokensPerMinute
+	if overRequests || overTokens {
+		return nil, t.window[0].at.Add(time.Minute).Sub(now)
+	}
+	entry := &throttleEntry{at: now, tokens: tokens}
+	t.window = append(t.window, entry)
+	return entry, 0
+}
+
+// record corrects a finished request's token count in its entry from
+// reserve, which other requests may have followed into the window, and adds
+// its cost to the spend
+func (t *Throttle) record(entry *throttleEntry, promptTokens, completionTokens int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry.tokens = promptTokens + completionTokens
+	t.spent += (float64(promptTokens)*t.InputPrice + float64(completionTokens)*t.OutputPrice) / 1e6
+}
+
+// Spent returns the estimated spend so far in dollars
+func (t *Throttle) Spent() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.spent
+}
+
+// OverBudget reports whether the estimated spend has reached -max-cost
+func (t *Throttle) OverBudget() bool {
+	return t.MaxCost > 0 && t.Spent() >= t.MaxCost
+}
+
+// Describe summarizes the configured limits for the run header
+func (t *Throttle) Describe() string {
+	var limits []string
+	if t.RequestsPerMinute > 0 {
+		limits = append(limits, fmt.Sprintf("%d requests/min", t.RequestsPerMinute))
+	}
+	if t.TokensPerMinute > 0 {
+		limits = append(limits, fmt.Sprintf("%d tokens/min", t.TokensPerMinute))
+	}
+	if t.MaxCost > 0 {
+		limits = append(limits, fmt.Sprintf("budget $%.2f", t.MaxCost))
+	}
+	return strings.Join(limits, ", ")
+}
+
+// estimateTokens approximates a token count at four characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottleRequestsPerMinute(t *testing.T) {
+	throttle := &Throttle{RequestsPerMinute: 2}
+	first, _ := throttle.reserve(10)
+	second, _ := throttle.reserve(10)
+	if first == nil || second == nil {
+		t.Fatal("reserve held back a request within the limit")
+	}
+	if entry, delay := throttle.reserve(10); entry != nil || delay <= 0 || delay > time.Minute {
+		t.Errorf("third request's delay = %s, want up to a minute", delay)
+	}
+}
+
+func TestThrottleTokensPerMinute(t *testing.T) {
+	throttle := &Throttle{TokensPerMinute: 1000}
+	entry, _ := throttle.reserve(500)
+	if entry == nil {
+		t.Fatal("reserve held back 500 of 1000 tokens")
+	}
+	throttle.record(entry, 500, 100)
+	// 600 of the 1000 tokens are used, so 400 more fit and 401 don't
+	if entry, _ := throttle.reserve(401); entry != nil {
+		t.Error("reserve let 401 tokens through with 600 of 1000 used")
+	}
+	if entry, _ := throttle.reserve(400); entry == nil {
+		t.Error("reserve held back 400 tokens with 600 of 1000 used")
+	}
+
+	// A request over the whole limit goes through on an empty window
+	if entry, _ := (&Throttle{TokensPerMinute: 1000}).reserve(5000); entry == nil {
+		t.Error("reserve held back an oversized request forever")
+	}
+}
+
+func TestThrottleBudget(t *testing.T) {
+	throttle := &Throttle{InputPrice: 1, OutputPrice: 2, MaxCost: 0.001}
+	entry, _ := throttle.reserve(300)
+	throttle.record(entry, 300, 100)
+	if want := (300*1.0 + 100*2.0) / 1e6; throttle.Spent() != want {
+		t.Errorf("Spent() = %g, want %g", throttle.Spent(), want)
+	}
+	if throttle.OverBudget() {
+		t.Error("over budget after spending half of it")
+	}
+	entry, _ = throttle.reserve(500)
+	throttle.record(entry, 500, 250)
+	if !throttle.OverBudget() {
+		t.Errorf("not over a $%g budget after spending $%g", throttle.MaxCost, throttle.Spent())
+	}
+}
+
+func TestThrottleRecordsOverlappingRequests(t *testing.T) {
+	throttle := &Throttle{TokensPerMinute: 1000, InputPrice: 1, OutputPrice: 2}
+	first, _ := throttle.reserve(100)
+	second, _ := throttle.reserve(200)
+	if first == nil || second == nil {
+		t.Fatal("reserve refused a request within the limits")
+	}
+
+	// The first request finishes after the second was sent
+	throttle.record(first, 150, 50)
+	throttle.record(second, 300, 100)
+
+	if first.tokens != 200 || second.tokens != 400 {
+		t.Errorf("window tokens = %d, %d, want 200, 400", first.tokens, second.tokens)
+	}
+	if want := (450*1.0 + 150*2.0) / 1e6; throttle.Spent() != want {
+		t.Errorf("Spent() = %g, want %g", throttle.Spent(), want)
+	}
+	// 600 of the 1000 tokens are used, so 400 more fit and 401 don't
+	if entry, _ := throttle.reserve(401); entry != nil {
+		t.Error("reserve let 401 tokens through with 600 of 1000 used")
+	}
+	if entry, _ := throttle.reserve(400); entry == nil {
+		t.Error("reserve refused 400 tokens with 600 of 1000 used")
+	}
+}
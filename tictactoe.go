@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+func init() {
+	RegisterGame("tictactoe", func() Game { return NewTicTacToe() })
+}
+
+// TicTacToe is the classic 3x3 game, with positions 0-8 numbered left to
+// right and top to bottom
+type TicTacToe struct {
+	Board  Board
+	Misere bool // whoever completes three in a row loses
+	Wild   bool // players may place either mark; see EncodeMove for the move numbers
+	last   string
+}
+
+// NewTicTacToe creates a game on an empty board
+func NewTicTacToe() *TicTacToe {
+	return &TicTacToe{Board: InitBoard()}
+}
+
+// Standard reports whether the game is played under the classic rules
+func (t *TicTacToe) Standard() bool {
+	return !t.Misere && !t.Wild
+}
+
+func (t *TicTacToe) Name() string {
+	if t.Wild {
+		return "Wild Tic-Tac-Toe"
+	}
+	if t.Misere {
+		return "Misère Tic-Tac-Toe"
+	}
+	return "Tic-Tac-Toe"
+}
+
+func (t *TicTacToe) Clone() Game {
+	clone := *t
+	return &clone
+}
+
+func (t *TicTacToe) Display(w io.Writer) { DisplayBoard(w, t.Board) }
+
+// DisplayColor prints the board with the last move and any three in a row
+// highlighted
+func (t *TicTacToe) DisplayColor(w io.Writer, last int) {
+	if t.Wild {
+		last, _ = t.DecodeMove(last)
+	}
+	var line [3]int
+	completed := false
+	for _, combo := range ticTacToeLines {
+		first := t.Board[combo[0]/3][combo[0]%3]
+		if first != Empty && t.Board[combo[1]/3][combo[1]%3] == first && t.Board[combo[2]/3][combo[2]%3] == first {
+			line, completed = combo, true
+			break
+		}
+	}
+	displayCells(w, func(row, col int) string {
+		position, mark := row*3+col, t.Board[row][col]
+		return colorCell(mark, mark, position == last, completed && slices.Contains(line[:], position))
+	})
+}
+
+func (t *TicTacToe) Dimensions() (rows, cols int) { return 3, 3 }
+
+func (t *TicTacToe) CellGrid() [][]string { return boardGrid(t.Board) }
+
+func (t *TicTacToe) Legal() []int {
+	available := AvailablePositions(t.Board)
+	if !t.Wild {
+		return available
+	}
+	var moves []int
+	for _, mark := range t.Marks() {
+		for _, pos := range available {
+			moves = append(moves, t.EncodeMove(pos, mark))
+		}
+	}
+	return moves
+}
+
+func (t *TicTacToe) Play(player string, position int) bool {
+	mark := player
+	if t.Wild {
+		position, mark = t.DecodeMove(position)
+	}
+	if position < 0 || position > 8 || mark == "" {
+		return false
+	}
+	if !MakeMove(&t.Board, mark, position/3, position%3) {
+		return false
+	}
+	t.last = player
+	return true
+}
+
+func (t *TicTacToe) Winner() string {
+	if winner := CheckWinner(t.Board); winner != "" {
+		if t.Wild {
+			// The line may be of either mark; whoever completed it wins
+			return t.last
+		}
+		if t.Misere {
+			return OtherPlayer(winner)
+		}
+		return winner
+	}
+	if IsBoardFull(t.Board) {
+		return "draw"
+	}
+	return ""
+}
+
+func (t *TicTacToe) Describe(position int) string {
+	if t.Wild {
+		pos, mark := t.DecodeMove(position)
+		return fmt.Sprintf("%s at position %d", orDefault(mark, "?"), pos)
+	}
+	return fmt.Sprintf("position %d", position)
+}
+
+func (t *TicTacToe) Rules(player string) string {
+	if t.Wild {
+		return fmt.Sprintf("You are playing Wild Tic-Tac-Toe as player %s: on each turn you may place either an X or an O, "+
+			"and whoever completes three in a row of either mark wins. Positions are numbered 0-8, left to right and top to bottom. "+
+			"Each turn, respond with the mark and the position, e.g. O4.", player)
+	}
+	if t.Misere {
+		return fmt.Sprintf("You are playing Misère Tic-Tac-Toe as player %s: whoever completes three in a row LOSES. "+
+			"Positions are numbered 0-8, left to right and top to bottom. Each turn, respond with a single position number.", player)
+	}
+	return fmt.Sprintf("You are playing Tic-Tac-Toe as player %s. Positions are numbered 0-8, "+
+		"left to right and top to bottom. Each turn, respond with a single position number.", player)
+}
+
+func (t *TicTacToe) Context(player string, moveHistory []Move) string {
+	if t.Wild {
+		return BuildWildContext(t, player, moveHistory)
+	}
+	if t.Misere {
+		return BuildMisereContext(t.Board, player, moveHistory)
+	}
+	return BuildGameContext(t.Board, player, moveHistory)
+}
+
+func (t *TicTacToe) Prompt(player string, moveHistory []Move) string {
+	if t.Wild {
+		return t.Context(player, moveHistory) + WildAnswerInstructions(t.Board)
+	}
+	return t.Context(player, moveHistory) + AnswerInstructions(t.Board)
+}
+
+func (t *TicTacToe) ParseMove(response string) (int, error) {
+	if t.Wild {
+		return ParseWildMove(response)
+	}
+	return ParseMove(response)
+}
+
+// HumanHint describes the accepted console input
+func (t *TicTacToe) HumanHint() string {
+	if t.Wild {
+		return `mark and position, e.g. "O 4"`
+	}
+	return `position 0-8 or "row col"`
+}
+
+// ParseHumanMove accepts a position or a row and column
+func (t *TicTacToe) ParseHumanMove(input string) (int, error) {
+	if t.Wild {
+		return ParseWildMove(input)
+	}
+	row, col, err := ParseHumanMove(input)
+	if err != nil {
+		return -1, err
+	}
+	return row*3 + col, nil
+}
+
+// LoadPosition reads the nine cells left to right and top to bottom. Wild
+// marks don't belong to either player, so a wild position never says whose
+// turn it is.
+func (t *TicTacToe) LoadPosition(board string) (string, error) {
+	cells, err := parseCells(board, 9)
+	if err != nil {
+		return "", err
+	}
+	for i, cell := range cells {
+		t.Board[i/3][i%3] = cell
+	}
+	if CheckWinner(t.Board) != "" {
+		return "", fmt.Errorf("start position already has three in a row")
+	}
+	if t.Wild {
+		return "", nil
+	}
+	return playerToMove(cells)
+}
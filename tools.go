@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ToolDefinition describes a function the model may call, with its parameters as a JSON schema
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+}
+
+// ToolCall is a function call requested by the model, in the OpenAI/Ollama wire format
+type ToolCall struct {
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string        `json:"name"`
+		Arguments ToolArguments `json:"arguments"`
+	} `json:"function"`
+}
+
+// ToolArguments holds a tool call's arguments as a JSON object. OpenAI sends
+// them as a JSON-encoded string while Ollama sends the object itself; both
+// decode to the same thing.
+type ToolArguments json.RawMessage
+
+func (t *ToolArguments) UnmarshalJSON(data []byte) error {
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err == nil {
+		*t = ToolArguments(encoded)
+		return nil
+	}
+	*t = append((*t)[:0], data...)
+	return nil
+}
+
+func (t ToolArguments) MarshalJSON() ([]byte, error) {
+	if len(t) == 0 {
+		return []byte("{}"), nil
+	}
+	return json.RawMessage(t).MarshalJSON()
+}
+
+// openAITool is the tool definition shape shared by OpenAI and Ollama
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	} `json:"function"`
+}
+
+// openAITools converts tool definitions to the OpenAI/Ollama request format
+func openAITools(tools []ToolDefinition) []openAITool {
+	var out []openAITool
+	for _, tool := range tools {
+		var t openAITool
+		t.Type = "function"
+		t.Function.Name = tool.Name
+		t.Function.Description = tool.Description
+		t.Function.Parameters = tool.Parameters
+		out = append(out, t)
+	}
+	return out
+}
+
+// MakeMoveTool defines the make_move tool, limited to the currently available positions
+func MakeMoveTool(available []int) ToolDefinition {
+	return ToolDefinition{
+		Name:        "make_move",
+		Description: "Make your move at the given position",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"position": map[string]any{
+					"type":        "integer",
+					"description": "Position to play, from the available positions",
+					"enum":        available,
+				},
+			},
+			"required": []string{"position"},
+		},
+	}
+}
+
+// PositionFromToolCalls reads the position argument of the first make_move call
+func PositionFromToolCalls(calls []ToolCall) (int, bool, error) {
+	for _, call := range calls {
+		if call.Function.Name != "make_move" {
+			continue
+		}
+		var args struct {
+			Position json.RawMessage `json:"position"`
+		}
+		if err := json.Unmarshal(call.Function.Arguments, &args); err != nil {
+			return -1, true, fmt.Errorf("invalid make_move arguments %s: %w", string(call.Function.Arguments), err)
+		}
+		// Some models send the number as a string
+		var position int
+		if err := json.Unmarshal(args.Position, &position); err != nil {
+			var text string
+			if json.Unmarshal(args.Position, &text) != nil {
+				return -1, true, fmt.Errorf("invalid make_move position %s", string(args.Position))
+			}
+			if position, err = strconv.Atoi(text); err != nil {
+				return -1, true, fmt.Errorf("invalid make_move position %q", text)
+			}
+		}
+		return position, true, nil
+	}
+	return -1, false, nil
+}
+
+// MarkFromToolCalls reads the mark argument of the first make_move call, if any
+func MarkFromToolCalls(calls []ToolCall) string {
+	for _, call := range calls {
+		if call.Function.Name != "make_move" {
+			continue
+		}
+		var args struct {
+			Mark string `json:"mark"`
+		}
+		json.Unmarshal(call.Function.Arguments, &args)
+		return strings.ToUpper(strings.TrimSpace(args.Mark))
+	}
+	return ""
+}
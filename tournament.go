@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Pairing is one scheduled match of a tournament, by player index
+type Pairing struct {
+	X, O int
+}
+
+// Tournament formats
+const (
+	FormatRoundRobin        = "round-robin"
+	FormatSwiss             = "swiss"
+	FormatKnockout          = "knockout"
+	FormatDoubleElimination = "double-elimination"
+)
+
+// TournamentFormats lists the formats for the -format flag
+var TournamentFormats = []string{FormatRoundRobin, FormatSwiss, FormatKnockout, FormatDoubleElimination}
+
+// Tournament is by default a round-robin where every pair of players meets
+// twice, once with each playing X, for Games games each time; swiss.go and
+// bracket.go add the other formats. Results are scored like chess: a point
+// for a win, half a point for a draw, and a game a player couldn't finish
+// forfeited to the opponent.
+type Tournament struct {
+	Players []Agent
+	Games   int
+	Format  string
+	Rounds  int // Swiss rounds
+	// BeforeGame, if set, is called as every game starts, e.g. to announce it
+	BeforeGame func(agents map[string]Agent)
+	// AfterGame, if set, is called with every finished game, e.g. to
+	// record statistics and ratings
+	AfterGame func(result GameResult, agents map[string]Agent)
+	// Out is where games print their moves, os.Stdout if nil
+	Out io.Writer
+
+	points  [][]float64 // points[i][j] is what player i scored against player j
+	met     [][]bool    // met[i][j] is whether players i and j have played
+	byes    []bool      // byes[i] is whether player i has had a Swiss bye
+	bracket *Bracket    // for the elimination formats
+	records []Standing
+}
+
+// Standing is one player's tournament record
+type Standing struct {
+	Name     string  `json:"name"`
+	Games    int     `json:"games"`
+	Wins     int     `json:"wins"`
+	Draws    int     `json:"draws"`
+	Losses   int     `json:"losses"`
+	Points   float64 `json:"points"`
+	Buchholz float64 `json:"buchholz,omitempty"` // opponents' points, the Swiss tiebreak
+
+	seed int // position in the -models list
+}
+
+// NewTournament creates a round-robin between players, Games games per pairing and color
+func NewTournament(players []Agent, games int) *Tournament {
+	t := &Tournament{Players: players, Games: games, Format: FormatRoundRobin, byes: make([]bool, len(players))}
+	for i, player := range players {
+		t.points = append(t.points, make([]float64, len(players)))
+		t.met = append(t.met, make([]bool, len(players)))
+		t.records = append(t.records, Standing{Name: player.Name(), seed: i})
+	}
+	return t
+}
+
+// Schedule returns every pairing both ways, spread out so that no player
+// sits out for long: the first legs of all pairings come before the returns
+func (t *Tournament) Schedule() []Pairing {
+	var first, second []Pairing
+	for i := range t.Players {
+		for j := i + 1; j < len(t.Players); j++ {
+			first = append(first, Pairing{X: i, O: j})
+			second = append(second, Pairing{X: j, O: i})
+		}
+	}
+	return append(first, second...)
+}
+
+// playFunc plays games games of a pairing, reporting whether the run must stop
+type playFunc func(pairing Pairing, heading string, games int) (stopped bool)
+
+// Run plays the tournament, giving each pairing Games games so the first
+// move alternates within it. stop is asked after every game whether the run
+// must end early, e.g. because the budget is spent.
+func (t *Tournament) Run(newGame func() Game, opening Opening, clock *Clock, maxRetries int, debug bool, stats *GameStats, stop func() bool) {
+	play := func(pairing Pairing, heading string, games int) (stopped bool) {
+		agents := map[string]Agent{PlayerX: t.Players[pairing.X], PlayerO: t.Players[pairing.O]}
+		fmt.Fprintf(t.out(), "\n##### %s: %s (X) vs %s (O) #####\n", heading, agents[PlayerX].Name(), agents[PlayerO].Name())
+		for game := 1; game <= games; game++ {
+			if t.BeforeGame != nil {
+				t.BeforeGame(agents)
+			}
+			result := PlayGame(t.out(), newGame(), agents, opening, clock, maxRetries, debug, game, stats)
+			t.Record(pairing, result)
+			if t.AfterGame != nil {
+				t.AfterGame(result, agents)
+			}
+			if stop() {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch t.Format {
+	case FormatSwiss:
+		t.runSwiss(play)
+	case FormatKnockout, FormatDoubleElimination:
+		t.runBracket(play)
+	default:
+		schedule := t.Schedule()
+		for n, pairing := range schedule {
+			if play(pairing, fmt.Sprintf("Pairing %d of %d", n+1, len(schedule)), t.Games) {
+				return
+			}
+		}
+	}
+}
+
+func (t *Tournament) out() io.Writer {
+	if t.Out == nil {
+		return os.Stdout
+	}
+	return t.Out
+}
+
+// Record scores a finished game between the players of a pairing
+func (t *Tournament) Record(p Pairing, result GameResult) {
+	winner := result.Winner
+	if winner == "error" {
+		winner = OtherPlayer(result.FailedPlayer)
+	}
+	t.met[p.X][p.O], t.met[p.O][p.X] = true, true
+	x, o := &t.records[p.X], &t.records[p.O]
+	x.Games++
+	o.Games++
+	switch winner {
+	case PlayerX:
+		x.Wins++
+		o.Losses++
+		x.Points++
+		t.points[p.X][p.O]++
+	case PlayerO:
+		o.Wins++
+		x.Losses++
+		o.Points++
+		t.points[p.O][p.X]++
+	default:
+		x.Draws++
+		o.Draws++
+		x.Points += 0.5
+		o.Points += 0.5
+		t.points[p.X][p.O] += 0.5
+		t.points[p.O][p.X] += 0.5
+	}
+}
+
+// Standings returns the players ranked by points, then Buchholz in a Swiss
+// tournament, then wins, then seeding
+func (t *Tournament) Standings() []Standing {
+	standings := append([]Standing(nil), t.records...)
+	for i := range standings {
+		for j, met := range t.met[i] {
+			if met {
+				standings[i].Buchholz += t.records[j].Points
+			}
+		}
+	}
+	sort.SliceStable(standings, func(a, b int) bool {
+		sa, sb := standings[a], standings[b]
+		if sa.Points != sb.Points {
+			return sa.Points > sb.Points
+		}
+		if t.Format == FormatSwiss && sa.Buchholz != sb.Buchholz {
+			return sa.Buchholz > sb.Buchholz
+		}
+		if sa.Wins != sb.Wins {
+			return sa.Wins > sb.Wins
+		}
+		return sa.seed < sb.seed
+	})
+	return standings
+}
+
+// shortName fits a player's name into a table column
+func shortName(name string, width int) string {
+	runes := []rune(name)
+	if len(runes) <= width {
+		return name
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// PrintCrossTable prints the points each player (rows) scored against each other (columns)
+func (t *Tournament) PrintCrossTable(w io.Writer) {
+	const width = 14
+	fmt.Fprintln(w, "Cross table (points scored by the row player against the column player):")
+	fmt.Fprintf(w, "  %-*s", width+4, "")
+	for i := range t.Players {
+		fmt.Fprintf(w, " %6d", i+1)
+	}
+	fmt.Fprintln(w)
+	for i, player := range t.Players {
+		fmt.Fprintf(w, "  %2d. %-*s", i+1, width, shortName(player.Name(), width))
+		for j := range t.Players {
+			if i == j {
+				fmt.Fprintf(w, " %6s", "-")
+				continue
+			}
+			if !t.met[i][j] {
+				fmt.Fprintf(w, " %6s", "·")
+				continue
+			}
+			fmt.Fprintf(w, " %6s", formatPoints(t.points[i][j]))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// PrintStandings prints the final standings table, with the Buchholz
+// tiebreak for a Swiss tournament
+func (t *Tournament) PrintStandings(w io.Writer) {
+	fmt.Fprintln(w, "Standings:")
+	header := fmt.Sprintf("  %-4s %-24s %5s %5s %5s %5s %7s", "Rank", "Player", "Games", "W", "D", "L", "Points")
+	if t.Format == FormatSwiss {
+		header += fmt.Sprintf(" %8s", "Buchholz")
+	}
+	fmt.Fprintln(w, header)
+	for rank, s := range t.Standings() {
+		line := fmt.Sprintf("  %-4d %-24s %5d %5d %5d %5d %7s", rank+1, shortName(s.Name, 24), s.Games, s.Wins, s.Draws, s.Losses, formatPoints(s.Points))
+		if t.Format == FormatSwiss {
+			line += fmt.Sprintf(" %8s", formatPoints(s.Buchholz))
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// Report prints the results of the tournament: the bracket for the
+// elimination formats, standings and a cross table otherwise
+func (t *Tournament) Report(w io.Writer) {
+	if t.bracket != nil {
+		t.PrintBracket(w)
+		return
+	}
+	t.PrintStandings(w)
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+	t.PrintCrossTable(w)
+}
+
+// parseModels splits a comma-separated -models list
+func parseModels(list string) []string {
+	var models []string
+	for _, model := range strings.Split(list, ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, model)
+		}
+	}
+	return models
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TranscriptWriter writes a text transcript of every finished game to Dir,
+// game-0001.txt for the run's first game and so on: every prompt sent, every
+// raw answer with its timing and tokens, and what became of it, for working
+// out why a model played the way it did
+type TranscriptWriter struct {
+	Dir     string
+	NewGame func() Game // creates the game the moves were played in
+	games   int
+}
+
+// NewTranscriptWriter writes transcripts to dir, creating it if needed
+func NewTranscriptWriter(dir string, newGame func() Game) (*TranscriptWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &TranscriptWriter{Dir: dir, NewGame: newGame}, nil
+}
+
+// Record writes a finished game's transcript; sides gives each player's backend
+func (t *TranscriptWriter) Record(result GameResult, agents map[string]Agent, sides map[string]string) error {
+	t.games++
+	path := filepath.Join(t.Dir, fmt.Sprintf("game-%04d.txt", t.games))
+	return os.WriteFile(path, []byte(t.transcript(t.games, result, agents, sides)), 0o644)
+}
+
+func (t *TranscriptWriter) transcript(number int, result GameResult, agents map[string]Agent, sides map[string]string) string {
+	game := t.NewGame()
+	var text strings.Builder
+	player := func(symbol string) string {
+		return fmt.Sprintf("%s (%s)", agents[symbol].Name(), symbol)
+	}
+	fmt.Fprintf(&text, "%s game %d: %s vs %s\n", game.Name(), number, player(PlayerX), player(PlayerO))
+	fmt.Fprintf(&text, "Backends: %s for X, %s for O; %s moved first\n", sides[PlayerX], sides[PlayerO], result.FirstPlayer)
+	fmt.Fprintf(&text, "Result: %s after %d moves in %.1fs\n", describeResult(result), len(result.Moves), result.Duration.Seconds())
+	fmt.Fprintf(&text, "Retries: %d, illegal moves: %d, unreadable answers: %d, tokens: %d prompt, %d completion\n",
+		result.Retries, result.IllegalMoves, result.ParseFailures, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+
+	// Attempts that were played match the moves after any random opening
+	played := 0
+	for _, a := range result.Attempts {
+		if a.Legal {
+			played++
+		}
+	}
+	opened := len(result.Moves) - played
+	for i, move := range result.Moves[:opened] {
+		fmt.Fprintf(&text, "\n########## Move %d: Player %s ##########\n", i+1, move.Player)
+		fmt.Fprintf(&text, "Random opening move: %s\n", game.Describe(move.Position))
+	}
+
+	moveNumber, try := opened+1, 0
+	for i, a := range result.Attempts {
+		if try == 0 {
+			heading := fmt.Sprintf("Move %d", moveNumber)
+			if moveNumber > len(result.Moves) {
+				heading = "Unfinished move"
+			}
+			fmt.Fprintf(&text, "\n########## %s: Player %s (%s) ##########\n", heading, a.Player, agents[a.Player].Name())
+		}
+		try++
+		fmt.Fprintf(&text, "\n---------- Attempt %d (%.2fs", try, a.Duration.Seconds())
+		if a.Usage.Total() > 0 {
+			fmt.Fprintf(&text, ", %d prompt + %d completion tokens", a.Usage.PromptTokens, a.Usage.CompletionTokens)
+		}
+		fmt.Fprintln(&text, ") ----------")
+		if a.Prompt != "" {
+			fmt.Fprintf(&text, "========== PROMPT ==========\n%s\n", strings.TrimRight(a.Prompt, "\n"))
+		}
+		if a.Response != "" {
+			fmt.Fprintf(&text, "========== RESPONSE ==========\n%s\n", strings.TrimRight(a.Response, "\n"))
+		}
+		if a.Transcript != "" {
+			fmt.Fprintf(&text, "========== TRANSCRIPT ==========\n%s\n", strings.TrimRight(a.Transcript, "\n"))
+		}
+		switch {
+		case i == len(result.Attempts)-1 && result.LostOnTime != "":
+			fmt.Fprintln(&text, "Outcome: ran out of time")
+		case a.Error != "":
+			fmt.Fprintf(&text, "Outcome: %s\n", a.Error)
+		case a.Legal:
+			fmt.Fprintf(&text, "Outcome: played %s\n", game.Describe(a.Position))
+		default:
+			fmt.Fprintf(&text, "Outcome: illegal move, %s\n", game.Describe(a.Position))
+		}
+		if a.Legal {
+			moveNumber, try = moveNumber+1, 0
+		}
+	}
+	return text.String()
+}
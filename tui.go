@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// errAbandoned is returned by RunTUI when the user quit in the middle of a game
+var errAbandoned = errors.New("run abandoned")
+
+// TUI is the full-screen terminal interface of -tui. It shows the board,
+// the moves played, the latest prompt and answer, the run's statistics, and
+// whatever the run prints side by side, and lets the user pause the game,
+// step through it a move at a time, and switch to the full prompt.
+type TUI struct {
+	program *tea.Program
+
+	// mu guards the pause state, which the game waits on before every move
+	mu        sync.Mutex
+	resume    *sync.Cond
+	paused    bool
+	steps     int // moves allowed while paused
+	stopping  bool
+	abandoned bool
+	game      int // number of the game being played
+}
+
+// Messages sent to the interface by the run
+type (
+	tuiEventMsg string // a line printed by the run, or an answer given
+	tuiStatsMsg string // the statistics panel
+	tuiDoneMsg  struct{}
+	tuiGameMsg  struct {
+		title, board, status string
+		moves                []string
+	}
+	tuiPromptMsg struct {
+		player, model, prompt, response, transcript string
+	}
+)
+
+// RunTUI shows the interface until the user quits it, while run plays the
+// games in the background. Everything printed to stdout in the meantime is
+// shown in the interface instead. It returns errAbandoned if the user quit
+// before run finished.
+func RunTUI(title string, debug bool, run func(ui *TUI)) error {
+	ui := &TUI{}
+	ui.resume = sync.NewCond(&ui.mu)
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	ui.program = tea.NewProgram(tuiModel{ui: ui, title: title, debug: debug}, tea.WithAltScreen(), tea.WithOutput(stdout))
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				ui.program.Send(tuiEventMsg(line))
+			}
+		}
+	}()
+	go func() {
+		run(ui)
+		ui.program.Send(tuiDoneMsg{})
+	}()
+	_, err = ui.program.Run()
+	w.Close()
+	if err != nil {
+		return err
+	}
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	if ui.abandoned {
+		return errAbandoned
+	}
+	return nil
+}
+
+// Agents wraps agents so the interface follows their moves and can hold
+// them back while paused
+func (ui *TUI) Agents(agents map[string]Agent) map[string]Agent {
+	wrapped := make(map[string]Agent, len(agents))
+	for player, agent := range agents {
+		wrapped[player] = &tuiAgent{Agent: agent, ui: ui}
+	}
+	return wrapped
+}
+
+// StartGame notes the number of the game about to be played
+func (ui *TUI) StartGame(number int) {
+	ui.mu.Lock()
+	ui.game = number
+	ui.mu.Unlock()
+}
+
+// EndGame shows a finished game's final position
+func (ui *TUI) EndGame(game Game, result GameResult) {
+	ui.mu.Lock()
+	number := ui.game
+	ui.mu.Unlock()
+	ui.program.Send(positionMsg(game, number, result.Moves, describeResult(result)))
+}
+
+// ShowStats replaces the statistics panel
+func (ui *TUI) ShowStats(panel string) {
+	ui.program.Send(tuiStatsMsg(panel))
+}
+
+// Stopping reports whether the user asked to stop after the current game
+func (ui *TUI) Stopping() bool {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return ui.stopping
+}
+
+// wait holds the game back while paused, until the user resumes or steps
+func (ui *TUI) wait() {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	for ui.paused && ui.steps == 0 && !ui.stopping {
+		ui.resume.Wait()
+	}
+	if ui.steps > 0 {
+		ui.steps--
+	}
+}
+
+func (ui *TUI) update(change func()) {
+	ui.mu.Lock()
+	change()
+	ui.mu.Unlock()
+	ui.resume.Broadcast()
+}
+
+// positionMsg renders a game's position for the interface
+func positionMsg(game Game, number int, history []Move, status string) tuiGameMsg {
+	moves := describeMoves(game, history)
+	for i := range moves {
+		moves[i] = fmt.Sprintf("%2d. %s", i+1, moves[i])
+	}
+	return tuiGameMsg{title: fmt.Sprintf("Game %d: %s", number, game.Name()), board: showBoard(game), status: status, moves: moves}
+}
+
+// tuiAgent reports an agent's moves to the interface
+type tuiAgent struct {
+	Agent
+	ui *TUI
+}
+
+func (a *tuiAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	a.ui.mu.Lock()
+	number := a.ui.game
+	a.ui.mu.Unlock()
+	a.ui.program.Send(positionMsg(game, number, moveHistory, fmt.Sprintf("Player %s (%s) to move", player, a.Name())))
+	a.ui.wait()
+
+	result, err := a.Agent.ChooseMove(game, player, moveHistory)
+	if result.Prompt != "" {
+		a.ui.program.Send(tuiPromptMsg{player: player, model: a.Name(), prompt: result.Prompt, response: result.Response, transcript: result.Transcript})
+	}
+	switch {
+	case err != nil:
+		a.ui.program.Send(tuiEventMsg(fmt.Sprintf("%s (%s): %v", player, a.Name(), err)))
+	case result.Response != "":
+		a.ui.program.Send(tuiEventMsg(fmt.Sprintf("%s (%s) answered %q: %s (%.2fs)", player, a.Name(),
+			strings.Join(strings.Fields(result.Response), " "), game.Describe(result.Position), result.Duration.Seconds())))
+	}
+	return result, err
+}
+
+// tuiModel is the interface's Bubble Tea model
+type tuiModel struct {
+	ui            *TUI
+	title         string
+	width, height int
+	game          tuiGameMsg
+	prompt        tuiPromptMsg
+	stats         string
+	events        []string
+	debug         bool
+	done          bool
+}
+
+// tuiEvents is how many events the interface keeps
+const tuiEvents = 200
+
+func (m tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tuiGameMsg:
+		m.game = msg
+	case tuiPromptMsg:
+		m.prompt = msg
+	case tuiStatsMsg:
+		m.stats = string(msg)
+	case tuiEventMsg:
+		m.events = append(m.events, string(msg))
+		if len(m.events) > tuiEvents {
+			m.events = m.events[len(m.events)-tuiEvents:]
+		}
+	case tuiDoneMsg:
+		m.done = true
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			if m.done {
+				return m, tea.Quit
+			}
+			quit := false
+			m.ui.update(func() {
+				if m.ui.stopping {
+					m.ui.abandoned, quit = true, true
+				}
+				m.ui.stopping = true
+			})
+			if quit {
+				return m, tea.Quit
+			}
+			m.events = append(m.events, "Stopping after this game; press q again to quit now")
+		case "p", " ":
+			m.ui.update(func() { m.ui.paused = !m.ui.paused })
+		case "n", "s":
+			m.ui.update(func() {
+				if m.ui.paused {
+					m.ui.steps++
+				}
+			})
+		case "d":
+			m.debug = !m.debug
+		}
+	}
+	return m, nil
+}
+
+var (
+	tuiBorder = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	tuiTitle  = lipgloss.NewStyle().Bold(true)
+	tuiFaint  = lipgloss.NewStyle().Faint(true)
+)
+
+func (m tuiModel) View() string {
+	if m.width == 0 {
+		return "Starting..."
+	}
+	const boardWidth, statsWidth, eventLines = 32, 64, 6
+	// Each box adds a border and padding around its text
+	inner := func(width int) int { return max(width-4, 1) }
+	topHeight := max(m.height-eventLines-2-1, 8) - 2
+	promptWidth := max(m.width-boardWidth-statsWidth, 20)
+
+	left := box(m.game.title, m.game.board+"\n"+m.game.status+"\n\n"+strings.Join(m.game.moves, "\n"), inner(boardWidth), topHeight)
+	promptTitle := "Prompt (d for full)"
+	var prompt string
+	if m.prompt.prompt != "" {
+		promptTitle = fmt.Sprintf("Prompt to %s (%s), d for full", m.prompt.model, m.prompt.player)
+		text := m.prompt.prompt
+		if !m.debug {
+			// The end of the prompt holds the position and the question
+			lines := strings.Split(strings.TrimSpace(text), "\n")
+			text = strings.Join(lines[max(len(lines)-8, 0):], "\n")
+		} else {
+			promptTitle = fmt.Sprintf("Full prompt to %s (%s), d for snippet", m.prompt.model, m.prompt.player)
+		}
+		prompt = text + "\n\nAnswer: " + strings.TrimSpace(m.prompt.response)
+		if m.debug && m.prompt.transcript != "" {
+			prompt += "\n\n" + m.prompt.transcript
+		}
+	}
+	middle := box(promptTitle, prompt, inner(promptWidth), topHeight)
+	right := box("Statistics", m.stats, inner(statsWidth), topHeight)
+	events := box("Log", strings.Join(m.events, "\n"), inner(m.width), eventLines)
+
+	state := "running"
+	m.ui.mu.Lock()
+	switch {
+	case m.done:
+		state = "finished; press q for the final statistics"
+	case m.ui.stopping:
+		state = "stopping after this game"
+	case m.ui.paused:
+		state = "paused"
+	}
+	m.ui.mu.Unlock()
+	footer := tuiFaint.Render(fmt.Sprintf(" %s: %s   [p] pause/resume  [n] step  [d] full prompt  [q] quit", m.title, state))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lipgloss.JoinHorizontal(lipgloss.Top, left, middle, right), events, footer)
+}
+
+// box draws text in a bordered box with a title, keeping its last lines if
+// it has more than fit
+func box(title, text string, width, height int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "…")
+	}
+	if len(lines) > height-1 {
+		lines = lines[len(lines)-(height-1):]
+	}
+	body := tuiTitle.Render(ansi.Truncate(title, width, "…")) + "\n" + strings.Join(lines, "\n")
+	return tuiBorder.Width(width + 2).Height(height).Render(body)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// twitchIRC is the address of Twitch chat's IRC interface
+const twitchIRC = "irc.chat.twitch.tv:6697"
+
+// TwitchChat is a connection to a Twitch channel's chat. Without a token it
+// logs in anonymously, which is enough to read votes; with one it can also
+// post to the chat as Nick.
+type TwitchChat struct {
+	Channel string
+	Nick    string
+	conn    net.Conn
+	mu      sync.Mutex      // guards writes to conn
+	chat    chan twitchLine // messages as they arrive
+}
+
+// twitchLine is a chat message
+type twitchLine struct {
+	User, Text string
+}
+
+// ConnectTwitch joins channel's chat, as nick with the OAuth token if it's
+// set and anonymously otherwise
+func ConnectTwitch(channel, nick, token string) (*TwitchChat, error) {
+	channel = strings.ToLower(strings.TrimPrefix(channel, "#"))
+	if token == "" {
+		nick = fmt.Sprintf("justinfan%d", rand.N(90000)+10000)
+	} else if nick == "" {
+		return nil, fmt.Errorf("a Twitch token needs -twitch-nick, the account it belongs to")
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", twitchIRC, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't connect to Twitch chat: %w", err)
+	}
+	c := &TwitchChat{Channel: channel, Nick: nick, conn: conn, chat: make(chan twitchLine, 1000)}
+	if token != "" {
+		c.send("PASS oauth:" + strings.TrimPrefix(token, "oauth:"))
+	}
+	c.send("NICK " + strings.ToLower(nick))
+	c.send("JOIN #" + channel)
+	go c.read()
+	return c, nil
+}
+
+func (c *TwitchChat) send(line string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fprintf(c.conn, "%s\r\n", line)
+	return err
+}
+
+// read answers the server's pings and passes chat messages on until the
+// connection closes
+func (c *TwitchChat) read() {
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "PING") {
+			c.send("PONG" + strings.TrimPrefix(line, "PING"))
+			continue
+		}
+		// :nick!nick@nick.tmi.twitch.tv PRIVMSG #channel :message
+		prefix, rest, ok := strings.Cut(strings.TrimPrefix(line, ":"), " PRIVMSG ")
+		if !ok {
+			if strings.Contains(line, "Login authentication failed") {
+				slog.Warn("Twitch chat rejected the login; check -twitch-nick and -twitch-token")
+			}
+			continue
+		}
+		_, text, _ := strings.Cut(rest, " :")
+		user, _, _ := strings.Cut(prefix, "!")
+		select {
+		case c.chat <- twitchLine{User: user, Text: text}:
+		default:
+			// Nobody is counting votes right now and the buffer is full
+		}
+	}
+	slog.Warn("disconnected from Twitch chat", "channel", c.Channel, "err", scanner.Err())
+	close(c.chat)
+}
+
+// Say posts to the chat, if logged in with a token
+func (c *TwitchChat) Say(text string) {
+	if strings.HasPrefix(c.Nick, "justinfan") {
+		return
+	}
+	if err := c.send("PRIVMSG #" + c.Channel + " :" + text); err != nil {
+		slog.Warn("couldn't post to Twitch chat", "err", err)
+	}
+}
+
+// TwitchAgent lets a Twitch channel's chat vote on its moves: for Window
+// after it's asked, each viewer's latest "!move" with a legal move counts as
+// their vote, and the move with the most votes is played, the one voted for
+// first on a tie. A window without valid votes is followed by another.
+type TwitchAgent struct {
+	Chat   *TwitchChat
+	Window time.Duration
+}
+
+// Name identifies the chat's players
+func (a *TwitchAgent) Name() string {
+	return "twitch"
+}
+
+// ChooseMove runs a vote and returns the winning move
+func (a *TwitchAgent) ChooseMove(game Game, player string, moveHistory []Move) (MoveResult, error) {
+	parse, hint := game.ParseMove, "move"
+	if input, ok := game.(humanInput); ok {
+		parse, hint = input.ParseHumanMove, input.HumanHint()
+	}
+	// Only votes cast from now on count
+	for len(a.Chat.chat) > 0 {
+		<-a.Chat.chat
+	}
+	for {
+		a.Chat.Say(fmt.Sprintf("Vote for %s's move: type !move and a %s in the next %s", player, hint, a.Window))
+		slog.Info(fmt.Sprintf("Twitch chat is voting for %s's move for %s", player, a.Window))
+		votes := map[string]int{} // each viewer's vote
+		var order []int           // moves in the order they were first voted for
+		deadline := time.After(a.Window)
+	collect:
+		for {
+			select {
+			case line, ok := <-a.Chat.chat:
+				if !ok {
+					return MoveResult{Position: -1}, fmt.Errorf("disconnected from Twitch chat")
+				}
+				input, isVote := strings.CutPrefix(strings.TrimSpace(line.Text), "!move")
+				if !isVote {
+					continue
+				}
+				position, err := parse(input)
+				if err != nil || !containsPosition(game.Legal(), position) {
+					continue
+				}
+				if !containsPosition(order, position) {
+					order = append(order, position)
+				}
+				votes[line.User] = position
+			case <-deadline:
+				break collect
+			}
+		}
+
+		tally := map[int]int{}
+		for _, position := range votes {
+			tally[position]++
+		}
+		if len(tally) == 0 {
+			slog.Info("No valid votes from Twitch chat; voting again")
+			continue
+		}
+		winner := -1
+		for _, position := range order {
+			if winner == -1 || tally[position] > tally[winner] {
+				winner = position
+			}
+		}
+		a.Chat.Say(fmt.Sprintf("Chat plays %s with %d of %d votes", game.Describe(winner), tally[winner], len(votes)))
+		slog.Info(fmt.Sprintf("Twitch chat plays %s with %d of %d votes", game.Describe(winner), tally[winner], len(votes)))
+		return MoveResult{Position: winner}, nil
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ServeWeb serves a page showing live's game as it's played at / on addr,
+// e.g. ":8080", in the background, and a transparent one for OBS browser
+// sources at /overlay. The pages poll the snapshot at /state; /events
+// streams it as server-sent events every time it changes.
+func ServeWeb(addr string, live *Live) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("can't serve the web page: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, webPage)
+	})
+	mux.HandleFunc("/overlay", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, overlayPage)
+	})
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		state, _ := live.State()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(state)
+	})
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		streamEvents(w, r, live)
+	})
+	go http.Serve(listener, mux)
+	return nil
+}
+
+// sseKeepAlive is how often an idle event stream gets a comment, so proxies
+// don't close it
+const sseKeepAlive = 15 * time.Second
+
+// streamEvents sends live's snapshots as server-sent events, as JSON like
+// /state: the latest one, then every one after it until the client goes
+// away. Each is named for what changed: "game" when a game starts, "move"
+// when one is played, "result" when the game ends, and "state" otherwise,
+// e.g. when a player starts or stops thinking.
+func streamEvents(w http.ResponseWriter, r *http.Request, live *Live) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	flusher := http.NewResponseController(w)
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	last, changed := live.State()
+	states := []LiveState{last}
+	last.Version = -1
+	for {
+		for _, state := range states {
+			data, err := json.Marshal(state)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", liveEvent(last, state), state.Version, data)
+			last = state
+		}
+		if len(states) == 0 {
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		if err := flusher.Flush(); err != nil {
+			return
+		}
+		states = nil
+		select {
+		case <-changed:
+			states, changed = live.Since(last.Version)
+		case <-keepAlive.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// liveEvent names the change from one snapshot to the next
+func liveEvent(previous, state LiveState) string {
+	switch {
+	case state.Game != previous.Game:
+		return "game"
+	case state.Result != "" && previous.Result == "":
+		return "result"
+	case len(state.Moves) > len(previous.Moves):
+		return "move"
+	}
+	return "state"
+}
+
+// webPage renders the live state, fetching it twice a second
+const webPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>llama-tac-toe</title>
+<style>
+  body { font-family: system-ui, sans-serif; background: #11151c; color: #e6e6e6; margin: 0; padding: 24px; }
+  h1 { font-size: 1.4em; margin: 0 0 4px; }
+  .sub { color: #9aa4b2; margin-bottom: 20px; }
+  .grid { display: grid; grid-template-columns: auto 1fr; gap: 24px; align-items: start; }
+  .card { background: #1a2029; border-radius: 10px; padding: 16px 20px; }
+  .card h2 { font-size: 0.8em; text-transform: uppercase; letter-spacing: 0.08em; color: #9aa4b2; margin: 0 0 10px; }
+  pre { margin: 0; white-space: pre-wrap; }
+  #board { font-size: 1.8em; line-height: 1.25; }
+  #status { font-size: 1.2em; margin-top: 14px; }
+  .thinking::after { content: " …"; animation: blink 1s infinite; }
+  @keyframes blink { 50% { opacity: 0; } }
+  #moves { margin: 0; padding-left: 1.6em; columns: 2; }
+  #response { font-size: 1.05em; max-height: 40vh; overflow: auto; }
+  details pre { font-size: 0.85em; color: #9aa4b2; max-height: 30vh; overflow: auto; margin-top: 8px; }
+  .score span { margin-right: 18px; }
+</style>
+</head>
+<body>
+<h1 id="title">llama-tac-toe</h1>
+<div class="sub" id="players">Waiting for the first game…</div>
+<div class="grid">
+  <div>
+    <div class="card">
+      <h2 id="game">Board</h2>
+      <pre id="board"></pre>
+      <div id="status"></div>
+    </div>
+    <div class="card" style="margin-top: 24px">
+      <h2>Score</h2>
+      <div class="score" id="score"></div>
+    </div>
+  </div>
+  <div>
+    <div class="card">
+      <h2 id="thoughts">Thoughts</h2>
+      <pre id="response"></pre>
+      <details><summary>Prompt</summary><pre id="prompt"></pre></details>
+    </div>
+    <div class="card" style="margin-top: 24px">
+      <h2>Moves</h2>
+      <ol id="moves"></ol>
+    </div>
+  </div>
+</div>
+<script>
+let version = -1;
+const $ = id => document.getElementById(id);
+async function refresh() {
+  try {
+    const s = await (await fetch("/state")).json();
+    if (s.version === version) return;
+    version = s.version;
+    if (s.game === 0) return;
+    $("title").textContent = s.name;
+    $("players").textContent = (s.players.X || "?") + " (X) vs " + (s.players.O || "?") + " (O)";
+    $("game").textContent = "Game " + s.game;
+    $("board").textContent = s.board;
+    const status = $("status");
+    status.className = s.thinking ? "thinking" : "";
+    status.textContent = s.result ? s.result : s.to_move ? s.to_move + " (" + s.players[s.to_move] + ") to move" : "";
+    $("score").innerHTML = "";
+    for (const [label, key] of [["X wins", "X"], ["O wins", "O"], ["Draws", "draw"], ["Errors", "error"]]) {
+      const span = document.createElement("span");
+      span.textContent = label + ": " + (s.score[key] || 0);
+      $("score").appendChild(span);
+    }
+    $("thoughts").textContent = s.model ? "Thoughts of " + s.model : "Thoughts";
+    $("response").textContent = s.response || "";
+    $("prompt").textContent = s.prompt || "";
+    $("moves").innerHTML = "";
+    for (const move of s.moves || []) {
+      const li = document.createElement("li");
+      li.textContent = move;
+      $("moves").appendChild(li);
+    }
+  } catch (e) {
+    // The run may have ended; keep showing the last state
+  }
+}
+refresh();
+setInterval(refresh, 500);
+</script>
+</body>
+</html>
+`
+
+// overlayPage shows the board, players, status, and score on a transparent
+// background, for laying over a stream
+const overlayPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>llama-tac-toe overlay</title>
+<style>
+  body { background: transparent; color: #fff; margin: 0; padding: 16px; font-family: system-ui, sans-serif;
+         text-shadow: 0 0 4px #000, 0 0 2px #000; }
+  #players { font-size: 1.3em; font-weight: bold; }
+  #board { font-size: 2em; line-height: 1.2; margin: 8px 0; }
+  #status { font-size: 1.2em; }
+  .thinking::after { content: " …"; animation: blink 1s infinite; }
+  @keyframes blink { 50% { opacity: 0; } }
+  #score { margin-top: 6px; color: #ddd; }
+</style>
+</head>
+<body>
+<div id="players"></div>
+<pre id="board"></pre>
+<div id="status"></div>
+<div id="score"></div>
+<script>
+let version = -1;
+const $ = id => document.getElementById(id);
+async function refresh() {
+  try {
+    const s = await (await fetch("/state")).json();
+    if (s.version === version || s.game === 0) return;
+    version = s.version;
+    $("players").textContent = (s.players.X || "?") + " (X) vs " + (s.players.O || "?") + " (O)";
+    $("board").textContent = s.board;
+    const status = $("status");
+    status.className = s.thinking ? "thinking" : "";
+    status.textContent = "Game " + s.game + ": " + (s.result ? s.result :
+      s.to_move ? s.players[s.to_move] + " (" + s.to_move + ")" + (s.thinking ? " is thinking" : " to move") : "");
+    let score = "X " + (s.score.X || 0) + "  O " + (s.score.O || 0) + "  Draws " + (s.score.draw || 0);
+    if (s.score.error) score += "  Errors " + s.score.error;
+    $("score").textContent = score;
+  } catch (e) {
+    // The run may have ended; keep showing the last state
+  }
+}
+refresh();
+setInterval(refresh, 500);
+</script>
+</body>
+</html>
+`
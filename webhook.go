@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webhookTimeout bounds each webhook call, so a slow receiver can't stall a run
+const webhookTimeout = 10 * time.Second
+
+// Webhooks POSTs JSON to every URL when a game ends and when the run
+// finishes, for CI systems and chat without polling: the "game" and
+// "summary" events of -output json, the summary named "tournament" and
+// with the final standings at the end of a tournament
+type Webhooks struct {
+	URLs  []string
+	Mode  string // name of the game being played, e.g. "Connect Four"
+	mu    sync.Mutex
+	games int
+}
+
+// TournamentEvent ends a tournament with its totals and final standings
+type TournamentEvent struct {
+	SummaryEvent
+	Standings []Standing `json:"standings"`
+}
+
+// NewWebhooks calls the comma-separated URLs in list
+func NewWebhooks(list, mode string) (*Webhooks, error) {
+	w := &Webhooks{Mode: mode}
+	for _, raw := range strings.Split(list, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q: must be an http or https URL", raw)
+		}
+		w.URLs = append(w.URLs, raw)
+	}
+	return w, nil
+}
+
+// Game reports a finished game; sides gives each player's backend
+func (w *Webhooks) Game(result GameResult, agents map[string]Agent, sides map[string]string) {
+	w.mu.Lock()
+	w.games++
+	number := w.games
+	w.mu.Unlock()
+	w.post(GameEvent{Event: "game", GameRecord: NewGameRecord(number, w.Mode, result, agents, sides)})
+}
+
+// Summary reports the run's totals
+func (w *Webhooks) Summary(stats *GameStats) {
+	w.post(NewSummaryEvent(stats))
+}
+
+// Tournament reports a finished tournament's totals and standings
+func (w *Webhooks) Tournament(stats *GameStats, standings []Standing) {
+	event := TournamentEvent{SummaryEvent: NewSummaryEvent(stats), Standings: standings}
+	event.Event = "tournament"
+	w.post(event)
+}
+
+// post sends event to every URL, warning about any that fail
+func (w *Webhooks) post(event any) {
+	for _, u := range w.URLs {
+		if err := callWebhook(u, event); err != nil {
+			slog.Warn("couldn't call the webhook", "url", u, "err", err)
+		}
+	}
+}
+
+// callWebhook posts event as JSON to url; receivers answer with any 2xx status
+func callWebhook(url string, event any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := newJSONRequest(ctx, url, map[string]string{"User-Agent": "llama-tac-toe"}, event)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &HTTPError{StatusCode: resp.StatusCode, URL: url, Body: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	RegisterVariant("tictactoe", "wild", func() Game {
+		return &TicTacToe{Board: InitBoard(), Wild: true}
+	})
+}
+
+// Marks returns the marks a wild tic-tac-toe player may place, and none in
+// the other variants, where each player always places their own
+func (t *TicTacToe) Marks() []string {
+	if !t.Wild {
+		return nil
+	}
+	return []string{PlayerX, PlayerO}
+}
+
+func (t *TicTacToe) EncodeMove(position int, mark string) int {
+	return encodeWildMove(position, mark)
+}
+
+func (t *TicTacToe) DecodeMove(move int) (position int, mark string) {
+	return decodeWildMove(move)
+}
+
+// encodeWildMove numbers a wild move: the position for an X, the position
+// plus 10 for an O, so 4 is X at 4 and 14 is O at 4
+func encodeWildMove(position int, mark string) int {
+	if mark == PlayerO {
+		return position + 10
+	}
+	return position
+}
+
+// decodeWildMove splits a wild move number into its position and mark; the
+// mark is empty for numbers that aren't moves
+func decodeWildMove(move int) (position int, mark string) {
+	switch {
+	case move >= 0 && move <= 8:
+		return move, PlayerX
+	case move >= 10 && move <= 18:
+		return move - 10, PlayerO
+	}
+	return move, ""
+}
+
+// BuildWildContext describes a wild tic-tac-toe game, where either player may
+// place either mark and whoever completes a line of either mark wins
+func BuildWildContext(t *TicTacToe, player string, moveHistory []Move) string {
+	var prompt strings.Builder
+	opponent := OtherPlayer(player)
+
+	prompt.WriteString(fmt.Sprintf("You are playing Wild Tic-Tac-Toe as player %s.\n", player))
+	prompt.WriteString("On each turn you may place EITHER an X or an O; the player names are only seat names. " +
+		"Whoever completes three in a row of the same mark wins, no matter who placed the other two marks.\n\n")
+
+	if len(moveHistory) > 0 {
+		prompt.WriteString("Move history:\n")
+		for i, move := range moveHistory {
+			prompt.WriteString(fmt.Sprintf("%d. Player %s placed %s\n", i+1, move.Player, t.Describe(move.Position)))
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString(DescribeBoard(t.Board, nil))
+
+	if promptHints != HintsNone {
+		// A move is safe when it leaves the opponent no completing move of either mark
+		winningMoves := ImmediateWins(t, player)
+		var safeMoves []string
+		for _, move := range t.Legal() {
+			next := t.Clone()
+			next.Play(player, move)
+			if len(ImmediateWins(next, opponent)) == 0 {
+				safeMoves = append(safeMoves, wildNotation(t, move))
+			}
+		}
+
+		prompt.WriteString("\n*** CRITICAL ANALYSIS ***\n")
+		if len(winningMoves) > 0 {
+			prompt.WriteString(fmt.Sprintf("🎯 YOU CAN WIN NOW! Place %s to complete three in a row!\n", t.Describe(winningMoves[0])))
+		} else if len(safeMoves) > 0 {
+			prompt.WriteString("⚠️  Any two matching marks in a line with the third cell empty let the NEXT player win. " +
+				fmt.Sprintf("These moves leave %s no winning move: %s\n", opponent, strings.Join(safeMoves, ", ")))
+		} else {
+			prompt.WriteString(fmt.Sprintf("Every move leaves %s a winning move. Pick the one that gives the fewest chances.\n", opponent))
+		}
+		prompt.WriteString("*** END ANALYSIS ***\n")
+	}
+
+	if promptHints == HintsFull {
+		prompt.WriteString("\nSTRATEGY PRIORITY:\n")
+		prompt.WriteString("1. WIN: Complete any line of three matching marks, X's or O's\n")
+		prompt.WriteString(fmt.Sprintf("2. SAFETY: Never leave two matching marks in a line with an empty third cell for %s to complete\n", opponent))
+		prompt.WriteString("3. STRATEGIC: Place marks that don't match their neighbours, and try to leave your opponent only unsafe moves\n")
+	}
+
+	return prompt.String()
+}
+
+// WildAnswerInstructions tells the LLM to answer with a mark and an available position
+func WildAnswerInstructions(board Board) string {
+	var prompt strings.Builder
+	example := AvailablePositions(board)[0]
+	prompt.WriteString("\n⚠️  CRITICAL INSTRUCTIONS:\n")
+	prompt.WriteString("1. You MUST choose a position ONLY from the AVAILABLE POSITIONS list above\n")
+	prompt.WriteString("2. Choose which mark to place there: X or O\n")
+	prompt.WriteString(fmt.Sprintf("3. ONLY respond with the mark followed by the position, e.g. O%d or X%d\n", example, example))
+	prompt.WriteString("4. Do NOT include any other text, explanation, or formatting\n")
+	return prompt.String()
+}
+
+// wildNotation writes a wild move the way the LLM is asked to answer, e.g. O4
+func wildNotation(t *TicTacToe, move int) string {
+	position, mark := t.DecodeMove(move)
+	return fmt.Sprintf("%s%d", mark, position)
+}
+
+var (
+	wildMarkFirst     = regexp.MustCompile(`(?i)\b([xo])\s*(?:(?:at|on|in|to)\s+)?(?:position\s*)?([0-8])\b`)
+	wildPositionFirst = regexp.MustCompile(`(?i)\b([0-8])\s*(?:(?:with|as)\s+(?:an?\s+)?)?([xo])\b`)
+)
+
+// ParseWildMove extracts a mark and position such as "O4", "X 2", "O at
+// position 4", or "4 O" from the response
+func ParseWildMove(response string) (int, error) {
+	var mark, position string
+	if match := wildMarkFirst.FindStringSubmatch(response); match != nil {
+		mark, position = match[1], match[2]
+	} else if match := wildPositionFirst.FindStringSubmatch(response); match != nil {
+		position, mark = match[1], match[2]
+	} else {
+		return -1, fmt.Errorf("no mark and position found in response: %s", strings.TrimSpace(response))
+	}
+	return encodeWildMove(int(position[0]-'0'), strings.ToUpper(mark)), nil
+}